@@ -9,15 +9,245 @@ CREATE DATABASE course_db;
 -- Создание таблицы пользователей для Auth Service
 CREATE TABLE users (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    email VARCHAR(255) UNIQUE NOT NULL,
+    email VARCHAR(255) NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
-    role VARCHAR(50) NOT NULL CHECK (role IN ('employee', 'manager')),
-    created_at TIMESTAMP DEFAULT NOW()
+    role VARCHAR(50) NOT NULL CHECK (role IN ('employee', 'manager', 'admin')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    email_verified_at TIMESTAMP, -- NULL, пока пользователь не подтвердил email
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'deactivated')),
+    status_reason TEXT, -- причина приостановки или деактивации
+    status_changed_at TIMESTAMP, -- NULL, пока статус не менялся
+    deleted_at TIMESTAMP, -- NULL, пока учетная запись не удалена
+    erased_at TIMESTAMP -- NULL, пока email и пароль не заменены обезличенными значениями
 );
 
 -- Создание индексов для быстрого поиска
-CREATE INDEX idx_users_email ON users(email);
+-- Email уникален только среди неудаленных учетных записей, чтобы его можно было зарегистрировать снова
+CREATE UNIQUE INDEX idx_users_email ON users(email) WHERE deleted_at IS NULL;
 CREATE INDEX idx_users_role ON users(role);
+CREATE INDEX idx_users_status ON users(status);
+CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL AND erased_at IS NULL;
+
+-- Создание таблицы refresh токенов (хранится только SHA-256 хеш токена)
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL, -- цепочка токенов, полученных ротацией от одного логина
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+-- Создание таблицы сессий входа (id совпадает с family_id цепочки refresh токенов и sid в access токене)
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(), -- последнее обновление токенов
+    expires_at TIMESTAMP NOT NULL, -- истечение последнего refresh токена
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+-- Создание списка отозванных access токенов (по jti)
+CREATE TABLE revoked_tokens (
+    jti UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL, -- после истечения токена запись можно удалить
+    revoked_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Отзыв всех токенов пользователя, выпущенных до revoked_before ("выйти на всех устройствах")
+CREATE TABLE user_token_revocations (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMP NOT NULL
+);
+
+-- Создание таблицы одноразовых токенов сброса пароля (хранится только SHA-256 хеш токена)
+CREATE TABLE password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- Создание таблицы одноразовых токенов подтверждения email (хранится только SHA-256 хеш токена)
+CREATE TABLE email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+
+-- Создание таблицы TOTP секретов второго фактора (confirmed_at NULL - подключение не завершено)
+CREATE TABLE user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- защищает от повторного ввода одного и того же кода
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Создание таблицы кодов восстановления (хранится только SHA-256 хеш кода)
+CREATE TABLE mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+-- Создание таблицы незавершенных входов, ожидающих второй фактор (хранится только SHA-256 хеш токена)
+CREATE TABLE mfa_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_mfa_challenges_user_id ON mfa_challenges(user_id);
+
+-- Создание таблицы счетчиков неудачных попыток входа (ключ - email:<адрес> или ip:<адрес>)
+CREATE TABLE login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
+
+-- Создание журнала смены ролей (old_role NULL - роль назначена при создании аккаунта по приглашению)
+CREATE TABLE role_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_role VARCHAR(50),
+    new_role VARCHAR(50) NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('admin', 'invitation', 'directory')),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_role_changes_user_id ON role_changes(user_id);
+
+-- Создание таблицы API клиентов сервисов (OAuth2 client_credentials, хранится только SHA-256 хеш секрета)
+CREATE TABLE api_clients (
+    id UUID PRIMARY KEY, -- client_id
+    name VARCHAR(100) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    scope TEXT NOT NULL, -- права через пробел
+    redirect_uris TEXT NOT NULL DEFAULT '', -- адреса возврата OpenID Connect через пробел
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+-- Создание таблицы кодов авторизации OpenID Connect (хранится только SHA-256 хеш кода)
+CREATE TABLE authorization_codes (
+    code_hash VARCHAR(64) PRIMARY KEY,
+    client_id UUID NOT NULL REFERENCES api_clients(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_id UUID NOT NULL,
+    redirect_uri TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    nonce VARCHAR(255) NOT NULL DEFAULT '',
+    code_challenge VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP
+);
+
+-- Создание таблицы персональных API ключей (хранится только SHA-256 хеш ключа)
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL, -- начало ключа для отображения в списке
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scope TEXT NOT NULL DEFAULT '', -- права через пробел, пусто - все права роли
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+-- Создание таблицы учетных записей во внешних провайдерах входа (корпоративный SSO)
+CREATE TABLE user_identities (
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL, -- sub из ID токена провайдера
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL, -- email на момент последнего входа
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMP,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- Создание таблицы незавершенных входов через внешний провайдер (хранится только SHA-256 хеш state)
+CREATE TABLE sso_states (
+    state_hash VARCHAR(64) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL, -- PKCE, отправляется провайдеру при обмене кода
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создание таблицы групп сотрудников из кадровой системы (SCIM)
+CREATE TABLE groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    display_name VARCHAR(255) UNIQUE NOT NULL,
+    external_id VARCHAR(255) UNIQUE, -- идентификатор группы в кадровой системе
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создание таблицы участников групп
+CREATE TABLE group_members (
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX idx_group_members_user_id ON group_members(user_id);
+
+-- Создание таблицы приглашений (хранится только SHA-256 хеш токена)
+CREATE TABLE invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL CHECK (role IN ('employee', 'manager', 'admin')),
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_invitations_email ON invitations(email);
 
 -- Подключаемся к user_db для будущих таблиц
 \c user_db;
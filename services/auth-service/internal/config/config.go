@@ -1,11 +1,33 @@
 package config
 
+import "time"
+
 // Config содержит все настройки приложения
 type Config struct {
-	Port       string
-	Database   DatabaseConfig
-	JWT        JWTConfig
-	BCryptCost int
+	Port          string
+	CORS          CORSConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	BCryptCost    int
+	Auth          AuthConfig
+	Mail          MailConfig
+	PasswordReset PasswordResetConfig
+	Verification  EmailVerificationConfig
+	Invitation    InvitationConfig
+	MFA           MFAConfig
+	Lockout       LockoutConfig
+	RateLimit     RateLimitConfig
+	Erasure       ErasureConfig
+	Password      PasswordPolicyConfig
+	PasswordHash  PasswordHashConfig
+	OIDC          OIDCConfig
+	SSO           SSOConfig
+	LDAP          LDAPConfig
+}
+
+// CORSConfig содержит настройки запросов из браузера с других источников
+type CORSConfig struct {
+	AllowedOrigins []string // источники фронтенда, которым разрешены запросы с cookie (вход через SSO)
 }
 
 // DatabaseConfig содержит настройки подключения к БД
@@ -19,5 +41,169 @@ type DatabaseConfig struct {
 
 // JWTConfig содержит настройки JWT
 type JWTConfig struct {
-	Secret string
+	Secret                 string
+	AccessTokenTTL         time.Duration
+	RefreshTokenTTL        time.Duration
+	RevocationSyncInterval time.Duration // как часто перечитывать список отозванных токенов из БД
+
+	// Асимметричная подпись (RS256/EdDSA). Если SigningKeyFile пуст, используется HS256 с Secret
+	SigningKeyFile       string   // PEM файл приватного ключа подписи
+	SigningKeyID         string   // kid активного ключа, по умолчанию JWK Thumbprint
+	VerificationKeyFiles []string // PEM файлы публичных ключей, которые еще принимаются после ротации
+}
+
+// Режимы самостоятельной регистрации
+const (
+	RegistrationOpen       = "open"        // любой email
+	RegistrationDomain     = "domain"      // только email из RegistrationAllowedDomains
+	RegistrationInviteOnly = "invite_only" // только по приглашению
+)
+
+// AuthConfig содержит правила аутентификации
+type AuthConfig struct {
+	RequireEmailVerification   bool     // запрещать вход с неподтвержденным email
+	RegistrationMode           string   // open, domain или invite_only; приглашения работают в любом режиме
+	RegistrationAllowedDomains []string // домены email в нижнем регистре для режима domain
+	RegistrationDeniedDomains  []string // домены email, с которых регистрация запрещена в любом режиме
+}
+
+// Классы символов, которые может требовать политика паролей
+const (
+	PasswordClassLower  = "lower"  // строчная буква
+	PasswordClassUpper  = "upper"  // заглавная буква
+	PasswordClassDigit  = "digit"  // цифра
+	PasswordClassSymbol = "symbol" // знак препинания или другой символ
+)
+
+// PasswordPolicyConfig содержит правила для новых паролей
+type PasswordPolicyConfig struct {
+	MinLength        int      // минимальная длина в символах
+	MaxBytes         int      // максимальная длина в байтах, не больше 72 из-за ограничения bcrypt
+	RequiredClasses  []string // классы символов, которые должны встречаться в пароле
+	BreachedListFile string   // файл SHA-1 хешей утекших паролей, пусто - проверка отключена
+}
+
+// Алгоритмы хеширования паролей
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// PasswordHashConfig содержит настройки хеширования новых паролей. Хеши другого алгоритма
+// или с более слабыми параметрами перехешируются при следующем успешном входе.
+type PasswordHashConfig struct {
+	Algorithm         string // bcrypt (стоимость из BCryptCost) или argon2id
+	Argon2Memory      uint32 // память argon2id в КиБ
+	Argon2Iterations  uint32 // число проходов argon2id
+	Argon2Parallelism uint8  // число потоков argon2id
+}
+
+// MailConfig содержит настройки отправки писем
+type MailConfig struct {
+	Sender  string // log - вывод в лог, file - запись в каталог FileDir
+	From    string
+	FileDir string
+}
+
+// PasswordResetConfig содержит настройки восстановления пароля
+type PasswordResetConfig struct {
+	TokenTTL time.Duration
+	URL      string // страница фронтенда, к которой добавляется ?token=
+}
+
+// EmailVerificationConfig содержит настройки подтверждения email
+type EmailVerificationConfig struct {
+	TokenTTL time.Duration
+	URL      string // адрес GET /api/v1/verify-email, к которому добавляется ?token=
+}
+
+// InvitationConfig содержит настройки приглашений
+type InvitationConfig struct {
+	TokenTTL time.Duration
+	URL      string // страница фронтенда, к которой добавляется ?token=
+}
+
+// ErasureConfig содержит настройки стирания персональных данных удаленных пользователей
+type ErasureConfig struct {
+	GracePeriod time.Duration // сколько удаленная учетная запись хранится до стирания
+	Interval    time.Duration // период запуска задачи стирания
+}
+
+// OIDCConfig содержит настройки встроенного OpenID Connect провайдера
+type OIDCConfig struct {
+	Issuer   string        // внешний адрес сервиса, значение iss в ID токенах
+	LoginURL string        // страница фронтенда, где пользователь входит и подтверждает вход во внешний сервис
+	CodeTTL  time.Duration // время жизни кода авторизации
+}
+
+// SSOConfig содержит настройки входа через внешние провайдеры удостоверений (корпоративный SSO)
+type SSOConfig struct {
+	Providers     []SSOProviderConfig
+	AutoProvision bool          // создавать учетную запись сотрудника при первом входе, если email не найден
+	StateTTL      time.Duration // время на вход во внешнем провайдере
+}
+
+// SSOProviderConfig содержит настройки внешнего OpenID Connect провайдера
+type SSOProviderConfig struct {
+	Name         string   // имя провайдера в адресах /api/v1/sso/:provider
+	Issuer       string   // iss провайдера, по нему загружается /.well-known/openid-configuration
+	ClientID     string   // client_id сервиса, зарегистрированного у провайдера
+	ClientSecret string   // client_secret сервиса
+	RedirectURL  string   // страница фронтенда, на которую провайдер возвращает пользователя
+	Scopes       []string // запрашиваемые scope, должен быть openid
+}
+
+// LDAPConfig содержит настройки входа через корпоративный каталог LDAP / Active Directory
+type LDAPConfig struct {
+	URL            string        // ldap:// или ldaps:// адрес сервера, пустой - вход через каталог отключен
+	StartTLS       bool          // перейти на TLS командой StartTLS после подключения по ldap://
+	BindDN         string        // служебная учетная запись для поиска пользователей, пустая - анонимный поиск
+	BindPassword   string        // пароль служебной учетной записи
+	BaseDN         string        // ветка каталога, в которой ищутся пользователи
+	UserFilter     string        // фильтр поиска пользователя, %s заменяется email
+	EmailAttribute string        // атрибут с email пользователя
+	GroupAttribute string        // атрибут с DN групп пользователя
+	EmployeeGroups []string      // группы с доступом к порталу, пусто - доступ у всех пользователей каталога
+	ManagerGroups  []string      // группы, участники которых получают роль manager
+	Timeout        time.Duration // время ожидания ответа сервера
+}
+
+// MFAConfig содержит настройки двухфакторной аутентификации (TOTP)
+type MFAConfig struct {
+	Issuer              string        // название сервиса в приложении-аутентификаторе
+	RequiredForManagers bool          // менеджеры и администраторы не могут войти без второго фактора
+	ChallengeTTL        time.Duration // время на ввод кода после проверки пароля
+}
+
+// LockoutConfig содержит настройки защиты входа от перебора паролей
+type LockoutConfig struct {
+	Store         string        // postgres - общий счетчик для всех экземпляров, memory - в памяти процесса
+	MaxFailures   int           // неудачных попыток на аккаунт до блокировки
+	IPMaxFailures int           // неудачных попыток с одного IP до блокировки
+	BaseDuration  time.Duration // первая блокировка, каждая следующая вдвое дольше
+	MaxDuration   time.Duration // верхняя граница блокировки
+	FailureWindow time.Duration // после стольких минут без ошибок счетчик начинается заново
+}
+
+// RateLimitConfig содержит ограничения частоты запросов к публичным маршрутам
+type RateLimitConfig struct {
+	Register           RouteRateLimit
+	Login              RouteRateLimit
+	PasswordForgot     RouteRateLimit
+	VerificationResend RouteRateLimit
+	LoginMFA           RouteRateLimit // ввод кода второго фактора по challenge
+	PasswordReset      RouteRateLimit // смена пароля по токену из письма
+	InvitationAccept   RouteRateLimit // принятие приглашения по токену
+}
+
+// RouteRateLimit содержит лимиты одного маршрута
+type RouteRateLimit struct {
+	IP    RateLimit // на адрес клиента
+	Email RateLimit // на email из тела запроса
+}
+
+// RateLimit задает корзину токенов: Burst запросов подряд, полное восстановление за Period
+type RateLimit struct {
+	Burst  int // 0 - без ограничения
+	Period time.Duration
 }
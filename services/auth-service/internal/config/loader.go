@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/avangero/auth-service/internal/lang"
 )
@@ -24,9 +25,79 @@ func NewLoader(messages lang.Messages) *Loader {
 func (l *Loader) Load() (*Config, error) {
 	cfg := &Config{
 		Port: l.getEnv("PORT", "8081"),
+		CORS: CORSConfig{
+			AllowedOrigins: l.parseList(l.getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000")),
+		},
 		JWT: JWTConfig{
-			Secret: l.getEnv("JWT_SECRET", ""),
+			Secret:               l.getEnv("JWT_SECRET", ""),
+			SigningKeyFile:       l.getEnv("JWT_SIGNING_KEY_FILE", ""),
+			SigningKeyID:         l.getEnv("JWT_SIGNING_KEY_ID", ""),
+			VerificationKeyFiles: l.parseList(l.getEnv("JWT_VERIFICATION_KEY_FILES", "")),
+		},
+		Mail: MailConfig{
+			Sender:  l.getEnv("MAIL_SENDER", "log"),
+			From:    l.getEnv("MAIL_FROM", "noreply@learning-portal.local"),
+			FileDir: l.getEnv("MAIL_FILE_DIR", "tmp/mail"),
+		},
+		PasswordReset: PasswordResetConfig{
+			URL: l.getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		},
+		Verification: EmailVerificationConfig{
+			URL: l.getEnv("EMAIL_VERIFICATION_URL", "http://localhost:8081/api/v1/verify-email"),
+		},
+		Invitation: InvitationConfig{
+			URL: l.getEnv("INVITATION_URL", "http://localhost:3000/accept-invitation"),
+		},
+		MFA: MFAConfig{
+			Issuer: l.getEnv("MFA_ISSUER", "Learning Portal"),
 		},
+		Lockout: LockoutConfig{
+			Store: l.getEnv("LOCKOUT_STORE", "postgres"),
+		},
+		OIDC: OIDCConfig{
+			Issuer:   strings.TrimSuffix(l.getEnv("OIDC_ISSUER", "http://localhost:8081"), "/"),
+			LoginURL: l.getEnv("OIDC_LOGIN_URL", "http://localhost:3000/oauth/authorize"),
+		},
+	}
+
+	// Загружаем правила аутентификации
+	requireVerification, err := l.parseBool(l.getEnv("REQUIRE_EMAIL_VERIFICATION", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("недопустимое значение REQUIRE_EMAIL_VERIFICATION: %v", err)
+	}
+	cfg.Auth.RequireEmailVerification = requireVerification
+	cfg.Auth.RegistrationMode = l.getEnv("REGISTRATION_MODE", RegistrationOpen)
+	if cfg.Auth.RegistrationAllowedDomains, err = l.loadDomainList("REGISTRATION_ALLOWED_DOMAINS"); err != nil {
+		return nil, err
+	}
+	if cfg.Auth.RegistrationDeniedDomains, err = l.loadDomainList("REGISTRATION_DENIED_DOMAINS"); err != nil {
+		return nil, err
+	}
+
+	requireManagerMFA, err := l.parseBool(l.getEnv("REQUIRE_MFA_FOR_MANAGERS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("недопустимое значение REQUIRE_MFA_FOR_MANAGERS: %v", err)
+	}
+	cfg.MFA.RequiredForManagers = requireManagerMFA
+
+	// Загружаем настройки входа через внешний провайдер
+	if err := l.loadSSO(cfg); err != nil {
+		return nil, err
+	}
+
+	// Загружаем настройки входа через каталог LDAP
+	if err := l.loadLDAP(cfg); err != nil {
+		return nil, err
+	}
+
+	// Загружаем политику паролей
+	if err := l.loadPasswordPolicy(cfg); err != nil {
+		return nil, err
+	}
+
+	// Загружаем настройки хеширования паролей
+	if err := l.loadPasswordHash(cfg); err != nil {
+		return nil, err
 	}
 
 	// Загружаем BCRYPT_COST
@@ -36,6 +107,21 @@ func (l *Loader) Load() (*Config, error) {
 	}
 	cfg.BCryptCost = bcryptCost
 
+	// Загружаем время жизни токенов
+	if err := l.loadTokenTTL(cfg); err != nil {
+		return nil, err
+	}
+
+	// Загружаем настройки защиты от перебора паролей
+	if err := l.loadLockout(cfg); err != nil {
+		return nil, err
+	}
+
+	// Загружаем ограничения частоты запросов
+	if err := l.loadRateLimits(cfg); err != nil {
+		return nil, err
+	}
+
 	// Загружаем конфигурацию БД - поддерживаем DATABASE_URL и отдельные переменные
 	if err := l.loadDatabaseConfig(cfg); err != nil {
 		return nil, err
@@ -49,6 +135,77 @@ func (l *Loader) Load() (*Config, error) {
 	return cfg, nil
 }
 
+// loadTokenTTL загружает время жизни токенов и интервал синхронизации отзывов
+func (l *Loader) loadTokenTTL(cfg *Config) error {
+	accessTTL, err := l.parseDuration(l.getEnv("JWT_ACCESS_TTL", "15m"), 15*time.Minute)
+	if err != nil {
+		return fmt.Errorf("%s: JWT_ACCESS_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.JWT.AccessTokenTTL = accessTTL
+
+	refreshTTL, err := l.parseDuration(l.getEnv("JWT_REFRESH_TTL", "720h"), 720*time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: JWT_REFRESH_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.JWT.RefreshTokenTTL = refreshTTL
+
+	syncInterval, err := l.parseDuration(l.getEnv("JWT_REVOCATION_SYNC_INTERVAL", "30s"), 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("%s: JWT_REVOCATION_SYNC_INTERVAL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.JWT.RevocationSyncInterval = syncInterval
+
+	resetTTL, err := l.parseDuration(l.getEnv("PASSWORD_RESET_TTL", "1h"), time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: PASSWORD_RESET_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.PasswordReset.TokenTTL = resetTTL
+
+	verificationTTL, err := l.parseDuration(l.getEnv("EMAIL_VERIFICATION_TTL", "24h"), 24*time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: EMAIL_VERIFICATION_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.Verification.TokenTTL = verificationTTL
+
+	invitationTTL, err := l.parseDuration(l.getEnv("INVITATION_TTL", "72h"), 72*time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: INVITATION_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.Invitation.TokenTTL = invitationTTL
+
+	mfaChallengeTTL, err := l.parseDuration(l.getEnv("MFA_CHALLENGE_TTL", "5m"), 5*time.Minute)
+	if err != nil {
+		return fmt.Errorf("%s: MFA_CHALLENGE_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.MFA.ChallengeTTL = mfaChallengeTTL
+
+	oidcCodeTTL, err := l.parseDuration(l.getEnv("OIDC_CODE_TTL", "1m"), time.Minute)
+	if err != nil {
+		return fmt.Errorf("%s: OIDC_CODE_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.OIDC.CodeTTL = oidcCodeTTL
+
+	ssoStateTTL, err := l.parseDuration(l.getEnv("SSO_STATE_TTL", "10m"), 10*time.Minute)
+	if err != nil {
+		return fmt.Errorf("%s: SSO_STATE_TTL: %v", l.messages.Get(lang.JWTTTLInvalid), err)
+	}
+	cfg.SSO.StateTTL = ssoStateTTL
+
+	erasureGracePeriod, err := l.parseDuration(l.getEnv("ERASURE_GRACE_PERIOD", "720h"), 720*time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: ERASURE_GRACE_PERIOD: %v", l.messages.Get(lang.ErasureConfigInvalid), err)
+	}
+	cfg.Erasure.GracePeriod = erasureGracePeriod
+
+	erasureInterval, err := l.parseDuration(l.getEnv("ERASURE_INTERVAL", "1h"), time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: ERASURE_INTERVAL: %v", l.messages.Get(lang.ErasureConfigInvalid), err)
+	}
+	cfg.Erasure.Interval = erasureInterval
+
+	return nil
+}
+
 // loadDatabaseConfig загружает конфигурацию БД из DATABASE_URL или отдельных переменных
 func (l *Loader) loadDatabaseConfig(cfg *Config) error {
 	// Проверяем DATABASE_URL сначала
@@ -109,6 +266,95 @@ func (l *Loader) getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// loadLockout загружает пороги и длительности блокировки входа
+func (l *Loader) loadLockout(cfg *Config) error {
+	maxFailures, err := l.parseInt(l.getEnv("LOCKOUT_MAX_FAILURES", "5"), 5)
+	if err != nil {
+		return fmt.Errorf("%s: LOCKOUT_MAX_FAILURES: %v", l.messages.Get(lang.LockoutConfigInvalid), err)
+	}
+	cfg.Lockout.MaxFailures = maxFailures
+
+	ipMaxFailures, err := l.parseInt(l.getEnv("LOCKOUT_IP_MAX_FAILURES", "20"), 20)
+	if err != nil {
+		return fmt.Errorf("%s: LOCKOUT_IP_MAX_FAILURES: %v", l.messages.Get(lang.LockoutConfigInvalid), err)
+	}
+	cfg.Lockout.IPMaxFailures = ipMaxFailures
+
+	baseDuration, err := l.parseDuration(l.getEnv("LOCKOUT_BASE_DURATION", "1m"), time.Minute)
+	if err != nil {
+		return fmt.Errorf("%s: LOCKOUT_BASE_DURATION: %v", l.messages.Get(lang.LockoutConfigInvalid), err)
+	}
+	cfg.Lockout.BaseDuration = baseDuration
+
+	maxDuration, err := l.parseDuration(l.getEnv("LOCKOUT_MAX_DURATION", "1h"), time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: LOCKOUT_MAX_DURATION: %v", l.messages.Get(lang.LockoutConfigInvalid), err)
+	}
+	cfg.Lockout.MaxDuration = maxDuration
+
+	failureWindow, err := l.parseDuration(l.getEnv("LOCKOUT_FAILURE_WINDOW", "1h"), time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: LOCKOUT_FAILURE_WINDOW: %v", l.messages.Get(lang.LockoutConfigInvalid), err)
+	}
+	cfg.Lockout.FailureWindow = failureWindow
+
+	return nil
+}
+
+// loadRateLimits загружает лимиты запросов к публичным маршрутам в формате "<запросов>/<период>"
+func (l *Loader) loadRateLimits(cfg *Config) error {
+	limits := []struct {
+		key          string
+		defaultValue string
+		target       *RateLimit
+	}{
+		{"RATE_LIMIT_REGISTER_IP", "5/1m", &cfg.RateLimit.Register.IP},
+		{"RATE_LIMIT_REGISTER_EMAIL", "3/1h", &cfg.RateLimit.Register.Email},
+		{"RATE_LIMIT_LOGIN_IP", "20/1m", &cfg.RateLimit.Login.IP},
+		{"RATE_LIMIT_LOGIN_EMAIL", "10/1m", &cfg.RateLimit.Login.Email},
+		{"RATE_LIMIT_PASSWORD_FORGOT_IP", "5/1m", &cfg.RateLimit.PasswordForgot.IP},
+		{"RATE_LIMIT_PASSWORD_FORGOT_EMAIL", "3/1h", &cfg.RateLimit.PasswordForgot.Email},
+		{"RATE_LIMIT_VERIFY_RESEND_IP", "5/1m", &cfg.RateLimit.VerificationResend.IP},
+		{"RATE_LIMIT_VERIFY_RESEND_EMAIL", "3/1h", &cfg.RateLimit.VerificationResend.Email},
+		{"RATE_LIMIT_LOGIN_MFA_IP", "10/1m", &cfg.RateLimit.LoginMFA.IP},
+		{"RATE_LIMIT_PASSWORD_RESET_IP", "10/1m", &cfg.RateLimit.PasswordReset.IP},
+		{"RATE_LIMIT_INVITATION_ACCEPT_IP", "10/1m", &cfg.RateLimit.InvitationAccept.IP},
+	}
+
+	for _, limit := range limits {
+		rateLimit, err := l.parseRateLimit(l.getEnv(limit.key, limit.defaultValue))
+		if err != nil {
+			return fmt.Errorf("%s: %s: %v", l.messages.Get(lang.RateLimitConfigInvalid), limit.key, err)
+		}
+		*limit.target = rateLimit
+	}
+
+	return nil
+}
+
+// parseRateLimit парсит лимит вида "20/1m"; "0" отключает ограничение
+func (l *Loader) parseRateLimit(value string) (RateLimit, error) {
+	if value == "0" {
+		return RateLimit{}, nil
+	}
+
+	burst, period, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimit{}, fmt.Errorf("ожидается формат <запросов>/<период>, получено %q", value)
+	}
+
+	var rateLimit RateLimit
+	var err error
+	if rateLimit.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil {
+		return RateLimit{}, err
+	}
+	if rateLimit.Period, err = time.ParseDuration(strings.TrimSpace(period)); err != nil {
+		return RateLimit{}, err
+	}
+
+	return rateLimit, nil
+}
+
 // parseInt парсит строку в int с обработкой ошибок
 func (l *Loader) parseInt(value string, defaultValue int) (int, error) {
 	if value == "" {
@@ -116,3 +362,174 @@ func (l *Loader) parseInt(value string, defaultValue int) (int, error) {
 	}
 	return strconv.Atoi(value)
 }
+
+// parseBool парсит строку в bool с обработкой ошибок
+func (l *Loader) parseBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// parseDuration парсит строку в time.Duration с обработкой ошибок
+func (l *Loader) parseDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// loadSSO загружает внешний провайдер входа. Провайдер подключается, только если задан SSO_ISSUER.
+func (l *Loader) loadSSO(cfg *Config) error {
+	autoProvision, err := l.parseBool(l.getEnv("SSO_AUTO_PROVISION", "true"))
+	if err != nil {
+		return fmt.Errorf("%s: SSO_AUTO_PROVISION: %v", l.messages.Get(lang.SSOConfigInvalid), err)
+	}
+	cfg.SSO.AutoProvision = autoProvision
+
+	issuer := l.getEnv("SSO_ISSUER", "")
+	if issuer == "" {
+		return nil
+	}
+
+	cfg.SSO.Providers = append(cfg.SSO.Providers, SSOProviderConfig{
+		Name:         strings.ToLower(l.getEnv("SSO_PROVIDER_NAME", "corporate")),
+		Issuer:       strings.TrimSuffix(issuer, "/"),
+		ClientID:     l.getEnv("SSO_CLIENT_ID", ""),
+		ClientSecret: l.getEnv("SSO_CLIENT_SECRET", ""),
+		RedirectURL:  l.getEnv("SSO_REDIRECT_URL", "http://localhost:3000/sso/callback"),
+		Scopes:       l.parseList(l.getEnv("SSO_SCOPES", "openid,email,profile")),
+	})
+
+	return nil
+}
+
+// loadLDAP загружает настройки каталога LDAP. Списки групп разделяются ";", потому что DN групп содержат запятые.
+func (l *Loader) loadLDAP(cfg *Config) error {
+	startTLS, err := l.parseBool(l.getEnv("LDAP_START_TLS", "false"))
+	if err != nil {
+		return fmt.Errorf("%s: LDAP_START_TLS: %v", l.messages.Get(lang.LDAPConfigInvalid), err)
+	}
+	timeout, err := l.parseDuration(l.getEnv("LDAP_TIMEOUT", "10s"), 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("%s: LDAP_TIMEOUT: %v", l.messages.Get(lang.LDAPConfigInvalid), err)
+	}
+
+	cfg.LDAP = LDAPConfig{
+		URL:            l.getEnv("LDAP_URL", ""),
+		StartTLS:       startTLS,
+		BindDN:         l.getEnv("LDAP_BIND_DN", ""),
+		BindPassword:   l.getEnv("LDAP_BIND_PASSWORD", ""),
+		BaseDN:         l.getEnv("LDAP_BASE_DN", ""),
+		UserFilter:     l.getEnv("LDAP_USER_FILTER", "(&(objectClass=person)(mail=%s))"),
+		EmailAttribute: l.getEnv("LDAP_EMAIL_ATTRIBUTE", "mail"),
+		GroupAttribute: l.getEnv("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		EmployeeGroups: l.parseDNList(l.getEnv("LDAP_EMPLOYEE_GROUPS", "")),
+		ManagerGroups:  l.parseDNList(l.getEnv("LDAP_MANAGER_GROUPS", "")),
+		Timeout:        timeout,
+	}
+
+	return nil
+}
+
+// loadPasswordPolicy загружает правила для новых паролей
+func (l *Loader) loadPasswordPolicy(cfg *Config) error {
+	minLength, err := l.parseInt(l.getEnv("PASSWORD_MIN_LENGTH", "8"), 8)
+	if err != nil {
+		return fmt.Errorf("%s: PASSWORD_MIN_LENGTH: %v", l.messages.Get(lang.PasswordPolicyInvalid), err)
+	}
+	maxBytes, err := l.parseInt(l.getEnv("PASSWORD_MAX_BYTES", "72"), 72)
+	if err != nil {
+		return fmt.Errorf("%s: PASSWORD_MAX_BYTES: %v", l.messages.Get(lang.PasswordPolicyInvalid), err)
+	}
+
+	cfg.Password = PasswordPolicyConfig{
+		MinLength:        minLength,
+		MaxBytes:         maxBytes,
+		BreachedListFile: l.getEnv("PASSWORD_BREACHED_LIST_FILE", ""),
+	}
+	for _, class := range l.parseList(l.getEnv("PASSWORD_REQUIRED_CLASSES", "")) {
+		cfg.Password.RequiredClasses = append(cfg.Password.RequiredClasses, strings.ToLower(class))
+	}
+
+	return nil
+}
+
+// loadPasswordHash загружает алгоритм и параметры хеширования паролей
+func (l *Loader) loadPasswordHash(cfg *Config) error {
+	cfg.PasswordHash.Algorithm = strings.ToLower(l.getEnv("PASSWORD_HASH_ALGORITHM", PasswordHashArgon2id))
+
+	params := []struct {
+		key          string
+		defaultValue int
+		maxValue     int
+		target       func(int)
+	}{
+		{"ARGON2_MEMORY", 19456, 1 << 22, func(v int) { cfg.PasswordHash.Argon2Memory = uint32(v) }},
+		{"ARGON2_ITERATIONS", 2, 1 << 10, func(v int) { cfg.PasswordHash.Argon2Iterations = uint32(v) }},
+		{"ARGON2_PARALLELISM", 1, 255, func(v int) { cfg.PasswordHash.Argon2Parallelism = uint8(v) }},
+	}
+	for _, param := range params {
+		value, err := l.parseInt(l.getEnv(param.key, strconv.Itoa(param.defaultValue)), param.defaultValue)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %v", l.messages.Get(lang.PasswordHashInvalid), param.key, err)
+		}
+		if value < 1 || value > param.maxValue {
+			return fmt.Errorf("%s: %s должно быть от 1 до %d", l.messages.Get(lang.PasswordHashInvalid), param.key, param.maxValue)
+		}
+		param.target(value)
+	}
+
+	return nil
+}
+
+// loadDomainList загружает список доменов email из переменной key (через запятую)
+// и из файла key_FILE (по одному домену в строке, # - комментарий). Списки объединяются.
+func (l *Loader) loadDomainList(key string) ([]string, error) {
+	values := l.parseList(l.getEnv(key, ""))
+
+	if path := l.getEnv(key+"_FILE", ""); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s_FILE: %v", l.messages.Get(lang.RegistrationInvalid), key, err)
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				values = append(values, line)
+			}
+		}
+	}
+
+	var domains []string
+	for _, value := range values {
+		domain := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "@"))
+		if domain == "" || strings.ContainsAny(domain, "@ ") {
+			return nil, fmt.Errorf("%s: %s: %q", l.messages.Get(lang.RegistrationInvalid), key, value)
+		}
+		domains = append(domains, domain)
+	}
+
+	return domains, nil
+}
+
+// parseList парсит список значений, разделенных запятыми
+func (l *Loader) parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseDNList разбирает список DN через ";"
+func (l *Loader) parseDNList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
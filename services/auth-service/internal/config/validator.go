@@ -2,8 +2,11 @@ package config
 
 import (
 	"errors"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/avangero/auth-service/internal/lang"
 )
@@ -22,8 +25,8 @@ func NewConfigValidator(messages lang.Messages) *ConfigValidator {
 
 // Validate валидирует конфигурацию
 func (v *ConfigValidator) Validate(cfg *Config) error {
-	// Проверка JWT Secret
-	if cfg.JWT.Secret == "" {
+	// Проверка JWT Secret (не нужен при асимметричной подписи)
+	if cfg.JWT.Secret == "" && cfg.JWT.SigningKeyFile == "" {
 		return errors.New(v.messages.Get(lang.JWTSecretMissing))
 	}
 
@@ -40,6 +43,164 @@ func (v *ConfigValidator) Validate(cfg *Config) error {
 		return errors.New(v.messages.Get(lang.BCryptCostInvalid) + ": должно быть от 4 до 31")
 	}
 
+	// Проверка времени жизни токенов
+	if cfg.JWT.AccessTokenTTL <= 0 || cfg.JWT.RefreshTokenTTL <= 0 || cfg.JWT.RevocationSyncInterval <= 0 || cfg.PasswordReset.TokenTTL <= 0 || cfg.Verification.TokenTTL <= 0 || cfg.Invitation.TokenTTL <= 0 || cfg.MFA.ChallengeTTL <= 0 || cfg.OIDC.CodeTTL <= 0 || cfg.SSO.StateTTL <= 0 {
+		return errors.New(v.messages.Get(lang.JWTTTLInvalid) + ": должно быть больше нуля")
+	}
+	if cfg.JWT.AccessTokenTTL >= cfg.JWT.RefreshTokenTTL {
+		return errors.New(v.messages.Get(lang.JWTTTLInvalid) + ": JWT_ACCESS_TTL должно быть меньше JWT_REFRESH_TTL")
+	}
+
+	// Проверка способа отправки писем
+	if cfg.Mail.Sender != "log" && cfg.Mail.Sender != "file" {
+		return errors.New(v.messages.Get(lang.MailSenderInvalid) + ": " + cfg.Mail.Sender)
+	}
+
+	// Проверка режима регистрации
+	switch cfg.Auth.RegistrationMode {
+	case RegistrationOpen, RegistrationInviteOnly:
+	case RegistrationDomain:
+		if len(cfg.Auth.RegistrationAllowedDomains) == 0 {
+			return errors.New(v.messages.Get(lang.RegistrationInvalid) + ": для REGISTRATION_MODE=domain укажите REGISTRATION_ALLOWED_DOMAINS")
+		}
+	default:
+		return errors.New(v.messages.Get(lang.RegistrationInvalid) + ": REGISTRATION_MODE=" + cfg.Auth.RegistrationMode)
+	}
+
+	// Проверка политики паролей
+	if cfg.Password.MinLength < 1 || cfg.Password.MaxBytes > 72 || cfg.Password.MinLength > cfg.Password.MaxBytes {
+		return errors.New(v.messages.Get(lang.PasswordPolicyInvalid) + ": должно быть 1 <= PASSWORD_MIN_LENGTH <= PASSWORD_MAX_BYTES <= 72")
+	}
+	for _, class := range cfg.Password.RequiredClasses {
+		switch class {
+		case PasswordClassLower, PasswordClassUpper, PasswordClassDigit, PasswordClassSymbol:
+		default:
+			return errors.New(v.messages.Get(lang.PasswordPolicyInvalid) + ": PASSWORD_REQUIRED_CLASSES=" + class)
+		}
+	}
+
+	// Проверка хеширования паролей
+	if cfg.PasswordHash.Algorithm != PasswordHashBcrypt && cfg.PasswordHash.Algorithm != PasswordHashArgon2id {
+		return errors.New(v.messages.Get(lang.PasswordHashInvalid) + ": PASSWORD_HASH_ALGORITHM=" + cfg.PasswordHash.Algorithm)
+	}
+	if cfg.PasswordHash.Argon2Memory < 8*uint32(cfg.PasswordHash.Argon2Parallelism) {
+		return errors.New(v.messages.Get(lang.PasswordHashInvalid) + ": ARGON2_MEMORY должно быть не меньше 8 * ARGON2_PARALLELISM")
+	}
+
+	// Проверка защиты от перебора паролей
+	if cfg.Lockout.Store != "postgres" && cfg.Lockout.Store != "memory" {
+		return errors.New(v.messages.Get(lang.LockoutConfigInvalid) + ": LOCKOUT_STORE=" + cfg.Lockout.Store)
+	}
+	if cfg.Lockout.MaxFailures < 1 || cfg.Lockout.IPMaxFailures < 1 || cfg.Lockout.BaseDuration <= 0 || cfg.Lockout.FailureWindow <= 0 {
+		return errors.New(v.messages.Get(lang.LockoutConfigInvalid) + ": должно быть больше нуля")
+	}
+	if cfg.Lockout.MaxDuration < cfg.Lockout.BaseDuration {
+		return errors.New(v.messages.Get(lang.LockoutConfigInvalid) + ": LOCKOUT_MAX_DURATION должно быть не меньше LOCKOUT_BASE_DURATION")
+	}
+
+	// Проверка ограничений частоты запросов
+	for _, route := range []RouteRateLimit{cfg.RateLimit.Register, cfg.RateLimit.Login, cfg.RateLimit.PasswordForgot, cfg.RateLimit.VerificationResend, cfg.RateLimit.LoginMFA, cfg.RateLimit.PasswordReset, cfg.RateLimit.InvitationAccept} {
+		for _, limit := range []RateLimit{route.IP, route.Email} {
+			if limit.Burst < 0 || (limit.Burst > 0 && limit.Period <= 0) {
+				return errors.New(v.messages.Get(lang.RateLimitConfigInvalid) + ": количество запросов и период должны быть больше нуля")
+			}
+		}
+	}
+
+	// Проверка источников CORS: запросы идут с cookie, поэтому "*" недопустим,
+	// а источник - только схема и хост без пути, как в заголовке Origin
+	if len(cfg.CORS.AllowedOrigins) == 0 {
+		return errors.New(v.messages.Get(lang.CORSConfigInvalid) + ": укажите CORS_ALLOWED_ORIGINS")
+	}
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		parsed, err := url.Parse(origin)
+		if err != nil || !parsed.IsAbs() || parsed.Host == "" || parsed.Path != "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+			return errors.New(v.messages.Get(lang.CORSConfigInvalid) + ": CORS_ALLOWED_ORIGINS=" + origin)
+		}
+	}
+
+	// Проверка OpenID Connect провайдера: iss должен быть абсолютным адресом без query и фрагмента
+	issuer, err := url.Parse(cfg.OIDC.Issuer)
+	if err != nil || !issuer.IsAbs() || issuer.Host == "" || issuer.RawQuery != "" || issuer.Fragment != "" {
+		return errors.New(v.messages.Get(lang.OIDCConfigInvalid) + ": OIDC_ISSUER=" + cfg.OIDC.Issuer)
+	}
+	if loginURL, err := url.Parse(cfg.OIDC.LoginURL); err != nil || !loginURL.IsAbs() {
+		return errors.New(v.messages.Get(lang.OIDCConfigInvalid) + ": OIDC_LOGIN_URL=" + cfg.OIDC.LoginURL)
+	}
+
+	// Проверка внешних провайдеров входа
+	names := make(map[string]bool)
+	for _, provider := range cfg.SSO.Providers {
+		if err := v.validateSSOProvider(provider); err != nil {
+			return err
+		}
+		if names[provider.Name] {
+			return errors.New(v.messages.Get(lang.SSOConfigInvalid) + ": повторяется SSO_PROVIDER_NAME=" + provider.Name)
+		}
+		names[provider.Name] = true
+	}
+
+	// Проверка каталога LDAP
+	if cfg.LDAP.URL != "" {
+		if err := v.validateLDAP(cfg.LDAP); err != nil {
+			return err
+		}
+	}
+
+	// Проверка стирания персональных данных
+	if cfg.Erasure.GracePeriod < 0 || cfg.Erasure.Interval <= 0 {
+		return errors.New(v.messages.Get(lang.ErasureConfigInvalid) + ": ERASURE_GRACE_PERIOD не может быть отрицательным, ERASURE_INTERVAL должно быть больше нуля")
+	}
+
+	return nil
+}
+
+// validateSSOProvider проверяет адреса, данные клиента и scope внешнего провайдера
+func (v *ConfigValidator) validateSSOProvider(provider SSOProviderConfig) error {
+	// Имя scim занято externalId кадровой системы: вход по sub с таким именем попал бы в чужую учетную запись
+	if provider.Name == "" || provider.Name == "scim" || strings.Trim(provider.Name, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+		return errors.New(v.messages.Get(lang.SSOConfigInvalid) + ": SSO_PROVIDER_NAME=" + provider.Name)
+	}
+	issuer, err := url.Parse(provider.Issuer)
+	if err != nil || !issuer.IsAbs() || issuer.Host == "" || issuer.RawQuery != "" || issuer.Fragment != "" {
+		return errors.New(v.messages.Get(lang.SSOConfigInvalid) + ": SSO_ISSUER=" + provider.Issuer)
+	}
+	if redirectURL, err := url.Parse(provider.RedirectURL); err != nil || !redirectURL.IsAbs() || redirectURL.Host == "" {
+		return errors.New(v.messages.Get(lang.SSOConfigInvalid) + ": SSO_REDIRECT_URL=" + provider.RedirectURL)
+	}
+	if provider.ClientID == "" || provider.ClientSecret == "" {
+		return errors.New(v.messages.Get(lang.SSOConfigInvalid) + ": укажите SSO_CLIENT_ID и SSO_CLIENT_SECRET")
+	}
+	if !slices.Contains(provider.Scopes, "openid") {
+		return errors.New(v.messages.Get(lang.SSOConfigInvalid) + ": SSO_SCOPES должен содержать openid")
+	}
+	return nil
+}
+
+// validateLDAP проверяет адрес сервера, служебную учетную запись и фильтр поиска каталога
+func (v *ConfigValidator) validateLDAP(cfg LDAPConfig) error {
+	serverURL, err := url.Parse(cfg.URL)
+	if err != nil || (serverURL.Scheme != "ldap" && serverURL.Scheme != "ldaps") || serverURL.Host == "" {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": LDAP_URL=" + cfg.URL)
+	}
+	if cfg.StartTLS && serverURL.Scheme != "ldap" {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": LDAP_START_TLS используется только с ldap://")
+	}
+	if (cfg.BindDN == "") != (cfg.BindPassword == "") {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": укажите LDAP_BIND_DN и LDAP_BIND_PASSWORD вместе")
+	}
+	if cfg.BaseDN == "" {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": укажите LDAP_BASE_DN")
+	}
+	if strings.Count(cfg.UserFilter, "%s") != 1 || !strings.HasPrefix(cfg.UserFilter, "(") {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": LDAP_USER_FILTER должен быть фильтром с одним %s")
+	}
+	if cfg.EmailAttribute == "" || cfg.GroupAttribute == "" {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": укажите LDAP_EMAIL_ATTRIBUTE и LDAP_GROUP_ATTRIBUTE")
+	}
+	if cfg.Timeout <= 0 {
+		return errors.New(v.messages.Get(lang.LDAPConfigInvalid) + ": LDAP_TIMEOUT должно быть больше нуля")
+	}
 	return nil
 }
 
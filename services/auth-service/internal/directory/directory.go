@@ -0,0 +1,19 @@
+package directory
+
+import "context"
+
+// Entry учетная запись пользователя в корпоративном каталоге
+type Entry struct {
+	DN     string
+	Email  string
+	Groups []string // DN групп, в которые входит пользователь
+}
+
+// Directory корпоративный каталог пользователей (LDAP / Active Directory).
+// Каталог только проверяет пароль и отдает данные учетной записи: роли по группам
+// и создание пользователей остаются в сервисном слое.
+type Directory interface {
+	// Authenticate проверяет пароль пользователя в каталоге.
+	// nil, nil - пользователь не найден, найден неоднозначно или пароль неверный.
+	Authenticate(ctx context.Context, email, password string) (*Entry, error)
+}
@@ -0,0 +1,134 @@
+package directory
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ldapDirectory каталог LDAP. Вход идет по схеме search + bind: служебная учетная запись
+// находит DN пользователя по email, затем пароль проверяется bind от имени этого DN.
+type ldapDirectory struct {
+	cfg      config.LDAPConfig
+	messages lang.Messages
+}
+
+// NewLDAPDirectory создает каталог LDAP
+func NewLDAPDirectory(cfg config.LDAPConfig, messages lang.Messages) Directory {
+	return &ldapDirectory{
+		cfg:      cfg,
+		messages: messages,
+	}
+}
+
+// Authenticate находит пользователя по email и проверяет его пароль. Для каждого входа
+// открывается отдельное соединение: после bind пользователя оно уже не годится для поиска.
+func (d *ldapDirectory) Authenticate(ctx context.Context, email, password string) (*Entry, error) {
+	// Пустой пароль означает unauthenticated bind, который сервер считает успешным (RFC 4513)
+	if password == "" {
+		log.Printf(d.messages.Get(lang.LogLDAPInvalidPassword), email)
+		return nil, nil
+	}
+
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			return nil, d.ldapError(err)
+		}
+	}
+
+	entry, err := d.findUser(conn, email)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			log.Printf(d.messages.Get(lang.LogLDAPInvalidPassword), email)
+			return nil, nil
+		}
+		return nil, d.ldapError(err)
+	}
+
+	return entry, nil
+}
+
+// dial подключается к серверу и при необходимости переходит на TLS
+func (d *ldapDirectory) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(d.cfg.URL, ldap.DialWithDialer(&net.Dialer{Timeout: d.cfg.Timeout}))
+	if err != nil {
+		return nil, d.ldapError(err)
+	}
+	conn.SetTimeout(d.cfg.Timeout)
+
+	if d.cfg.StartTLS {
+		serverURL, _ := url.Parse(d.cfg.URL)
+		if err := conn.StartTLS(&tls.Config{ServerName: serverURL.Hostname(), MinVersion: tls.VersionTLS12}); err != nil {
+			conn.Close()
+			return nil, d.ldapError(err)
+		}
+	}
+
+	return conn, nil
+}
+
+// findUser ищет единственную учетную запись с указанным email. Две и более записи
+// не дают входа: иначе пароль проверялся бы у случайной из них.
+func (d *ldapDirectory) findUser(conn *ldap.Conn, email string) (*Entry, error) {
+	request := ldap.NewSearchRequest(
+		d.cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2, // второй результат нужен только чтобы заметить неоднозначность
+		int(d.cfg.Timeout.Seconds()),
+		false,
+		fmt.Sprintf(d.cfg.UserFilter, ldap.EscapeFilter(email)),
+		[]string{d.cfg.EmailAttribute, d.cfg.GroupAttribute},
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, d.ldapError(err)
+	}
+
+	switch {
+	case len(result.Entries) == 0:
+		log.Printf(d.messages.Get(lang.LogLDAPUserNotFound), email)
+		return nil, nil
+	case len(result.Entries) > 1:
+		log.Printf(d.messages.Get(lang.LogLDAPAmbiguousUser), email)
+		return nil, nil
+	}
+
+	found := result.Entries[0]
+	entry := &Entry{
+		DN:     found.DN,
+		Email:  found.GetEqualFoldAttributeValue(d.cfg.EmailAttribute),
+		Groups: found.GetEqualFoldAttributeValues(d.cfg.GroupAttribute),
+	}
+	// Без email в каталоге нельзя сопоставить запись с пользователем портала
+	if entry.Email == "" {
+		log.Printf(d.messages.Get(lang.LogLDAPUserNotFound), email)
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+// ldapError формирует ошибку обращения к каталогу
+func (d *ldapDirectory) ldapError(err error) error {
+	return fmt.Errorf("%s: %v", d.messages.Get(lang.LDAPError), err)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccountHandler обработчик запросов к персональным данным пользователя
+type AccountHandler struct {
+	accountService services.AccountService
+	messages       lang.Messages
+}
+
+// NewAccountHandler создает новый обработчик персональных данных
+func NewAccountHandler(accountService services.AccountService, messages lang.Messages) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		messages:       messages,
+	}
+}
+
+// Export возвращает все данные, которые сервис хранит о текущем пользователе
+func (h *AccountHandler) Export(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogExportRequest), clientIP)
+
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	export, err := h.accountService.Export(c.Context(), user.ID)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogExportFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="account-export.json"`)
+	return c.JSON(export)
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AdminHandler обработчик административных операций
+type AdminHandler struct {
+	loginGuard     services.LoginGuard
+	userManagement services.UserManagementService
+	validator      *validators.AuthValidator
+	messages       lang.Messages
+}
+
+// NewAdminHandler создает новый обработчик административных операций
+func NewAdminHandler(loginGuard services.LoginGuard, userManagement services.UserManagementService, messages lang.Messages) *AdminHandler {
+	return &AdminHandler{
+		loginGuard:     loginGuard,
+		userManagement: userManagement,
+		validator:      validators.NewAuthValidator(messages),
+		messages:       messages,
+	}
+}
+
+// UnlockUser снимает блокировку входа после неудачных попыток
+func (h *AdminHandler) UnlockUser(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogUnlockRequest), clientIP)
+
+	var req requests.UnlockRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.loginGuard.Unlock(c.Context(), req.Email); err != nil {
+		log.Printf(h.messages.Get(lang.LogUnlockFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogUnlockSuccess), req.Email, middleware.GetUserEmail(c))
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.AccountUnlocked),
+	})
+}
+
+// ChangeRole меняет роль пользователя
+func (h *AdminHandler) ChangeRole(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogChangeRoleRequest), clientIP)
+
+	admin, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	var req requests.ChangeRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	user, err := h.userManagement.ChangeRole(c.Context(), admin, userID, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogChangeRoleFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(user)
+}
+
+// RoleHistory возвращает журнал смены ролей пользователя
+func (h *AdminHandler) RoleHistory(c *fiber.Ctx) error {
+	clientIP := c.IP()
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	changes, err := h.userManagement.RoleHistory(c.Context(), userID)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogRoleHistoryFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	return c.JSON(changes)
+}
+
+// ListUsers возвращает страницу пользователей с фильтрами
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	var req requests.ListUsersRequest
+	if err := c.QueryParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	list, err := h.userManagement.ListUsers(c.Context(), &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogListUsersFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	return c.JSON(list)
+}
+
+// GetUser возвращает пользователя по ID
+func (h *AdminHandler) GetUser(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	user, err := h.userManagement.GetUser(c.Context(), userID)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogGetUserFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(user)
+}
+
+// ChangeStatus приостанавливает, деактивирует или снова активирует учетную запись
+func (h *AdminHandler) ChangeStatus(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	admin, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	var req requests.ChangeStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	user, err := h.userManagement.ChangeStatus(c.Context(), admin, userID, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogChangeStatusFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(user)
+}
+
+// DeleteUser удаляет учетную запись пользователя
+func (h *AdminHandler) DeleteUser(c *fiber.Ctx) error {
+	return h.userAction(c, lang.LogDeleteUserFailed, lang.UserDeleted, h.userManagement.DeleteUser)
+}
+
+// ResetUser сбрасывает пароль, сессии и блокировку входа пользователя.
+// Тело запроса необязательно; reset_mfa дополнительно отключает второй фактор.
+func (h *AdminHandler) ResetUser(c *fiber.Ctx) error {
+	var req requests.ResetUserRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			log.Printf(h.messages.Get(lang.LogParseRequestFailed), c.IP(), err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": h.messages.Get(lang.InvalidRequestFormat),
+			})
+		}
+	}
+
+	return h.userAction(c, lang.LogResetUserFailed, lang.UserReset, func(ctx context.Context, actor *models.User, userID uuid.UUID) error {
+		return h.userManagement.ResetUser(ctx, actor, userID, &req)
+	})
+}
+
+// userAction выполняет операцию администратора над пользователем из параметра :id
+func (h *AdminHandler) userAction(
+	c *fiber.Ctx,
+	failedKey lang.MessageKey,
+	successKey lang.MessageKey,
+	action func(ctx context.Context, actor *models.User, userID uuid.UUID) error,
+) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	admin, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	if err := action(c.Context(), admin, userID); err != nil {
+		log.Printf(h.messages.Get(failedKey), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(successKey),
+	})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler обработчик запросов к персональным API ключам текущего пользователя
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+	validator     *validators.AuthValidator
+	messages      lang.Messages
+}
+
+// NewAPIKeyHandler создает новый обработчик API ключей
+func NewAPIKeyHandler(apiKeyService services.APIKeyService, messages lang.Messages) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     validators.NewAuthValidator(messages),
+		messages:      messages,
+	}
+}
+
+// List возвращает действующие API ключи текущего пользователя
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAPIKeysRequest), clientIP)
+
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	keys, err := h.apiKeyService.List(c.Context(), user.ID)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogAPIKeyFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	return c.JSON(keys)
+}
+
+// Create выпускает API ключ и один раз возвращает его значение
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAPIKeysRequest), clientIP)
+
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	var req requests.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	created, err := h.apiKeyService.Create(c.Context(), user, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogAPIKeyFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// Revoke отзывает API ключ текущего пользователя
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAPIKeysRequest), clientIP)
+
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	if err := h.apiKeyService.Revoke(c.Context(), user, keyID); err != nil {
+		log.Printf(h.messages.Get(lang.LogAPIKeyFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.APIKeyRevoked),
+	})
+}
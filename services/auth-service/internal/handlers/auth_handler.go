@@ -4,9 +4,11 @@ import (
 	"log"
 
 	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
 	"github.com/avangero/auth-service/internal/models"
 	"github.com/avangero/auth-service/internal/models/requests"
 	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/avangero/auth-service/internal/services"
 	"github.com/avangero/auth-service/internal/validators"
 	"github.com/gofiber/fiber/v2"
@@ -19,11 +21,12 @@ type AuthHandler struct {
 	messages    lang.Messages
 }
 
-// NewAuthHandler создает новый обработчик аутентификации
-func NewAuthHandler(authService services.AuthService, messages lang.Messages) *AuthHandler {
+// NewAuthHandler создает новый обработчик аутентификации.
+// registrationDomains и passwordPolicy проверяют запрос регистрации до обращения к сервису.
+func NewAuthHandler(authService services.AuthService, registrationDomains models.EmailDomainPolicy, passwordPolicy passwords.Policy, messages lang.Messages) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
-		validator:   validators.NewAuthValidator(messages),
+		validator:   validators.NewAuthValidator(messages, validators.WithEmailDomainPolicy(registrationDomains), validators.WithPasswordPolicy(passwordPolicy)),
 		messages:    messages,
 	}
 }
@@ -60,7 +63,7 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	}
 
 	// Регистрация
-	response, err := h.authService.Register(c.Context(), &req)
+	response, err := h.authService.Register(c.Context(), &req, clientInfo(c))
 	if err != nil {
 		log.Printf(h.messages.Get(lang.LogRegistrationFailed), clientIP, err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -95,7 +98,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Аутентификация
-	response, err := h.authService.Login(c.Context(), &req)
+	response, err := h.authService.Login(c.Context(), &req, clientInfo(c))
 	if err != nil {
 		log.Printf(h.messages.Get(lang.LogLoginFailed), clientIP, err)
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -107,6 +110,41 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// Refresh обменивает refresh токен на новую пару токенов
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogRefreshRequest), clientIP)
+
+	var req requests.RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	// Ротация токенов
+	response, err := h.authService.Refresh(c.Context(), &req, clientInfo(c))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogRefreshFailed), clientIP, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogRefreshSuccess), clientIP, response.User.Email)
+	return c.JSON(response)
+}
+
 // GetMe возвращает информацию о текущем пользователе
 func (h *AuthHandler) GetMe(c *fiber.Ctx) error {
 	clientIP := c.IP()
@@ -122,10 +160,68 @@ func (h *AuthHandler) GetMe(c *fiber.Ctx) error {
 	return c.JSON(user)
 }
 
-// ValidateToken валидирует JWT токен
+// Logout отзывает текущий токен пользователя
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogLogoutRequest), clientIP)
+
+	user, ok := c.Locals("user").(*models.User)
+	tokenString, hasToken := c.Locals("token").(string)
+	if !ok || !hasToken {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	if err := h.authService.Logout(c.Context(), tokenString); err != nil {
+		log.Printf(h.messages.Get(lang.LogLogoutFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogLogoutSuccess), clientIP, user.Email)
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.LoggedOut),
+	})
+}
+
+// LogoutAll отзывает все токены пользователя на всех устройствах
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogLogoutRequest), clientIP)
+
+	user, ok := c.Locals("user").(*models.User)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	if err := h.authService.LogoutAll(c.Context(), user.ID); err != nil {
+		log.Printf(h.messages.Get(lang.LogLogoutFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogLogoutAllSuccess), clientIP, user.Email)
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.LoggedOutEverywhere),
+	})
+}
+
+// ValidateToken валидирует JWT токен. Пользователь проверяет собственный токен,
+// API клиент с правом tokens:validate - токен пользователя из тела запроса.
 func (h *AuthHandler) ValidateToken(c *fiber.Ctx) error {
 	clientIP := c.IP()
 
+	if _, ok := middleware.GetClient(c); ok {
+		return h.validateUserToken(c)
+	}
+
 	// Получаем пользователя из контекста (установлен в middleware)
 	user, ok := c.Locals("user").(*models.User)
 	if !ok {
@@ -137,7 +233,52 @@ func (h *AuthHandler) ValidateToken(c *fiber.Ctx) error {
 
 	log.Printf("Token validation successful for IP %s, user: %s", clientIP, user.Email)
 	return c.JSON(responses.ValidationResponse{
-		Valid: true,
-		User:  *user,
+		Valid:       true,
+		User:        *user,
+		Permissions: models.PermissionsForRole(user.Role),
+	})
+}
+
+// validateUserToken проверяет токен пользователя, переданный API клиентом
+func (h *AuthHandler) validateUserToken(c *fiber.Ctx) error {
+	clientIP := c.IP()
+
+	var req requests.ValidateTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	user, err := h.authService.ValidateToken(c.Context(), req.Token)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogJWTValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(responses.ErrorResponse{
+			Error: h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	return c.JSON(responses.ValidationResponse{
+		Valid:       true,
+		User:        *user,
+		Permissions: models.PermissionsForRole(user.Role),
 	})
 }
+
+// clientInfo собирает сведения о клиенте, которые сохраняются в сессии
+func clientInfo(c *fiber.Ctx) models.ClientInfo {
+	return models.ClientInfo{
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ClientHandler обработчик эндпоинта токенов OAuth2 и управления API клиентами
+type ClientHandler struct {
+	clientService services.ClientService
+	oidcService   services.OIDCService
+	validator     *validators.AuthValidator
+	messages      lang.Messages
+}
+
+// NewClientHandler создает новый обработчик API клиентов
+func NewClientHandler(clientService services.ClientService, oidcService services.OIDCService, messages lang.Messages) *ClientHandler {
+	return &ClientHandler{
+		clientService: clientService,
+		oidcService:   oidcService,
+		validator:     validators.NewAuthValidator(messages),
+		messages:      messages,
+	}
+}
+
+// Token выдает токен API клиенту по OAuth2 client_credentials или обменивает код авторизации
+// OpenID Connect (authorization_code). Ошибки возвращаются в формате RFC 6749: {"error": "...", "error_description": "..."}.
+func (h *ClientHandler) Token(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogOAuthTokenRequest), clientIP)
+
+	// Ответы эндпоинта токенов не должны кешироваться
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+
+	var req requests.ClientTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(responses.OAuthErrorResponse{
+			Error:            services.OAuthInvalidRequest,
+			ErrorDescription: h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// client_secret_basic: данные клиента в заголовке Authorization вместо тела
+	usedBasic := false
+	if clientID, secret, ok := basicCredentials(c.Get(fiber.HeaderAuthorization)); ok {
+		if req.ClientID != "" || req.ClientSecret != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(responses.OAuthErrorResponse{
+				Error:            services.OAuthInvalidRequest,
+				ErrorDescription: h.messages.Get(lang.OAuthClientCredentialsDuplicated),
+			})
+		}
+		req.ClientID, req.ClientSecret = clientID, secret
+		usedBasic = true
+	}
+
+	var response *responses.ClientTokenResponse
+	var err error
+	if req.GrantType == services.GrantTypeAuthorizationCode {
+		response, err = h.oidcService.ExchangeCode(c.Context(), &req)
+	} else {
+		response, err = h.clientService.IssueToken(c.Context(), &req)
+	}
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogOAuthTokenFailed), clientIP, err)
+
+		var oauthErr *services.OAuthError
+		if !errors.As(err, &oauthErr) {
+			return c.Status(fiber.StatusInternalServerError).JSON(responses.OAuthErrorResponse{
+				Error: "server_error",
+			})
+		}
+
+		status := fiber.StatusBadRequest
+		if oauthErr.Code == services.OAuthInvalidClient {
+			status = fiber.StatusUnauthorized
+			if usedBasic {
+				c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+			}
+		}
+		return c.Status(status).JSON(responses.OAuthErrorResponse{
+			Error:            oauthErr.Code,
+			ErrorDescription: oauthErr.Description,
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// Create регистрирует API клиента и один раз возвращает его секрет
+func (h *ClientHandler) Create(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	admin, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	var req requests.CreateClientRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	created, err := h.clientService.Create(c.Context(), admin, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogAPIClientFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// List возвращает зарегистрированных API клиентов
+func (h *ClientHandler) List(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	clients, err := h.clientService.List(c.Context())
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogAPIClientFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	return c.JSON(clients)
+}
+
+// Revoke отзывает API клиента
+func (h *ClientHandler) Revoke(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAdminUserRequest), c.Method(), c.Path(), clientIP)
+
+	admin, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	clientID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	if err := h.clientService.Revoke(c.Context(), admin, clientID); err != nil {
+		log.Printf(h.messages.Get(lang.LogAPIClientFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.APIClientRevoked),
+	})
+}
+
+// basicCredentials извлекает client_id и client_secret из заголовка Authorization: Basic.
+// По RFC 6749 оба значения перед кодированием в base64 проходят form-urlencoding.
+func basicCredentials(header string) (string, string, bool) {
+	encoded, found := strings.CutPrefix(header, "Basic ")
+	if !found {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+
+	rawID, rawSecret, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", false
+	}
+
+	clientID, err := url.QueryUnescape(rawID)
+	if err != nil {
+		return "", "", false
+	}
+	secret, err := url.QueryUnescape(rawSecret)
+	if err != nil {
+		return "", "", false
+	}
+
+	return clientID, secret, true
+}
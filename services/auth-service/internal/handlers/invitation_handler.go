@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// InvitationHandler обработчик приглашений
+type InvitationHandler struct {
+	invitationService services.InvitationService
+	validator         *validators.AuthValidator
+	messages          lang.Messages
+}
+
+// NewInvitationHandler создает новый обработчик приглашений
+func NewInvitationHandler(invitationService services.InvitationService, passwordPolicy passwords.Policy, messages lang.Messages) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+		validator:         validators.NewAuthValidator(messages, validators.WithPasswordPolicy(passwordPolicy)),
+		messages:          messages,
+	}
+}
+
+// Create создает приглашение и отправляет ссылку на email
+func (h *InvitationHandler) Create(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogCreateInvitationRequest), clientIP)
+
+	inviter, ok := middleware.GetUser(c)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	var req requests.CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	invitation, err := h.invitationService.Invite(c.Context(), inviter, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogCreateInvitationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(invitation)
+}
+
+// Accept создает аккаунт по приглашению
+func (h *InvitationHandler) Accept(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogAcceptInvitationRequest), clientIP)
+
+	var req requests.AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	response, err := h.invitationService.Accept(c.Context(), &req, clientInfo(c))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogAcceptInvitationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogAcceptInvitationSuccess), clientIP, response.User.Email)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWKSHandler публикует публичные ключи проверки JWT для других сервисов
+type JWKSHandler struct {
+	keySet *keys.KeySet
+}
+
+// NewJWKSHandler создает новый обработчик JWKS
+func NewJWKSHandler(keySet *keys.KeySet) *JWKSHandler {
+	return &JWKSHandler{keySet: keySet}
+}
+
+// GetJWKS возвращает набор публичных ключей в формате JWKS
+func (h *JWKSHandler) GetJWKS(c *fiber.Ctx) error {
+	// Клиенты могут кешировать ключи, но должны перечитывать их после ротации
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.keySet.JWKS())
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MFAHandler обработчик для двухфакторной аутентификации
+type MFAHandler struct {
+	authService services.AuthService
+	mfaService  services.MFAService
+	validator   *validators.AuthValidator
+	messages    lang.Messages
+}
+
+// NewMFAHandler создает новый обработчик двухфакторной аутентификации
+func NewMFAHandler(authService services.AuthService, mfaService services.MFAService, messages lang.Messages) *MFAHandler {
+	return &MFAHandler{
+		authService: authService,
+		mfaService:  mfaService,
+		validator:   validators.NewAuthValidator(messages),
+		messages:    messages,
+	}
+}
+
+// Login завершает вход кодом второго фактора
+func (h *MFAHandler) Login(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogMFALoginRequest), clientIP)
+
+	var req requests.MFALoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	response, err := h.authService.LoginMFA(c.Context(), &req, clientInfo(c))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogMFALoginFailed), clientIP, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogMFALoginSuccess), clientIP, response.User.Email)
+	return c.JSON(response)
+}
+
+// EnrollWithChallenge выдает секрет TOTP во время входа, если второй фактор обязателен, но не настроен
+func (h *MFAHandler) EnrollWithChallenge(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogMFAEnrollRequest), clientIP)
+
+	var req requests.MFAEnrollRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	response, err := h.mfaService.EnrollWithChallenge(c.Context(), &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogMFAEnrollFailed), clientIP, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// Enroll выдает секрет TOTP текущему пользователю
+func (h *MFAHandler) Enroll(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogMFAEnrollRequest), clientIP)
+
+	user, ok := c.Locals("user").(*models.User)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	response, err := h.mfaService.Enroll(c.Context(), user.ID)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogMFAEnrollFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// Confirm включает второй фактор по первому коду из приложения
+func (h *MFAHandler) Confirm(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogMFAConfirmRequest), clientIP)
+
+	user, ok := c.Locals("user").(*models.User)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	var req requests.MFAConfirmRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	response, err := h.mfaService.Confirm(c.Context(), user.ID, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogMFAConfirmFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OIDCHandler обработчик эндпоинтов встроенного OpenID Connect провайдера
+type OIDCHandler struct {
+	oidcService services.OIDCService
+	loginURL    string
+	messages    lang.Messages
+}
+
+// NewOIDCHandler создает новый обработчик OpenID Connect
+func NewOIDCHandler(oidcService services.OIDCService, loginURL string, messages lang.Messages) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		loginURL:    loginURL,
+		messages:    messages,
+	}
+}
+
+// Discovery возвращает документ /.well-known/openid-configuration
+func (h *OIDCHandler) Discovery(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.oidcService.Discovery())
+}
+
+// Authorize проверяет запрос авторизации внешнего сервиса и перенаправляет пользователя
+// на страницу входа портала с теми же параметрами. Код выдается после входа через Approve.
+func (h *OIDCHandler) Authorize(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogOIDCAuthorizeRequest), clientIP)
+
+	var req requests.AuthorizeRequest
+	if err := c.QueryParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	if err := h.oidcService.CheckAuthorization(c.Context(), &req); err != nil {
+		log.Printf(h.messages.Get(lang.LogOIDCAuthorizeFailed), clientIP, err)
+
+		// Ошибки параметров возвращаются клиенту, неизвестный redirect_uri - только пользователю
+		var oauthErr *services.OAuthError
+		if errors.As(err, &oauthErr) {
+			return c.Redirect(services.AuthorizationErrorRedirect(&req, oauthErr), fiber.StatusFound)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Redirect(h.loginRedirect(string(c.Request().URI().QueryString())), fiber.StatusFound)
+}
+
+// loginRedirect добавляет параметры запроса авторизации к странице входа,
+// сохраняя параметры, которые уже заданы в OIDC_LOGIN_URL
+func (h *OIDCHandler) loginRedirect(rawQuery string) string {
+	link, err := url.Parse(h.loginURL)
+	if err != nil {
+		return h.loginURL + "?" + rawQuery
+	}
+
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return h.loginURL + "?" + rawQuery
+	}
+
+	query := link.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// Approve выдает код авторизации вошедшему пользователю и возвращает адрес возврата во внешний сервис
+func (h *OIDCHandler) Approve(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogOIDCAuthorizeRequest), clientIP)
+
+	tokenString, ok := c.Locals("token").(string)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	var req requests.AuthorizeRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	response, err := h.oidcService.Authorize(c.Context(), tokenString, &req)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogOIDCAuthorizeFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// UserInfo возвращает данные пользователя по access токену внешнего сервиса
+func (h *OIDCHandler) UserInfo(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogUserInfoRequest), clientIP)
+
+	tokenString, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !found || tokenString == "" {
+		log.Printf(h.messages.Get(lang.LogJWTMissingHeader), clientIP)
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer`)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenNotProvided),
+		})
+	}
+
+	userInfo, err := h.oidcService.UserInfo(c.Context(), tokenString)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogUserInfoFailed), clientIP, err)
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(userInfo)
+}
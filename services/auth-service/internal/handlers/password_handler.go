@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PasswordHandler обработчик для восстановления пароля
+type PasswordHandler struct {
+	passwordService services.PasswordService
+	validator       *validators.AuthValidator
+	messages        lang.Messages
+}
+
+// NewPasswordHandler создает новый обработчик восстановления пароля
+func NewPasswordHandler(passwordService services.PasswordService, passwordPolicy passwords.Policy, messages lang.Messages) *PasswordHandler {
+	return &PasswordHandler{
+		passwordService: passwordService,
+		validator:       validators.NewAuthValidator(messages, validators.WithPasswordPolicy(passwordPolicy)),
+		messages:        messages,
+	}
+}
+
+// ForgotPassword отправляет письмо со ссылкой на сброс пароля
+func (h *PasswordHandler) ForgotPassword(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogForgotPasswordRequest), clientIP)
+
+	var req requests.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.passwordService.ForgotPassword(c.Context(), &req); err != nil {
+		log.Printf(h.messages.Get(lang.LogForgotPasswordFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	// Ответ одинаковый независимо от существования аккаунта
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.PasswordResetRequested),
+	})
+}
+
+// ResetPassword устанавливает новый пароль по токену из письма
+func (h *PasswordHandler) ResetPassword(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogResetPasswordRequest), clientIP)
+
+	var req requests.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.passwordService.ResetPassword(c.Context(), &req); err != nil {
+		log.Printf(h.messages.Get(lang.LogResetPasswordFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogResetPasswordSuccess), clientIP)
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.PasswordResetComplete),
+	})
+}
+
+// ChangePassword меняет пароль текущего пользователя
+func (h *PasswordHandler) ChangePassword(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogChangePasswordRequest), clientIP)
+
+	user, ok := c.Locals("user").(*models.User)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	var req requests.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+	req.Email = user.Email
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.passwordService.ChangePassword(c.Context(), user.ID, &req); err != nil {
+		log.Printf(h.messages.Get(lang.LogChangePasswordFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Printf(h.messages.Get(lang.LogChangePasswordSuccess), clientIP, user.Email)
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.PasswordChanged),
+	})
+}
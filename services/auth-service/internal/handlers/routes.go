@@ -1,42 +1,154 @@
 package handlers
 
 import (
+	"strings"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
 	"github.com/avangero/auth-service/internal/lang"
 	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/avangero/auth-service/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
+// RouteDeps зависимости обработчиков, из которых SetupRoutes собирает маршруты
+type RouteDeps struct {
+	AuthService         services.AuthService
+	PasswordService     services.PasswordService
+	VerificationService services.EmailVerificationService
+	MFAService          services.MFAService
+	LoginGuard          services.LoginGuard
+	UserManagement      services.UserManagementService
+	InvitationService   services.InvitationService
+	AccountService      services.AccountService
+	SessionService      services.SessionService
+	ClientService       services.ClientService
+	APIKeyService       services.APIKeyService
+	OIDCService         services.OIDCService
+	SSOService          services.SSOService
+	SCIMService         services.SCIMService
+	RegistrationDomains models.EmailDomainPolicy
+	PasswordPolicy      passwords.Policy
+	RateLimits          config.RateLimitConfig
+	CORS                config.CORSConfig
+	OIDCConfig          config.OIDCConfig
+	KeySet              *keys.KeySet
+	Messages            lang.Messages
+}
+
 // SetupRoutes настраивает маршруты приложения
-func SetupRoutes(app *fiber.App, authService services.AuthService, messages lang.Messages) {
+func SetupRoutes(app *fiber.App, deps RouteDeps) {
+	messages, rateLimits := deps.Messages, deps.RateLimits
+
 	// Middleware
 	app.Use(logger.New())
+	// Cookie sso_state приходит с фронтенда на другом порту или поддомене, поэтому
+	// источники перечислены явно и запросы с credentials разрешены
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowOrigins:     strings.Join(deps.CORS.AllowedOrigins, ","),
+		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowCredentials: true,
 	}))
 
 	// Создаем обработчик с зависимостями
-	authHandler := NewAuthHandler(authService, messages)
+	authHandler := NewAuthHandler(deps.AuthService, deps.RegistrationDomains, deps.PasswordPolicy, messages)
+	passwordHandler := NewPasswordHandler(deps.PasswordService, deps.PasswordPolicy, messages)
+	verificationHandler := NewVerificationHandler(deps.VerificationService, messages)
+	mfaHandler := NewMFAHandler(deps.AuthService, deps.MFAService, messages)
+	adminHandler := NewAdminHandler(deps.LoginGuard, deps.UserManagement, messages)
+	invitationHandler := NewInvitationHandler(deps.InvitationService, deps.PasswordPolicy, messages)
+	accountHandler := NewAccountHandler(deps.AccountService, messages)
+	sessionHandler := NewSessionHandler(deps.SessionService, messages)
+	clientHandler := NewClientHandler(deps.ClientService, deps.OIDCService, messages)
+	oidcHandler := NewOIDCHandler(deps.OIDCService, deps.OIDCConfig.LoginURL, messages)
+	ssoHandler := NewSSOHandler(deps.SSOService, messages)
+	apiKeyHandler := NewAPIKeyHandler(deps.APIKeyService, messages)
+	scimHandler := NewSCIMHandler(deps.SCIMService, messages)
+	jwksHandler := NewJWKSHandler(deps.KeySet)
 
 	// Создаем JWT middleware
-	jwtMiddleware := middleware.JWTMiddleware(authService, messages)
+	jwtMiddleware := middleware.JWTMiddleware(deps.AuthService, deps.ClientService, deps.APIKeyService, messages)
 
 	// Публичные маршруты
 	app.Get("/", authHandler.GetStatus)
+	app.Get("/.well-known/jwks.json", jwksHandler.GetJWKS)
+	app.Get("/.well-known/openid-configuration", oidcHandler.Discovery)
+	app.Post("/oauth/token", clientHandler.Token)
+	app.Get("/oauth/authorize", oidcHandler.Authorize)
+	app.Get("/oauth/userinfo", oidcHandler.UserInfo)
+	app.Post("/oauth/userinfo", oidcHandler.UserInfo)
+
+	// SCIM 2.0 для кадровой системы. Право scim:provision выдается только API клиентам,
+	// поэтому пользователи и API ключи сюда не проходят.
+	scimAPI := app.Group("/scim/v2", jwtMiddleware, middleware.RequirePermission(messages, models.PermissionSCIMProvision))
+	scimAPI.Get("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+	scimAPI.Get("/Users", scimHandler.ListUsers)
+	scimAPI.Post("/Users", scimHandler.CreateUser)
+	scimAPI.Get("/Users/:id", scimHandler.GetUser)
+	scimAPI.Put("/Users/:id", scimHandler.ReplaceUser)
+	scimAPI.Patch("/Users/:id", scimHandler.PatchUser)
+	scimAPI.Delete("/Users/:id", scimHandler.DeleteUser)
+	scimAPI.Get("/Groups", scimHandler.ListGroups)
+	scimAPI.Post("/Groups", scimHandler.CreateGroup)
+	scimAPI.Get("/Groups/:id", scimHandler.GetGroup)
+	scimAPI.Put("/Groups/:id", scimHandler.ReplaceGroup)
+	scimAPI.Patch("/Groups/:id", scimHandler.PatchGroup)
+	scimAPI.Delete("/Groups/:id", scimHandler.DeleteGroup)
 
 	// API группа
 	api := app.Group("/api/v1")
 
 	// Публичные маршруты аутентификации
-	api.Post("/register", authHandler.Register)
-	api.Post("/login", authHandler.Login)
+	api.Post("/register", middleware.RateLimitMiddleware(rateLimits.Register, messages), authHandler.Register)
+	api.Post("/login", middleware.RateLimitMiddleware(rateLimits.Login, messages), authHandler.Login)
+	// Код второго фактора проверяется и при входе, и при обязательной настройке: лимит общий
+	mfaRateLimit := middleware.RateLimitMiddleware(rateLimits.LoginMFA, messages)
+	api.Post("/login/mfa", mfaRateLimit, mfaHandler.Login)
+	api.Get("/sso", ssoHandler.Providers)
+	api.Get("/sso/:provider/login", middleware.RateLimitMiddleware(rateLimits.Login, messages), ssoHandler.Login)
+	api.Post("/sso/:provider/callback", middleware.RateLimitMiddleware(rateLimits.Login, messages), ssoHandler.Callback)
+	api.Post("/login/mfa/enroll", mfaRateLimit, mfaHandler.EnrollWithChallenge)
+	api.Post("/refresh", authHandler.Refresh)
+	api.Post("/password/forgot", middleware.RateLimitMiddleware(rateLimits.PasswordForgot, messages), passwordHandler.ForgotPassword)
+	api.Post("/password/reset", middleware.RateLimitMiddleware(rateLimits.PasswordReset, messages), passwordHandler.ResetPassword)
+	api.Get("/verify-email", verificationHandler.VerifyEmail)
+	api.Post("/invitations/accept", middleware.RateLimitMiddleware(rateLimits.InvitationAccept, messages), invitationHandler.Accept)
+	api.Post("/verify-email/resend", middleware.RateLimitMiddleware(rateLimits.VerificationResend, messages), verificationHandler.ResendVerification)
 
 	// Защищенные маршруты
 	protected := api.Use(jwtMiddleware)
+	sessionRequired := middleware.RequireSession(messages)
 	protected.Get("/me", authHandler.GetMe)
-	protected.Post("/validate", authHandler.ValidateToken)
+	protected.Post("/validate", middleware.RequireScope(messages, models.PermissionTokensValidate), authHandler.ValidateToken)
+	protected.Post("/logout", sessionRequired, authHandler.Logout)
+	protected.Post("/logout/all", sessionRequired, authHandler.LogoutAll)
+	protected.Get("/me/export", sessionRequired, accountHandler.Export)
+	protected.Get("/me/sessions", sessionRequired, sessionHandler.List)
+	protected.Delete("/me/sessions/:id", sessionRequired, sessionHandler.Revoke)
+	protected.Put("/me/password", sessionRequired, passwordHandler.ChangePassword)
+	protected.Post("/me/mfa/enroll", sessionRequired, mfaHandler.Enroll)
+	protected.Post("/me/mfa/confirm", sessionRequired, mfaHandler.Confirm)
+	protected.Get("/me/api-keys", sessionRequired, apiKeyHandler.List)
+	protected.Post("/me/api-keys", sessionRequired, apiKeyHandler.Create)
+	protected.Delete("/me/api-keys/:id", sessionRequired, apiKeyHandler.Revoke)
+	protected.Post("/oauth/authorize", sessionRequired, oidcHandler.Approve)
+
+	// Административные маршруты
+	protected.Post("/admin/users/unlock", middleware.RequirePermission(messages, models.PermissionUsersUnlock), adminHandler.UnlockUser)
+	protected.Get("/admin/users", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.ListUsers)
+	protected.Get("/admin/users/:id", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.GetUser)
+	protected.Put("/admin/users/:id/status", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.ChangeStatus)
+	protected.Post("/admin/users/:id/reset", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.ResetUser)
+	protected.Delete("/admin/users/:id", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.DeleteUser)
+	protected.Put("/admin/users/:id/role", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.ChangeRole)
+	protected.Get("/admin/users/:id/role-changes", middleware.RequirePermission(messages, models.PermissionUsersManage), adminHandler.RoleHistory)
+	protected.Post("/invitations", middleware.RequirePermission(messages, models.PermissionUsersInvite), invitationHandler.Create)
+	protected.Post("/admin/clients", middleware.RequirePermission(messages, models.PermissionClientsManage), clientHandler.Create)
+	protected.Get("/admin/clients", middleware.RequirePermission(messages, models.PermissionClientsManage), clientHandler.List)
+	protected.Delete("/admin/clients/:id", middleware.RequirePermission(messages, models.PermissionClientsManage), clientHandler.Revoke)
 }
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/scim"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SCIMHandler обработчик SCIM 2.0 для синхронизации с кадровой системой.
+// Ответы и ошибки формируются по RFC 7644, чтобы их разбирали стандартные клиенты SCIM.
+type SCIMHandler struct {
+	scimService services.SCIMService
+	validator   *validators.AuthValidator
+	messages    lang.Messages
+}
+
+// NewSCIMHandler создает новый обработчик SCIM
+func NewSCIMHandler(scimService services.SCIMService, messages lang.Messages) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+		validator:   validators.NewAuthValidator(messages),
+		messages:    messages,
+	}
+}
+
+// ServiceProviderConfig возвращает поддерживаемые возможности протокола
+func (h *SCIMHandler) ServiceProviderConfig(c *fiber.Ctx) error {
+	return c.JSON(scim.NewServiceProviderConfig(), scim.ContentType)
+}
+
+// ListUsers ищет пользователей по фильтру
+func (h *SCIMHandler) ListUsers(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	response, err := h.scimService.ListUsers(c.Context(), h.listQuery(c))
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, response)
+}
+
+// GetUser возвращает пользователя
+func (h *SCIMHandler) GetUser(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	user, err := h.scimService.GetUser(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, user)
+}
+
+// CreateUser создает пользователя
+func (h *SCIMHandler) CreateUser(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	var req scim.User
+	if err := h.parse(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	user, err := h.scimService.CreateUser(c.Context(), &req)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusCreated, user)
+}
+
+// ReplaceUser заменяет пользователя целиком
+func (h *SCIMHandler) ReplaceUser(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	var req scim.User
+	if err := h.parse(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	user, err := h.scimService.ReplaceUser(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, user)
+}
+
+// PatchUser частично изменяет пользователя, в том числе деактивирует его
+func (h *SCIMHandler) PatchUser(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	var req scim.PatchRequest
+	if err := h.parse(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	user, err := h.scimService.PatchUser(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, user)
+}
+
+// DeleteUser удаляет пользователя
+func (h *SCIMHandler) DeleteUser(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	if err := h.scimService.DeleteUser(c.Context(), c.Params("id")); err != nil {
+		return h.fail(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListGroups ищет группы по фильтру
+func (h *SCIMHandler) ListGroups(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	response, err := h.scimService.ListGroups(c.Context(), h.listQuery(c))
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, response)
+}
+
+// GetGroup возвращает группу
+func (h *SCIMHandler) GetGroup(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	group, err := h.scimService.GetGroup(c.Context(), c.Params("id"), h.listQuery(c).ExcludeMembers)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, group)
+}
+
+// CreateGroup создает группу
+func (h *SCIMHandler) CreateGroup(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	var req scim.Group
+	if err := h.parse(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	group, err := h.scimService.CreateGroup(c.Context(), &req)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusCreated, group)
+}
+
+// ReplaceGroup заменяет группу целиком
+func (h *SCIMHandler) ReplaceGroup(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	var req scim.Group
+	if err := h.parse(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	group, err := h.scimService.ReplaceGroup(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, group)
+}
+
+// PatchGroup частично изменяет группу: название и состав
+func (h *SCIMHandler) PatchGroup(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	var req scim.PatchRequest
+	if err := h.parse(c, &req); err != nil {
+		return h.fail(c, err)
+	}
+
+	group, err := h.scimService.PatchGroup(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return h.fail(c, err)
+	}
+
+	return h.respond(c, fiber.StatusOK, group)
+}
+
+// DeleteGroup удаляет группу
+func (h *SCIMHandler) DeleteGroup(c *fiber.Ctx) error {
+	h.logRequest(c)
+
+	if err := h.scimService.DeleteGroup(c.Context(), c.Params("id")); err != nil {
+		return h.fail(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// listQuery разбирает параметры поиска из строки запроса
+func (h *SCIMHandler) listQuery(c *fiber.Ctx) scim.ListQuery {
+	return scim.NewListQuery(c.Query("filter"), c.Query("startIndex"), c.Query("count"), c.Query("excludedAttributes"))
+}
+
+// parse разбирает и валидирует тело запроса. Ошибка возвращается как *scim.Error.
+func (h *SCIMHandler) parse(c *fiber.Ctx, req interface{}) error {
+	// application/scim+json разбирается как JSON
+	if err := c.BodyParser(req); err != nil {
+		return scim.NewError(fiber.StatusBadRequest, scim.ErrorInvalidSyntax, h.messages.Get(lang.InvalidRequestFormat))
+	}
+
+	// Валидация
+	if err := h.validator.Validate(req); err != nil {
+		return scim.NewError(fiber.StatusBadRequest, scim.ErrorInvalidValue, err.Error())
+	}
+
+	return nil
+}
+
+// respond отправляет ресурс или страницу ресурсов с адресами ресурсов в meta.location
+func (h *SCIMHandler) respond(c *fiber.Ctx, status int, body interface{}) error {
+	switch body := body.(type) {
+	case *scim.ListResponse:
+		for _, resource := range body.Resources {
+			h.locate(c, resource)
+		}
+	default:
+		if location := h.locate(c, body); status == fiber.StatusCreated {
+			c.Location(location)
+		}
+	}
+
+	return c.Status(status).JSON(body, scim.ContentType)
+}
+
+// locate заполняет meta.location ресурса и возвращает адрес
+func (h *SCIMHandler) locate(c *fiber.Ctx, resource interface{}) string {
+	switch resource := resource.(type) {
+	case *scim.User:
+		resource.Meta.Location = c.BaseURL() + "/scim/v2/Users/" + resource.ID
+		return resource.Meta.Location
+	case *scim.Group:
+		resource.Meta.Location = c.BaseURL() + "/scim/v2/Groups/" + resource.ID
+		return resource.Meta.Location
+	}
+	return ""
+}
+
+// fail отвечает ошибкой SCIM: ошибки запроса со своим статусом, остальные - 500
+func (h *SCIMHandler) fail(c *fiber.Ctx, err error) error {
+	log.Printf(h.messages.Get(lang.LogSCIMRequestFailed), c.Method(), c.Path(), h.clientName(c), err)
+
+	var scimErr *scim.Error
+	if errors.As(err, &scimErr) {
+		return h.sendError(c, scimErr.Status, scimErr.Type, scimErr.Detail)
+	}
+	return h.sendError(c, fiber.StatusInternalServerError, "", h.messages.Get(lang.InternalServerError))
+}
+
+// sendError отправляет тело ошибки SCIM
+func (h *SCIMHandler) sendError(c *fiber.Ctx, status int, scimType, detail string) error {
+	return c.Status(status).JSON(scim.NewErrorResponse(status, scimType, detail), scim.ContentType)
+}
+
+// logRequest записывает в журнал запрос и API клиента кадровой системы
+func (h *SCIMHandler) logRequest(c *fiber.Ctx) {
+	log.Printf(h.messages.Get(lang.LogSCIMRequest), c.Method(), c.Path(), h.clientName(c), c.IP())
+}
+
+// clientName возвращает имя API клиента из токена
+func (h *SCIMHandler) clientName(c *fiber.Ctx) string {
+	if client, ok := middleware.GetClient(c); ok {
+		return client.Name
+	}
+	return ""
+}
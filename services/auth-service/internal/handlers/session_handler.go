@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SessionHandler обработчик запросов к сессиям текущего пользователя
+type SessionHandler struct {
+	sessionService services.SessionService
+	messages       lang.Messages
+}
+
+// NewSessionHandler создает новый обработчик сессий
+func NewSessionHandler(sessionService services.SessionService, messages lang.Messages) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		messages:       messages,
+	}
+}
+
+// List возвращает активные сессии текущего пользователя
+func (h *SessionHandler) List(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogSessionsRequest), clientIP)
+
+	tokenString, ok := c.Locals("token").(string)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	sessions, err := h.sessionService.List(c.Context(), tokenString)
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogListSessionsFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	return c.JSON(sessions)
+}
+
+// Revoke завершает сессию текущего пользователя на другом устройстве или текущую
+func (h *SessionHandler) Revoke(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogSessionsRequest), clientIP)
+
+	tokenString, ok := c.Locals("token").(string)
+	if !ok {
+		log.Printf(h.messages.Get(lang.LogGetMeFailed), clientIP)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": h.messages.Get(lang.TokenInvalid),
+		})
+	}
+
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	if err := h.sessionService.Revoke(c.Context(), tokenString, sessionID); err != nil {
+		log.Printf(h.messages.Get(lang.LogRevokeSessionFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.SessionRevoked),
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Cookie, связывающая вход через провайдер с браузером, который его начал.
+// Путь покрывает /sso/:provider/login и /sso/:provider/callback.
+const (
+	ssoStateCookie     = "sso_state"
+	ssoStateCookiePath = "/api/v1/sso"
+)
+
+// SSOHandler обработчик входа через внешние провайдеры удостоверений
+type SSOHandler struct {
+	ssoService services.SSOService
+	validator  *validators.AuthValidator
+	messages   lang.Messages
+}
+
+// NewSSOHandler создает новый обработчик входа через внешние провайдеры
+func NewSSOHandler(ssoService services.SSOService, messages lang.Messages) *SSOHandler {
+	return &SSOHandler{
+		ssoService: ssoService,
+		validator:  validators.NewAuthValidator(messages),
+		messages:   messages,
+	}
+}
+
+// Providers возвращает подключенные провайдеры входа
+func (h *SSOHandler) Providers(c *fiber.Ctx) error {
+	return c.JSON(h.ssoService.Providers())
+}
+
+// Login перенаправляет пользователя на страницу входа внешнего провайдера
+func (h *SSOHandler) Login(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogSSOLoginRequest), clientIP)
+
+	redirectTo, browserState, err := h.ssoService.Begin(c.Context(), c.Params("provider"))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogSSOLoginFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.setStateCookie(c, browserState, time.Time{})
+	return c.Redirect(redirectTo, fiber.StatusFound)
+}
+
+// Callback завершает вход по code и state, которые провайдер вернул на страницу фронтенда
+func (h *SSOHandler) Callback(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogSSOLoginRequest), clientIP)
+
+	var req requests.SSOCallbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	// Cookie нужна один раз: удаляем ее при любом исходе
+	req.BrowserState = c.Cookies(ssoStateCookie)
+	h.setStateCookie(c, "", time.Unix(0, 0))
+
+	response, err := h.ssoService.Complete(c.Context(), c.Params("provider"), &req, clientInfo(c))
+	if err != nil {
+		log.Printf(h.messages.Get(lang.LogSSOLoginFailed), clientIP, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(response)
+}
+
+// setStateCookie устанавливает или удаляет cookie со state входа. SameSite=Lax пропускает cookie
+// при возврате пользователя от провайдера, но не в запросах, отправленных с чужих сайтов.
+func (h *SSOHandler) setStateCookie(c *fiber.Ctx, value string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     ssoStateCookie,
+		Value:    value,
+		Path:     ssoStateCookiePath,
+		Expires:  expires,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/validators"
+	"github.com/gofiber/fiber/v2"
+)
+
+// VerificationHandler обработчик для подтверждения email
+type VerificationHandler struct {
+	verificationService services.EmailVerificationService
+	validator           *validators.AuthValidator
+	messages            lang.Messages
+}
+
+// NewVerificationHandler создает новый обработчик подтверждения email
+func NewVerificationHandler(verificationService services.EmailVerificationService, messages lang.Messages) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+		validator:           validators.NewAuthValidator(messages),
+		messages:            messages,
+	}
+}
+
+// VerifyEmail подтверждает email по токену из ссылки в письме
+func (h *VerificationHandler) VerifyEmail(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogVerifyEmailRequest), clientIP)
+
+	var req requests.VerifyEmailRequest
+	if err := c.QueryParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.verificationService.VerifyEmail(c.Context(), req.Token); err != nil {
+		log.Printf(h.messages.Get(lang.LogVerifyEmailFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.EmailVerified),
+	})
+}
+
+// ResendVerification повторно отправляет письмо подтверждения email
+func (h *VerificationHandler) ResendVerification(c *fiber.Ctx) error {
+	clientIP := c.IP()
+	log.Printf(h.messages.Get(lang.LogResendVerificationRequest), clientIP)
+
+	var req requests.ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogParseRequestFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InvalidRequestFormat),
+		})
+	}
+
+	// Валидация
+	if err := h.validator.Validate(&req); err != nil {
+		log.Printf(h.messages.Get(lang.LogValidationFailed), clientIP, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   h.messages.Get(lang.InvalidRequestFormat),
+			"details": err.Error(),
+		})
+	}
+
+	if err := h.verificationService.ResendVerification(c.Context(), &req); err != nil {
+		log.Printf(h.messages.Get(lang.LogResendVerificationFailed), clientIP, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": h.messages.Get(lang.InternalServerError),
+		})
+	}
+
+	// Ответ одинаковый независимо от существования аккаунта
+	return c.JSON(responses.MessageResponse{
+		Message: h.messages.Get(lang.EmailVerificationSent),
+	})
+}
@@ -0,0 +1,240 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// verificationKey публичный ключ проверки подписи вместе с алгоритмом
+type verificationKey struct {
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// KeySet набор ключей для подписи и проверки JWT.
+// Токены подписываются одним активным ключом, а проверяются любым из опубликованных,
+// что позволяет ротировать ключи без принудительного выхода пользователей.
+type KeySet struct {
+	signingKeyID  string
+	signingMethod jwt.SigningMethod
+	signingKey    interface{}
+	verification  map[string]verificationKey
+	messages      lang.Messages
+}
+
+// NewKeySet создает набор ключей по конфигурации JWT.
+// Если JWT_SIGNING_KEY_FILE не задан, используется HS256 с общим секретом JWT_SECRET.
+func NewKeySet(jwtConfig config.JWTConfig, messages lang.Messages) (*KeySet, error) {
+	if jwtConfig.SigningKeyFile == "" {
+		return &KeySet{
+			signingMethod: jwt.SigningMethodHS256,
+			signingKey:    []byte(jwtConfig.Secret),
+			messages:      messages,
+		}, nil
+	}
+
+	ks := &KeySet{
+		verification: make(map[string]verificationKey),
+		messages:     messages,
+	}
+
+	if err := ks.loadSigningKey(jwtConfig.SigningKeyFile, jwtConfig.SigningKeyID); err != nil {
+		return nil, err
+	}
+
+	for _, path := range jwtConfig.VerificationKeyFiles {
+		if err := ks.loadVerificationKey(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// SigningMethod возвращает алгоритм подписи активного ключа
+func (ks *KeySet) SigningMethod() jwt.SigningMethod {
+	return ks.signingMethod
+}
+
+// Sign подписывает токен активным ключом и проставляет заголовок kid
+func (ks *KeySet) Sign(token *jwt.Token) (string, error) {
+	if ks.signingKeyID != "" {
+		token.Header["kid"] = ks.signingKeyID
+	}
+	return token.SignedString(ks.signingKey)
+}
+
+// Keyfunc возвращает ключ проверки подписи по заголовку kid токена
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if ks.verification == nil {
+		return ks.signingKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.verification[kid]
+	if !ok {
+		log.Printf(ks.messages.Get(lang.LogJWTUnknownKeyID), kid)
+		return nil, errors.New(ks.messages.Get(lang.TokenInvalid))
+	}
+
+	// Алгоритм токена должен совпадать с алгоритмом ключа
+	if token.Method.Alg() != key.method.Alg() {
+		log.Printf(ks.messages.Get(lang.LogJWTUnknownKeyID), kid)
+		return nil, errors.New(ks.messages.Get(lang.TokenInvalid))
+	}
+
+	return key.public, nil
+}
+
+// ValidMethods возвращает список алгоритмов, допустимых при проверке токенов
+func (ks *KeySet) ValidMethods() []string {
+	if ks.verification == nil {
+		return []string{ks.signingMethod.Alg()}
+	}
+
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range ks.verification {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS возвращает публичные ключи проверки в формате JSON Web Key Set.
+// При подписи общим секретом набор пуст: секрет не публикуется.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for kid, key := range ks.verification {
+		if jwk, ok := newJWK(kid, key); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// loadSigningKey загружает приватный ключ подписи из PEM файла
+func (ks *KeySet) loadSigningKey(path, keyID string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ks.keyError(path, err)
+	}
+
+	var public crypto.PublicKey
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		ks.signingMethod = jwt.SigningMethodRS256
+		ks.signingKey = rsaKey
+		public = &rsaKey.PublicKey
+	} else if edKey, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		ks.signingMethod = jwt.SigningMethodEdDSA
+		ks.signingKey = edKey
+		public = edKey.(ed25519.PrivateKey).Public()
+	} else {
+		return ks.keyError(path, err)
+	}
+
+	key := verificationKey{method: ks.signingMethod, public: public}
+	if keyID == "" {
+		keyID = thumbprint(key)
+	}
+
+	ks.signingKeyID = keyID
+	ks.verification[keyID] = key
+	log.Printf(ks.messages.Get(lang.LogSigningKeyLoaded), keyID, ks.signingMethod.Alg())
+	return nil
+}
+
+// loadVerificationKey загружает дополнительный публичный ключ проверки из PEM файла.
+// Используется при ротации: предыдущий ключ подписи остается в списке,
+// пока не истекут все выпущенные им токены.
+func (ks *KeySet) loadVerificationKey(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ks.keyError(path, err)
+	}
+
+	var key verificationKey
+	if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		key = verificationKey{method: jwt.SigningMethodRS256, public: rsaKey}
+	} else if edKey, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		key = verificationKey{method: jwt.SigningMethodEdDSA, public: edKey}
+	} else {
+		return ks.keyError(path, err)
+	}
+
+	keyID := thumbprint(key)
+	ks.verification[keyID] = key
+	log.Printf(ks.messages.Get(lang.LogVerificationKeyLoaded), keyID, key.method.Alg())
+	return nil
+}
+
+// keyError формирует ошибку загрузки ключа
+func (ks *KeySet) keyError(path string, err error) error {
+	return fmt.Errorf("%s %s: %v", ks.messages.Get(lang.JWTSigningKeyInvalid), path, err)
+}
+
+// thumbprint вычисляет идентификатор ключа как JWK Thumbprint (RFC 7638)
+func thumbprint(key verificationKey) string {
+	jwk, _ := newJWK("", key)
+
+	// RFC 7638: только обязательные члены в лексикографическом порядке, без пробелов
+	var canonical []byte
+	switch jwk.Kty {
+	case "RSA":
+		canonical, _ = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N})
+	default:
+		canonical, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X})
+	}
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// newJWK преобразует публичный ключ в JWK
+func newJWK(kid string, key verificationKey) (JWK, bool) {
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(public),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
@@ -15,55 +15,338 @@ const (
 	DBConnected       MessageKey = "db.connected"
 
 	// Config messages
-	JWTSecretMissing  MessageKey = "config.jwt_secret.missing"
-	BCryptCostInvalid MessageKey = "config.bcrypt_cost.invalid"
+	JWTSecretMissing       MessageKey = "config.jwt_secret.missing"
+	BCryptCostInvalid      MessageKey = "config.bcrypt_cost.invalid"
+	JWTTTLInvalid          MessageKey = "config.jwt_ttl.invalid"
+	JWTSigningKeyInvalid   MessageKey = "config.jwt_signing_key.invalid"
+	MailSenderInvalid      MessageKey = "config.mail_sender.invalid"
+	LockoutConfigInvalid   MessageKey = "config.lockout.invalid"
+	RateLimitConfigInvalid MessageKey = "config.rate_limit.invalid"
+	ErasureConfigInvalid   MessageKey = "config.erasure.invalid"
+	RegistrationInvalid    MessageKey = "config.registration.invalid"
+	PasswordPolicyInvalid  MessageKey = "config.password_policy.invalid"
+	BreachedListInvalid    MessageKey = "config.breached_list.invalid"
+	PasswordHashInvalid    MessageKey = "config.password_hash.invalid"
+	OIDCConfigInvalid      MessageKey = "config.oidc.invalid"
+	CORSConfigInvalid      MessageKey = "config.cors.invalid"
+	SSOConfigInvalid       MessageKey = "config.sso.invalid"
+	LDAPConfigInvalid      MessageKey = "config.ldap.invalid"
 
 	// Auth messages
-	InvalidRequestFormat MessageKey = "auth.request.invalid_format"
-	UserAlreadyExists    MessageKey = "auth.user.already_exists"
-	InvalidCredentials   MessageKey = "auth.credentials.invalid"
-	TokenNotProvided     MessageKey = "auth.token.not_provided"
-	TokenInvalid         MessageKey = "auth.token.invalid"
-	UserNotFound         MessageKey = "auth.user.not_found"
-	InternalServerError  MessageKey = "auth.server.internal_error"
-	UserRegistered       MessageKey = "auth.user.registered"
-	UserLoggedIn         MessageKey = "auth.user.logged_in"
+	InvalidRequestFormat         MessageKey = "auth.request.invalid_format"
+	UserAlreadyExists            MessageKey = "auth.user.already_exists"
+	InvalidCredentials           MessageKey = "auth.credentials.invalid"
+	TokenNotProvided             MessageKey = "auth.token.not_provided"
+	TokenInvalid                 MessageKey = "auth.token.invalid"
+	UserNotFound                 MessageKey = "auth.user.not_found"
+	InternalServerError          MessageKey = "auth.server.internal_error"
+	UserRegistered               MessageKey = "auth.user.registered"
+	UserLoggedIn                 MessageKey = "auth.user.logged_in"
+	RefreshTokenInvalid          MessageKey = "auth.refresh_token.invalid"
+	RefreshTokenReused           MessageKey = "auth.refresh_token.reused"
+	TokenRevoked                 MessageKey = "auth.token.revoked"
+	LoggedOut                    MessageKey = "auth.user.logged_out"
+	RegistrationInviteOnly       MessageKey = "auth.registration.invite_only"
+	RegistrationDomainNotAllowed MessageKey = "auth.registration.domain_not_allowed"
+	RegistrationDomainDenied     MessageKey = "auth.registration.domain_denied"
+	LoggedOutEverywhere          MessageKey = "auth.user.logged_out_everywhere"
+	AccountLocked                MessageKey = "auth.account.locked"
+	ClientLocked                 MessageKey = "auth.client.locked"
+	AccountUnlocked              MessageKey = "auth.account.unlocked"
+	AccessDenied                 MessageKey = "auth.access.denied"
+	TooManyRequests              MessageKey = "auth.rate_limit.exceeded"
+
+	// Password reset messages
+	PasswordResetRequested    MessageKey = "password.reset.requested"
+	PasswordResetComplete     MessageKey = "password.reset.complete"
+	PasswordResetTokenInvalid MessageKey = "password.reset.token_invalid"
+	PasswordResetExternal     MessageKey = "password.reset.external"
+	PasswordResetEmailSubject MessageKey = "password.reset.email.subject"
+	PasswordResetEmailBody    MessageKey = "password.reset.email.body"
+	PasswordChanged           MessageKey = "password.change.complete"
+	CurrentPasswordInvalid    MessageKey = "password.change.current_invalid"
+
+	// Email verification messages
+	EmailVerified                 MessageKey = "email.verification.complete"
+	EmailVerificationSent         MessageKey = "email.verification.sent"
+	EmailVerificationTokenInvalid MessageKey = "email.verification.token_invalid"
+	EmailVerificationRequired     MessageKey = "email.verification.required"
+	EmailVerificationEmailSubject MessageKey = "email.verification.email.subject"
+	EmailVerificationEmailBody    MessageKey = "email.verification.email.body"
+
+	// MFA messages
+	MFAEnabled          MessageKey = "mfa.enabled"
+	MFAAlreadyEnabled   MessageKey = "mfa.already_enabled"
+	MFANotEnrolled      MessageKey = "mfa.not_enrolled"
+	MFACodeInvalid      MessageKey = "mfa.code.invalid"
+	MFAChallengeInvalid MessageKey = "mfa.challenge.invalid"
+
+	// User management messages
+	RoleChangeOwnForbidden MessageKey = "user.role.change_own_forbidden"
+	OwnAccountForbidden    MessageKey = "user.own_account_forbidden"
+	AccountSuspended       MessageKey = "user.account.suspended"
+	AccountDeactivated     MessageKey = "user.account.deactivated"
+	StatusReasonRequired   MessageKey = "user.status.reason_required"
+	UserDeleted            MessageKey = "user.deleted"
+	UserReset              MessageKey = "user.reset"
+
+	// Invitation messages
+	InvitationInvalid      MessageKey = "invitation.token_invalid"
+	InvitationRoleDenied   MessageKey = "invitation.role_denied"
+	InvitationEmailSubject MessageKey = "invitation.email.subject"
+	InvitationEmailBody    MessageKey = "invitation.email.body"
+
+	// Session messages
+	SessionNotFound MessageKey = "session.not_found"
+	SessionRevoked  MessageKey = "session.revoked"
+
+	// API client messages
+	APIClientNotFound                MessageKey = "client.not_found"
+	APIClientRevoked                 MessageKey = "client.revoked"
+	OAuthGrantTypeUnsupported        MessageKey = "oauth.grant_type.unsupported"
+	OAuthClientCredentialsMissing    MessageKey = "oauth.client.credentials_missing"
+	OAuthClientCredentialsDuplicated MessageKey = "oauth.client.credentials_duplicated"
+	OAuthClientInvalid               MessageKey = "oauth.client.invalid"
+	OAuthScopeInvalid                MessageKey = "oauth.scope.invalid"
+	OAuthClientNotAllowed            MessageKey = "oauth.client.not_allowed"
+	OAuthResponseTypeUnsupported     MessageKey = "oauth.response_type.unsupported"
+	OAuthOpenIDScopeMissing          MessageKey = "oauth.scope.openid_missing"
+	OAuthPKCERequired                MessageKey = "oauth.pkce.required"
+	OAuthCodeRequestInvalid          MessageKey = "oauth.code.request_invalid"
+	OAuthCodeInvalid                 MessageKey = "oauth.code.invalid"
+	OIDCClientInvalid                MessageKey = "oidc.client.invalid"
+
+	// SSO messages
+	SSOProviderNotFound MessageKey = "sso.provider.not_found"
+	SSOStateInvalid     MessageKey = "sso.state.invalid"
+	SSOLoginFailed      MessageKey = "sso.login.failed"
+	SSOProviderError    MessageKey = "sso.provider.error"
+	SSOIDTokenInvalid   MessageKey = "sso.id_token.invalid"
+	SSOEmailNotVerified MessageKey = "sso.email.not_verified"
+	SSOAccountNotFound  MessageKey = "sso.account.not_found"
+
+	// LDAP messages
+	LDAPError MessageKey = "ldap.error"
+
+	// SCIM messages
+	SCIMGroupNotFound      MessageKey = "scim.group.not_found"
+	SCIMGroupExists        MessageKey = "scim.group.exists"
+	SCIMMemberNotFound     MessageKey = "scim.group.member_not_found"
+	SCIMFilterInvalid      MessageKey = "scim.filter.invalid"
+	SCIMPatchInvalid       MessageKey = "scim.patch.invalid"
+	SCIMValueInvalid       MessageKey = "scim.value.invalid"
+	SCIMDeactivationReason MessageKey = "scim.deactivation.reason"
+	SCIMAdminForbidden     MessageKey = "scim.user.admin_forbidden"
+
+	// API key messages
+	APIKeyNotFound        MessageKey = "api_key.not_found"
+	APIKeyRevoked         MessageKey = "api_key.revoked"
+	APIKeyInvalid         MessageKey = "api_key.invalid"
+	APIKeyScopeDenied     MessageKey = "api_key.scope_denied"
+	APIKeyExpiryInvalid   MessageKey = "api_key.expiry_invalid"
+	APIKeyLimitReached    MessageKey = "api_key.limit_reached"
+	APIKeySessionRequired MessageKey = "api_key.session_required"
 
 	// Validation messages
-	ValidationFieldRequired MessageKey = "validation.field.required"
-	ValidationEmailInvalid  MessageKey = "validation.email.invalid"
-	ValidationPasswordMin   MessageKey = "validation.password.min"
-	ValidationRoleInvalid   MessageKey = "validation.role.invalid"
+	ValidationFieldRequired      MessageKey = "validation.field.required"
+	ValidationEmailInvalid       MessageKey = "validation.email.invalid"
+	ValidationPasswordMin        MessageKey = "validation.password.min"
+	ValidationRoleInvalid        MessageKey = "validation.role.invalid"
+	ValidationPasswordSame       MessageKey = "validation.password.same"
+	ValidationCodeFormat         MessageKey = "validation.code.format"
+	ValidationDateFormat         MessageKey = "validation.date.format"
+	ValidationValueMax           MessageKey = "validation.value.max"
+	ValidationEmailDomain        MessageKey = "validation.email.domain"
+	ValidationPasswordMax        MessageKey = "validation.password.max"
+	ValidationPasswordLower      MessageKey = "validation.password.lower"
+	ValidationPasswordUpper      MessageKey = "validation.password.upper"
+	ValidationPasswordDigit      MessageKey = "validation.password.digit"
+	ValidationPasswordSymbol     MessageKey = "validation.password.symbol"
+	ValidationPasswordEmail      MessageKey = "validation.password.email"
+	ValidationPasswordBreached   MessageKey = "validation.password.breached"
+	ValidationScopeInvalid       MessageKey = "validation.scope.invalid"
+	ValidationRedirectURIInvalid MessageKey = "validation.redirect_uri.invalid"
 
 	// Logging messages - Handler level
-	LogRegistrationRequest MessageKey = "log.registration.request"
-	LogLoginRequest        MessageKey = "log.login.request"
-	LogValidationFailed    MessageKey = "log.validation.failed"
-	LogRegistrationFailed  MessageKey = "log.registration.failed"
-	LogRegistrationSuccess MessageKey = "log.registration.success"
-	LogLoginFailed         MessageKey = "log.login.failed"
-	LogLoginSuccess        MessageKey = "log.login.success"
-	LogGetMeFailed         MessageKey = "log.getme.failed"
-	LogGetMeSuccess        MessageKey = "log.getme.success"
-	LogParseRequestFailed  MessageKey = "log.parse.request.failed"
+	LogRegistrationRequest       MessageKey = "log.registration.request"
+	LogLoginRequest              MessageKey = "log.login.request"
+	LogValidationFailed          MessageKey = "log.validation.failed"
+	LogRegistrationFailed        MessageKey = "log.registration.failed"
+	LogRegistrationSuccess       MessageKey = "log.registration.success"
+	LogLoginFailed               MessageKey = "log.login.failed"
+	LogLoginSuccess              MessageKey = "log.login.success"
+	LogGetMeFailed               MessageKey = "log.getme.failed"
+	LogGetMeSuccess              MessageKey = "log.getme.success"
+	LogParseRequestFailed        MessageKey = "log.parse.request.failed"
+	LogRefreshRequest            MessageKey = "log.refresh.request"
+	LogRefreshFailed             MessageKey = "log.refresh.failed"
+	LogRefreshSuccess            MessageKey = "log.refresh.success"
+	LogLogoutRequest             MessageKey = "log.logout.request"
+	LogLogoutFailed              MessageKey = "log.logout.failed"
+	LogLogoutSuccess             MessageKey = "log.logout.success"
+	LogLogoutAllSuccess          MessageKey = "log.logout_all.success"
+	LogForgotPasswordRequest     MessageKey = "log.password.forgot.request"
+	LogForgotPasswordFailed      MessageKey = "log.password.forgot.failed"
+	LogResetPasswordRequest      MessageKey = "log.password.reset.request"
+	LogResetPasswordFailed       MessageKey = "log.password.reset.failed"
+	LogResetPasswordSuccess      MessageKey = "log.password.reset.success"
+	LogChangePasswordRequest     MessageKey = "log.password.change.request"
+	LogChangePasswordFailed      MessageKey = "log.password.change.failed"
+	LogChangePasswordSuccess     MessageKey = "log.password.change.success"
+	LogVerifyEmailRequest        MessageKey = "log.email.verify.request"
+	LogVerifyEmailFailed         MessageKey = "log.email.verify.failed"
+	LogResendVerificationRequest MessageKey = "log.email.verify.resend.request"
+	LogResendVerificationFailed  MessageKey = "log.email.verify.resend.failed"
+	LogMFALoginRequest           MessageKey = "log.mfa.login.request"
+	LogMFALoginFailed            MessageKey = "log.mfa.login.failed"
+	LogMFALoginSuccess           MessageKey = "log.mfa.login.success"
+	LogMFAEnrollRequest          MessageKey = "log.mfa.enroll.request"
+	LogMFAEnrollFailed           MessageKey = "log.mfa.enroll.failed"
+	LogMFAConfirmRequest         MessageKey = "log.mfa.confirm.request"
+	LogMFAConfirmFailed          MessageKey = "log.mfa.confirm.failed"
+	LogUnlockRequest             MessageKey = "log.admin.unlock.request"
+	LogUnlockFailed              MessageKey = "log.admin.unlock.failed"
+	LogUnlockSuccess             MessageKey = "log.admin.unlock.success"
+	LogChangeRoleRequest         MessageKey = "log.admin.role.change.request"
+	LogChangeRoleFailed          MessageKey = "log.admin.role.change.failed"
+	LogRoleHistoryFailed         MessageKey = "log.admin.role.history.failed"
+	LogAdminUserRequest          MessageKey = "log.admin.user.request"
+	LogListUsersFailed           MessageKey = "log.admin.users.list.failed"
+	LogGetUserFailed             MessageKey = "log.admin.user.get.failed"
+	LogChangeStatusFailed        MessageKey = "log.admin.user.status.failed"
+	LogDeleteUserFailed          MessageKey = "log.admin.user.delete.failed"
+	LogResetUserFailed           MessageKey = "log.admin.user.reset.failed"
+	LogExportRequest             MessageKey = "log.account.export.request"
+	LogExportFailed              MessageKey = "log.account.export.failed"
+	LogCreateInvitationRequest   MessageKey = "log.invitation.create.request"
+	LogCreateInvitationFailed    MessageKey = "log.invitation.create.failed"
+	LogAcceptInvitationRequest   MessageKey = "log.invitation.accept.request"
+	LogAcceptInvitationFailed    MessageKey = "log.invitation.accept.failed"
+	LogAcceptInvitationSuccess   MessageKey = "log.invitation.accept.success"
+	LogSessionsRequest           MessageKey = "log.sessions.request"
+	LogListSessionsFailed        MessageKey = "log.sessions.list.failed"
+	LogRevokeSessionFailed       MessageKey = "log.sessions.revoke.failed"
+	LogOAuthTokenRequest         MessageKey = "log.oauth.token.request"
+	LogOAuthTokenFailed          MessageKey = "log.oauth.token.failed"
+	LogAPIClientFailed           MessageKey = "log.admin.client.failed"
+	LogAPIKeysRequest            MessageKey = "log.api_keys.request"
+	LogAPIKeyFailed              MessageKey = "log.api_keys.failed"
+	LogOIDCAuthorizeRequest      MessageKey = "log.oidc.authorize.request"
+	LogOIDCAuthorizeFailed       MessageKey = "log.oidc.authorize.failed"
+	LogUserInfoRequest           MessageKey = "log.oidc.userinfo.request"
+	LogUserInfoFailed            MessageKey = "log.oidc.userinfo.failed"
+	LogSSOLoginRequest           MessageKey = "log.sso.login.request"
+	LogSSOLoginFailed            MessageKey = "log.sso.login.failed"
+	LogSCIMRequest               MessageKey = "log.scim.request"
+	LogSCIMRequestFailed         MessageKey = "log.scim.request.failed"
 
 	// Logging messages - Service level
-	LogAttemptingRegistration MessageKey = "log.service.attempting.registration"
-	LogCheckEmailExists       MessageKey = "log.service.check.email.exists"
-	LogEmailAlreadyExists     MessageKey = "log.service.email.already.exists"
-	LogPasswordHashError      MessageKey = "log.service.password.hash.error"
-	LogUserCreateError        MessageKey = "log.service.user.create.error"
-	LogJWTGenerateError       MessageKey = "log.service.jwt.generate.error"
-	LogRegistrationComplete   MessageKey = "log.service.registration.complete"
-	LogAttemptingLogin        MessageKey = "log.service.attempting.login"
-	LogDatabaseErrorLogin     MessageKey = "log.service.database.error.login"
-	LogUserNotFoundLogin      MessageKey = "log.service.user.not.found.login"
-	LogInvalidPassword        MessageKey = "log.service.invalid.password"
-	LogLoginComplete          MessageKey = "log.service.login.complete"
-	LogJWTParseError          MessageKey = "log.service.jwt.parse.error"
-	LogJWTInvalid             MessageKey = "log.service.jwt.invalid"
-	LogUserFetchError         MessageKey = "log.service.user.fetch.error"
-	LogUserNotFoundValidation MessageKey = "log.service.user.not.found.validation"
+	LogAttemptingRegistration    MessageKey = "log.service.attempting.registration"
+	LogCheckEmailExists          MessageKey = "log.service.check.email.exists"
+	LogEmailAlreadyExists        MessageKey = "log.service.email.already.exists"
+	LogPasswordHashError         MessageKey = "log.service.password.hash.error"
+	LogUserCreateError           MessageKey = "log.service.user.create.error"
+	LogJWTGenerateError          MessageKey = "log.service.jwt.generate.error"
+	LogRegistrationComplete      MessageKey = "log.service.registration.complete"
+	LogRegistrationRejected      MessageKey = "log.service.registration.rejected"
+	LogRegistrationDomainDenied  MessageKey = "log.service.registration.domain_denied"
+	LogAttemptingLogin           MessageKey = "log.service.attempting.login"
+	LogDatabaseErrorLogin        MessageKey = "log.service.database.error.login"
+	LogUserNotFoundLogin         MessageKey = "log.service.user.not.found.login"
+	LogInvalidPassword           MessageKey = "log.service.invalid.password"
+	LogLoginComplete             MessageKey = "log.service.login.complete"
+	LogJWTParseError             MessageKey = "log.service.jwt.parse.error"
+	LogJWTInvalid                MessageKey = "log.service.jwt.invalid"
+	LogUserFetchError            MessageKey = "log.service.user.fetch.error"
+	LogUserNotFoundValidation    MessageKey = "log.service.user.not.found.validation"
+	LogRefreshTokenIssueError    MessageKey = "log.service.refresh_token.issue.error"
+	LogRefreshTokenNotFound      MessageKey = "log.service.refresh_token.not.found"
+	LogRefreshTokenExpired       MessageKey = "log.service.refresh_token.expired"
+	LogRefreshTokenReuse         MessageKey = "log.service.refresh_token.reuse"
+	LogRefreshComplete           MessageKey = "log.service.refresh.complete"
+	LogJWTRevoked                MessageKey = "log.service.jwt.revoked"
+	LogTokenRevoked              MessageKey = "log.service.token.revoked"
+	LogUserTokensRevoked         MessageKey = "log.service.user.tokens.revoked"
+	LogRevocationSyncError       MessageKey = "log.service.revocation.sync.error"
+	LogLogoutError               MessageKey = "log.service.logout.error"
+	LogLogoutComplete            MessageKey = "log.service.logout.complete"
+	LogLogoutAllComplete         MessageKey = "log.service.logout_all.complete"
+	LogPasswordResetRequested    MessageKey = "log.service.password_reset.requested"
+	LogPasswordResetSent         MessageKey = "log.service.password_reset.sent"
+	LogPasswordResetTokenInvalid MessageKey = "log.service.password_reset.token.invalid"
+	LogPasswordResetComplete     MessageKey = "log.service.password_reset.complete"
+	LogPasswordResetExternal     MessageKey = "log.service.password_reset.external"
+	LogPasswordChangeComplete    MessageKey = "log.service.password_change.complete"
+	LogEmailVerificationSent     MessageKey = "log.service.email_verification.sent"
+	LogEmailVerificationInvalid  MessageKey = "log.service.email_verification.token.invalid"
+	LogEmailVerificationComplete MessageKey = "log.service.email_verification.complete"
+	LogEmailNotVerified          MessageKey = "log.service.email_verification.required"
+	LogMFAChallengeIssued        MessageKey = "log.service.mfa.challenge.issued"
+	LogMFAChallengeInvalid       MessageKey = "log.service.mfa.challenge.invalid"
+	LogMFACodeInvalid            MessageKey = "log.service.mfa.code.invalid"
+	LogMFAEnrollmentStarted      MessageKey = "log.service.mfa.enrollment.started"
+	LogMFAEnabled                MessageKey = "log.service.mfa.enabled"
+	LogMFARecoveryCodeUsed       MessageKey = "log.service.mfa.recovery_code.used"
+	LogLoginLocked               MessageKey = "log.service.login.locked"
+	LogLoginLockApplied          MessageKey = "log.service.login.lock.applied"
+	LogLoginUnlocked             MessageKey = "log.service.login.unlocked"
+	LogRoleChanged               MessageKey = "log.service.role.changed"
+	LogUserStatusChanged         MessageKey = "log.service.user.status.changed"
+	LogUserDeleted               MessageKey = "log.service.user.deleted"
+	LogUserReset                 MessageKey = "log.service.user.reset"
+	LogLoginInactive             MessageKey = "log.service.login.inactive"
+	LogInvitationSent            MessageKey = "log.service.invitation.sent"
+	LogInvitationInvalid         MessageKey = "log.service.invitation.invalid"
+	LogInvitationAccepted        MessageKey = "log.service.invitation.accepted"
+	LogAccountExported           MessageKey = "log.service.account.exported"
+	LogErasureComplete           MessageKey = "log.service.erasure.complete"
+	LogErasureFailed             MessageKey = "log.service.erasure.failed"
+	LogErasureBatch              MessageKey = "log.service.erasure.batch"
+	LogPasswordRehashed          MessageKey = "log.service.password.rehashed"
+	LogPasswordRehashFailed      MessageKey = "log.service.password.rehash_failed"
+	LogSessionStarted            MessageKey = "log.service.session.started"
+	LogSessionRevoked            MessageKey = "log.service.session.revoked"
+	LogSessionRevokedToken       MessageKey = "log.service.session.revoked_token"
+	LogAPIClientCreated          MessageKey = "log.service.client.created"
+	LogAPIClientRevoked          MessageKey = "log.service.client.revoked"
+	LogAPIClientInactive         MessageKey = "log.service.client.inactive"
+	LogClientTokenIssued         MessageKey = "log.service.client.token_issued"
+	LogClientScopeDenied         MessageKey = "log.service.client.scope_denied"
+	LogClientAuthFailed          MessageKey = "log.service.client.auth_failed"
+	LogJWTClientToken            MessageKey = "log.service.jwt.client_token"
+	LogAPIKeyCreated             MessageKey = "log.service.api_key.created"
+	LogAPIKeyRevoked             MessageKey = "log.service.api_key.revoked"
+	LogAPIKeyRejected            MessageKey = "log.service.api_key.rejected"
+	LogAPIKeyTouchFailed         MessageKey = "log.service.api_key.touch_failed"
+	LogOIDCCodeIssued            MessageKey = "log.service.oidc.code_issued"
+	LogOIDCCodeInvalid           MessageKey = "log.service.oidc.code_invalid"
+	LogOIDCPKCEFailed            MessageKey = "log.service.oidc.pkce_failed"
+	LogOIDCTokensIssued          MessageKey = "log.service.oidc.tokens_issued"
+	LogOIDCSymmetricKey          MessageKey = "log.service.oidc.symmetric_key"
+	LogSSOStateInvalid           MessageKey = "log.service.sso.state_invalid"
+	LogSSOBrowserMismatch        MessageKey = "log.service.sso.browser_mismatch"
+	LogSSOExchangeFailed         MessageKey = "log.service.sso.exchange_failed"
+	LogSSOIdentityLinked         MessageKey = "log.service.sso.identity_linked"
+	LogSSOUserProvisioned        MessageKey = "log.service.sso.user_provisioned"
+	LogSSOLoginComplete          MessageKey = "log.service.sso.login_complete"
+	LogSSOAccountNotFound        MessageKey = "log.service.sso.account_not_found"
+	LogSSOProviderLoaded         MessageKey = "log.service.sso.provider_loaded"
+	LogAuthenticatorFailed       MessageKey = "log.service.authenticator.failed"
+	LogLDAPUserNotFound          MessageKey = "log.service.ldap.user_not_found"
+	LogLDAPAmbiguousUser         MessageKey = "log.service.ldap.ambiguous_user"
+	LogLDAPInvalidPassword       MessageKey = "log.service.ldap.invalid_password"
+	LogLDAPAccessDenied          MessageKey = "log.service.ldap.access_denied"
+	LogLDAPUserProvisioned       MessageKey = "log.service.ldap.user_provisioned"
+	LogLDAPRoleSynced            MessageKey = "log.service.ldap.role_synced"
+	LogSCIMUserProvisioned       MessageKey = "log.service.scim.user_provisioned"
+	LogSCIMUserUpdated           MessageKey = "log.service.scim.user_updated"
+	LogSCIMUserDeactivated       MessageKey = "log.service.scim.user_deactivated"
+	LogSCIMUserReactivated       MessageKey = "log.service.scim.user_reactivated"
+	LogSCIMUserDeleted           MessageKey = "log.service.scim.user_deleted"
+	LogSCIMAdminForbidden        MessageKey = "log.service.scim.admin_forbidden"
+	LogSCIMGroupCreated          MessageKey = "log.service.scim.group_created"
+	LogSCIMGroupUpdated          MessageKey = "log.service.scim.group_updated"
+	LogSCIMGroupDeleted          MessageKey = "log.service.scim.group_deleted"
 
 	// Logging messages - Repository level
 	LogUserCreateSuccess MessageKey = "log.repo.user.create.success"
@@ -72,11 +355,58 @@ const (
 	LogDatabaseError     MessageKey = "log.repo.database.error"
 	LogEmailExistsCheck  MessageKey = "log.repo.email.exists.check"
 
+	LogRefreshTokenCreateFailed MessageKey = "log.repo.refresh_token.create.failed"
+	LogRefreshTokenDBError      MessageKey = "log.repo.refresh_token.database.error"
+	LogRefreshFamilyRevoked     MessageKey = "log.repo.refresh_token.family.revoked"
+	LogRevocationDBError        MessageKey = "log.repo.revocation.database.error"
+	LogPasswordResetDBError     MessageKey = "log.repo.password_reset.database.error"
+	LogPasswordUpdated          MessageKey = "log.repo.user.password.updated"
+	LogEmailVerificationDBError MessageKey = "log.repo.email_verification.database.error"
+	LogEmailMarkedVerified      MessageKey = "log.repo.user.email.verified"
+	LogMFADBError               MessageKey = "log.repo.mfa.database.error"
+	LogLoginAttemptDBError      MessageKey = "log.repo.login_attempt.database.error"
+	LogRoleUpdated              MessageKey = "log.repo.user.role.updated"
+	LogUserStatusUpdated        MessageKey = "log.repo.user.status.updated"
+	LogUserRemoved              MessageKey = "log.repo.user.removed"
+	LogUserErased               MessageKey = "log.repo.user.erased"
+	LogMFARemoved               MessageKey = "log.repo.mfa.removed"
+	LogRoleChangeDBError        MessageKey = "log.repo.role_change.database.error"
+	LogInvitationDBError        MessageKey = "log.repo.invitation.database.error"
+	LogSessionDBError           MessageKey = "log.repo.session.database.error"
+	LogAPIClientDBError         MessageKey = "log.repo.client.database.error"
+	LogAPIKeyDBError            MessageKey = "log.repo.api_key.database.error"
+	LogAuthorizationCodeDBError MessageKey = "log.repo.authorization_code.database.error"
+	LogSSOStateDBError          MessageKey = "log.repo.sso_state.database.error"
+	LogUserIdentityDBError      MessageKey = "log.repo.user_identity.database.error"
+	LogGroupDBError             MessageKey = "log.repo.group.database.error"
+	LogEmailUpdated             MessageKey = "log.repo.user.email.updated"
+
 	// Logging messages - Middleware level
-	LogJWTMissingHeader     MessageKey = "log.jwt.missing.header"
-	LogJWTInvalidFormat     MessageKey = "log.jwt.invalid.format"
-	LogJWTValidationFailed  MessageKey = "log.jwt.validation.failed"
-	LogJWTValidationSuccess MessageKey = "log.jwt.validation.success"
+	LogJWTMissingHeader           MessageKey = "log.jwt.missing.header"
+	LogJWTInvalidFormat           MessageKey = "log.jwt.invalid.format"
+	LogJWTValidationFailed        MessageKey = "log.jwt.validation.failed"
+	LogJWTValidationSuccess       MessageKey = "log.jwt.validation.success"
+	LogJWTMissingUser             MessageKey = "log.jwt.missing.user"
+	LogAuthorizationDenied        MessageKey = "log.authorization.denied"
+	LogScopeDenied                MessageKey = "log.authorization.scope_denied"
+	LogJWTClientValidationSuccess MessageKey = "log.jwt.client.validation.success"
+	LogAPIKeyValidationSuccess    MessageKey = "log.jwt.api_key.validation.success"
+	LogAPIKeySessionRequired      MessageKey = "log.authorization.api_key.session_required"
+	LogRateLimitExceeded          MessageKey = "log.rate_limit.exceeded"
+
+	// Logging messages - Keys
+	LogSigningKeyLoaded      MessageKey = "log.keys.signing.loaded"
+	LogVerificationKeyLoaded MessageKey = "log.keys.verification.loaded"
+	LogJWTUnknownKeyID       MessageKey = "log.keys.unknown.kid"
+
+	// Logging messages - Passwords
+	LogBreachedListLoaded  MessageKey = "log.passwords.breached.loaded"
+	LogBreachedCheckFailed MessageKey = "log.passwords.breached.check_failed"
+
+	// Logging messages - Mail
+	LogMailLogged    MessageKey = "log.mail.logged"
+	LogMailWritten   MessageKey = "log.mail.written"
+	LogMailSendError MessageKey = "log.mail.send.error"
 )
 
 // Messages интерфейс для получения сообщений
@@ -118,6 +448,36 @@ func (m *MessageProvider) GetValidationError(field, tag, param string) string {
 		return m.Get(ValidationPasswordMin) + ": " + field + " (мин. " + param + " символов)"
 	case "oneof":
 		return m.Get(ValidationRoleInvalid) + ": " + field
+	case "nefield":
+		return m.Get(ValidationPasswordSame) + ": " + field
+	case "datetime":
+		return m.Get(ValidationDateFormat) + ": " + field
+	case "max":
+		return m.Get(ValidationValueMax) + ": " + field + " (макс. " + param + ")"
+	case "password_min":
+		return m.Get(ValidationPasswordMin) + ": " + field + " (мин. " + param + " символов)"
+	case "password_max":
+		return m.Get(ValidationPasswordMax) + ": " + field + " (макс. " + param + " байт)"
+	case "password_lower":
+		return m.Get(ValidationPasswordLower) + ": " + field
+	case "password_upper":
+		return m.Get(ValidationPasswordUpper) + ": " + field
+	case "password_digit":
+		return m.Get(ValidationPasswordDigit) + ": " + field
+	case "password_symbol":
+		return m.Get(ValidationPasswordSymbol) + ": " + field
+	case "password_email":
+		return m.Get(ValidationPasswordEmail) + ": " + field
+	case "password_breached":
+		return m.Get(ValidationPasswordBreached) + ": " + field
+	case "corporate_email":
+		return m.Get(ValidationEmailDomain) + ": " + field
+	case "client_scopes":
+		return m.Get(ValidationScopeInvalid) + ": " + field
+	case "redirect_uri":
+		return m.Get(ValidationRedirectURIInvalid) + ": " + field
+	case "len", "numeric":
+		return m.Get(ValidationCodeFormat) + ": " + field
 	default:
 		return "Ошибка валидации поля: " + field
 	}
@@ -11,55 +11,336 @@ func NewRussianMessages() lang.Messages {
 		lang.DBConnected:       "✅ Подключение к PostgreSQL успешно",
 
 		// Config
-		lang.JWTSecretMissing:  "JWT_SECRET не установлен",
-		lang.BCryptCostInvalid: "Неверное значение BCRYPT_COST",
+		lang.JWTSecretMissing:       "JWT_SECRET не установлен",
+		lang.BCryptCostInvalid:      "Неверное значение BCRYPT_COST",
+		lang.JWTTTLInvalid:          "Неверное время жизни JWT токенов",
+		lang.JWTSigningKeyInvalid:   "Не удалось загрузить ключ JWT",
+		lang.MailSenderInvalid:      "Неверное значение MAIL_SENDER (допустимо: log, file)",
+		lang.LockoutConfigInvalid:   "Неверная настройка защиты от перебора паролей (LOCKOUT_*)",
+		lang.RateLimitConfigInvalid: "Неверная настройка ограничения частоты запросов (RATE_LIMIT_*)",
+		lang.ErasureConfigInvalid:   "Неверная настройка удаления персональных данных (ERASURE_*)",
+		lang.RegistrationInvalid:    "Неверная настройка регистрации (REGISTRATION_*)",
+		lang.PasswordPolicyInvalid:  "Неверная настройка политики паролей (PASSWORD_*)",
+		lang.BreachedListInvalid:    "Не удалось загрузить список утекших паролей (PASSWORD_BREACHED_LIST_FILE)",
+		lang.PasswordHashInvalid:    "Неверная настройка хеширования паролей (PASSWORD_HASH_ALGORITHM, ARGON2_*)",
+		lang.OIDCConfigInvalid:      "Неверная настройка OpenID Connect (OIDC_*)",
+		lang.CORSConfigInvalid:      "Неверная настройка источников фронтенда (CORS_ALLOWED_ORIGINS)",
+		lang.SSOConfigInvalid:       "Неверная настройка входа через внешний провайдер (SSO_*)",
+		lang.LDAPConfigInvalid:      "Неверная настройка входа через каталог LDAP (LDAP_*)",
 
 		// Auth
-		lang.InvalidRequestFormat: "Неверный формат запроса",
-		lang.UserAlreadyExists:    "Пользователь с таким email уже существует",
-		lang.InvalidCredentials:   "Неверный email или пароль",
-		lang.TokenNotProvided:     "Токен не предоставлен",
-		lang.TokenInvalid:         "Недействительный токен",
-		lang.UserNotFound:         "Пользователь не найден",
-		lang.InternalServerError:  "Внутренняя ошибка сервера",
-		lang.UserRegistered:       "✅ Новый пользователь зарегистрирован",
-		lang.UserLoggedIn:         "✅ Пользователь вошел в систему",
+		lang.InvalidRequestFormat:         "Неверный формат запроса",
+		lang.UserAlreadyExists:            "Пользователь с таким email уже существует",
+		lang.InvalidCredentials:           "Неверный email или пароль",
+		lang.TokenNotProvided:             "Токен не предоставлен",
+		lang.TokenInvalid:                 "Недействительный токен",
+		lang.UserNotFound:                 "Пользователь не найден",
+		lang.InternalServerError:          "Внутренняя ошибка сервера",
+		lang.UserRegistered:               "✅ Новый пользователь зарегистрирован",
+		lang.UserLoggedIn:                 "✅ Пользователь вошел в систему",
+		lang.RefreshTokenInvalid:          "Недействительный refresh токен",
+		lang.RefreshTokenReused:           "Refresh токен уже был использован, все сессии этой цепочки отозваны",
+		lang.TokenRevoked:                 "Токен отозван",
+		lang.LoggedOut:                    "Вы вышли из системы",
+		lang.RegistrationInviteOnly:       "Регистрация доступна только по приглашению",
+		lang.RegistrationDomainNotAllowed: "Регистрация доступна только с корпоративного email",
+		lang.RegistrationDomainDenied:     "Регистрация с адресов этого домена запрещена",
+		lang.LoggedOutEverywhere:          "Вы вышли из системы на всех устройствах",
+		lang.AccountLocked:                "Слишком много неудачных попыток входа. Аккаунт временно заблокирован, повторите через %d мин.",
+		lang.ClientLocked:                 "Слишком много неудачных попыток входа с вашего адреса. Повторите через %d мин.",
+		lang.AccountUnlocked:              "Блокировка входа снята",
+		lang.AccessDenied:                 "Недостаточно прав для выполнения операции",
+		lang.TooManyRequests:              "Слишком много запросов. Повторите через %d сек.",
+
+		// Password reset
+		lang.PasswordResetRequested:    "Если аккаунт с таким email существует, мы отправили на него ссылку для сброса пароля",
+		lang.PasswordResetComplete:     "Пароль успешно изменен. Войдите с новым паролем",
+		lang.PasswordResetTokenInvalid: "Ссылка для сброса пароля недействительна или устарела",
+		lang.PasswordResetExternal:     "Учетная запись входит через корпоративный каталог или SSO, пароль меняется там",
+		lang.PasswordChanged:           "Пароль изменен. Войдите заново на всех устройствах",
+		lang.CurrentPasswordInvalid:    "Неверный текущий пароль",
+		lang.PasswordResetEmailSubject: "Сброс пароля на Портале Обучения",
+		lang.PasswordResetEmailBody:    "Здравствуйте!\n\nМы получили запрос на сброс пароля для вашего аккаунта.\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действительна %d мин. и может быть использована только один раз.\nЕсли вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+
+		// Email verification messages
+		lang.EmailVerified:                 "Email подтвержден",
+		lang.EmailVerificationSent:         "Если аккаунт с таким email существует и еще не подтвержден, мы отправили на него новую ссылку",
+		lang.EmailVerificationTokenInvalid: "Ссылка для подтверждения email недействительна или устарела",
+		lang.EmailVerificationRequired:     "Подтвердите email по ссылке из письма, чтобы войти",
+		lang.EmailVerificationEmailSubject: "Подтверждение email на Портале Обучения",
+		lang.EmailVerificationEmailBody:    "Здравствуйте!\n\nЧтобы подтвердить адрес электронной почты, перейдите по ссылке:\n%s\n\nСсылка действительна %d ч. и может быть использована только один раз.\nЕсли вы не регистрировались на Портале Обучения, просто проигнорируйте это письмо.",
+
+		// MFA messages
+		lang.MFAEnabled:          "Двухфакторная аутентификация включена. Сохраните коды восстановления - они показываются один раз",
+		lang.MFAAlreadyEnabled:   "Двухфакторная аутентификация уже включена",
+		lang.MFANotEnrolled:      "Двухфакторная аутентификация не настроена",
+		lang.MFACodeInvalid:      "Неверный код подтверждения",
+		lang.MFAChallengeInvalid: "Сессия входа истекла. Войдите заново",
+
+		// User management
+		lang.RoleChangeOwnForbidden: "Нельзя изменить собственную роль",
+		lang.OwnAccountForbidden:    "Нельзя выполнить эту операцию над собственной учетной записью",
+		lang.AccountSuspended:       "Учетная запись приостановлена. Обратитесь к администратору",
+		lang.AccountDeactivated:     "Учетная запись деактивирована",
+		lang.StatusReasonRequired:   "Укажите причину приостановки или деактивации",
+		lang.UserDeleted:            "Пользователь удален",
+		lang.UserReset:              "Пароль сброшен, сессии завершены. Пользователю отправлена ссылка для установки нового пароля",
+
+		// Invitations
+		lang.InvitationInvalid:      "Приглашение недействительно или устарело",
+		lang.InvitationRoleDenied:   "Недостаточно прав для приглашения с этой ролью",
+		lang.InvitationEmailSubject: "Приглашение на Портал Обучения",
+		lang.InvitationEmailBody:    "Здравствуйте!\n\n%s приглашает вас на Портал Обучения с ролью %s.\nЧтобы создать аккаунт, перейдите по ссылке и задайте пароль:\n%s\n\nСсылка действительна %d ч. и может быть использована только один раз.\nЕсли вы не ждали приглашения, просто проигнорируйте это письмо.",
+
+		// Sessions
+		lang.SessionNotFound: "Сессия не найдена",
+		lang.SessionRevoked:  "Сессия завершена",
+
+		// API clients
+		lang.APIClientNotFound:                "API клиент не найден",
+		lang.APIClientRevoked:                 "API клиент отозван",
+		lang.OAuthGrantTypeUnsupported:        "Поддерживается только grant_type=client_credentials",
+		lang.OAuthClientCredentialsMissing:    "Не переданы client_id и client_secret",
+		lang.OAuthClientCredentialsDuplicated: "Данные клиента переданы одновременно в заголовке Authorization и в теле запроса",
+		lang.OAuthClientInvalid:               "Неверный client_id или client_secret",
+		lang.OAuthScopeInvalid:                "Запрошены права, не выданные клиенту",
+		lang.OAuthClientNotAllowed:            "Клиенту не разрешен этот способ получения токена",
+		lang.OAuthResponseTypeUnsupported:     "Поддерживается только response_type=code",
+		lang.OAuthOpenIDScopeMissing:          "Запрос должен содержать scope openid",
+		lang.OAuthPKCERequired:                "Требуется code_challenge с методом S256",
+		lang.OAuthCodeRequestInvalid:          "Укажите code, redirect_uri и code_verifier",
+		lang.OAuthCodeInvalid:                 "Код авторизации недействителен, истек или уже использован",
+		lang.OIDCClientInvalid:                "Неизвестный клиент или незарегистрированный redirect_uri",
+
+		lang.SSOProviderNotFound: "Провайдер входа не найден",
+		lang.SSOStateInvalid:     "Вход через внешний провайдер истек или уже завершен, начните вход заново",
+		lang.SSOLoginFailed:      "Не удалось войти через внешний провайдер",
+		lang.SSOProviderError:    "Ошибка обращения к внешнему провайдеру входа",
+		lang.SSOIDTokenInvalid:   "Недействительный ID токен внешнего провайдера",
+		lang.SSOEmailNotVerified: "Внешний провайдер не подтвердил email учетной записи",
+		lang.SSOAccountNotFound:  "Учетная запись не найдена, обратитесь к администратору",
+
+		// LDAP messages
+		lang.LDAPError: "Ошибка обращения к каталогу LDAP",
+
+		// SCIM messages
+		lang.SCIMGroupNotFound:      "Группа не найдена",
+		lang.SCIMGroupExists:        "Группа с таким displayName уже существует",
+		lang.SCIMMemberNotFound:     "Участник группы не найден",
+		lang.SCIMFilterInvalid:      "Фильтр не поддерживается: допустимо только условие вида attribute eq \"value\"",
+		lang.SCIMPatchInvalid:       "Некорректная операция PATCH",
+		lang.SCIMValueInvalid:       "Некорректное значение атрибута",
+		lang.SCIMDeactivationReason: "Деактивирована кадровой системой через SCIM",
+		lang.SCIMAdminForbidden:     "Учетные записи администраторов не изменяются через SCIM",
+
+		lang.APIKeyNotFound:        "API ключ не найден",
+		lang.APIKeyRevoked:         "API ключ отозван",
+		lang.APIKeyInvalid:         "Недействительный API ключ",
+		lang.APIKeyScopeDenied:     "Ключу можно выдать только права вашей роли",
+		lang.APIKeyExpiryInvalid:   "Срок действия ключа должен быть в будущем",
+		lang.APIKeyLimitReached:    "Достигнуто максимальное число API ключей, отзовите неиспользуемые",
+		lang.APIKeySessionRequired: "Это действие недоступно по API ключу, войдите в систему",
 
 		// Validation
-		lang.ValidationFieldRequired: "Поле обязательно для заполнения",
-		lang.ValidationEmailInvalid:  "Поле должно быть действительным email адресом",
-		lang.ValidationPasswordMin:   "Поле должно содержать минимум символов",
-		lang.ValidationRoleInvalid:   "Поле должно быть одним из разрешенных значений",
+		lang.ValidationFieldRequired:      "Поле обязательно для заполнения",
+		lang.ValidationEmailInvalid:       "Поле должно быть действительным email адресом",
+		lang.ValidationPasswordMin:        "Поле должно содержать минимум символов",
+		lang.ValidationRoleInvalid:        "Поле должно быть одним из разрешенных значений",
+		lang.ValidationPasswordSame:       "Новый пароль должен отличаться от текущего",
+		lang.ValidationCodeFormat:         "Поле должно содержать 6-значный код из приложения",
+		lang.ValidationDateFormat:         "Дата должна быть в формате ГГГГ-ММ-ДД",
+		lang.ValidationValueMax:           "Значение превышает допустимое",
+		lang.ValidationEmailDomain:        "Регистрация с этого домена email недоступна",
+		lang.ValidationScopeInvalid:       "Укажите хотя бы одно право из доступных API клиентам",
+		lang.ValidationRedirectURIInvalid: "Адрес перенаправления должен быть абсолютным и без фрагмента",
+		lang.ValidationPasswordMax:        "Пароль слишком длинный",
+		lang.ValidationPasswordLower:      "Пароль должен содержать строчную букву",
+		lang.ValidationPasswordUpper:      "Пароль должен содержать заглавную букву",
+		lang.ValidationPasswordDigit:      "Пароль должен содержать цифру",
+		lang.ValidationPasswordSymbol:     "Пароль должен содержать символ, отличный от буквы и цифры",
+		lang.ValidationPasswordEmail:      "Пароль не должен совпадать с email",
+		lang.ValidationPasswordBreached:   "Пароль найден в утечках данных, выберите другой",
 
 		// Logging messages - Handler level
-		lang.LogRegistrationRequest: "Запрос регистрации с IP: %s",
-		lang.LogLoginRequest:        "Запрос входа с IP: %s",
-		lang.LogValidationFailed:    "Ошибка валидации запроса с IP %s: %v",
-		lang.LogRegistrationFailed:  "Регистрация не удалась для IP %s: %v",
-		lang.LogRegistrationSuccess: "Регистрация успешна для IP %s, email: %s",
-		lang.LogLoginFailed:         "Вход не удался для IP %s: %v",
-		lang.LogLoginSuccess:        "Вход успешен для IP %s, email: %s",
-		lang.LogGetMeFailed:         "GetMe не удался: пользователь не найден в контексте с IP %s",
-		lang.LogGetMeSuccess:        "GetMe успешен для IP %s, пользователь: %s",
-		lang.LogParseRequestFailed:  "Ошибка парсинга запроса с IP %s: %v",
+		lang.LogRegistrationRequest:       "Запрос регистрации с IP: %s",
+		lang.LogLoginRequest:              "Запрос входа с IP: %s",
+		lang.LogValidationFailed:          "Ошибка валидации запроса с IP %s: %v",
+		lang.LogRegistrationFailed:        "Регистрация не удалась для IP %s: %v",
+		lang.LogRegistrationSuccess:       "Регистрация успешна для IP %s, email: %s",
+		lang.LogLoginFailed:               "Вход не удался для IP %s: %v",
+		lang.LogLoginSuccess:              "Вход успешен для IP %s, email: %s",
+		lang.LogGetMeFailed:               "GetMe не удался: пользователь не найден в контексте с IP %s",
+		lang.LogGetMeSuccess:              "GetMe успешен для IP %s, пользователь: %s",
+		lang.LogParseRequestFailed:        "Ошибка парсинга запроса с IP %s: %v",
+		lang.LogRefreshRequest:            "Запрос обновления токена с IP: %s",
+		lang.LogRefreshFailed:             "Обновление токена не удалось для IP %s: %v",
+		lang.LogRefreshSuccess:            "Обновление токена успешно для IP %s, email: %s",
+		lang.LogLogoutRequest:             "Запрос выхода с IP: %s",
+		lang.LogLogoutFailed:              "Выход не удался для IP %s: %v",
+		lang.LogLogoutSuccess:             "Выход успешен для IP %s, email: %s",
+		lang.LogLogoutAllSuccess:          "Выход на всех устройствах успешен для IP %s, email: %s",
+		lang.LogForgotPasswordRequest:     "Запрос восстановления пароля с IP: %s",
+		lang.LogForgotPasswordFailed:      "Восстановление пароля не удалось для IP %s: %v",
+		lang.LogResetPasswordRequest:      "Запрос сброса пароля с IP: %s",
+		lang.LogResetPasswordFailed:       "Сброс пароля не удался для IP %s: %v",
+		lang.LogResetPasswordSuccess:      "Сброс пароля успешен для IP %s",
+		lang.LogChangePasswordRequest:     "Запрос смены пароля с IP: %s",
+		lang.LogChangePasswordFailed:      "Смена пароля не удалась для IP %s: %v",
+		lang.LogChangePasswordSuccess:     "Смена пароля успешна для IP %s, email: %s",
+		lang.LogVerifyEmailRequest:        "Запрос подтверждения email с IP: %s",
+		lang.LogVerifyEmailFailed:         "Подтверждение email не удалось для IP %s: %v",
+		lang.LogResendVerificationRequest: "Запрос повторной отправки письма подтверждения с IP: %s",
+		lang.LogResendVerificationFailed:  "Повторная отправка письма подтверждения не удалась для IP %s: %v",
+		lang.LogMFALoginRequest:           "Запрос ввода второго фактора с IP: %s",
+		lang.LogMFALoginFailed:            "Проверка второго фактора не удалась для IP %s: %v",
+		lang.LogMFALoginSuccess:           "Вход со вторым фактором успешен для IP %s, email: %s",
+		lang.LogMFAEnrollRequest:          "Запрос подключения двухфакторной аутентификации с IP: %s",
+		lang.LogMFAEnrollFailed:           "Подключение двухфакторной аутентификации не удалось для IP %s: %v",
+		lang.LogMFAConfirmRequest:         "Запрос подтверждения двухфакторной аутентификации с IP: %s",
+		lang.LogMFAConfirmFailed:          "Подтверждение двухфакторной аутентификации не удалось для IP %s: %v",
+		lang.LogUnlockRequest:             "Запрос снятия блокировки входа с IP: %s",
+		lang.LogUnlockFailed:              "Снятие блокировки входа не удалось для IP %s: %v",
+		lang.LogUnlockSuccess:             "Блокировка входа для %s снята пользователем %s",
+		lang.LogChangeRoleRequest:         "Запрос смены роли с IP: %s",
+		lang.LogChangeRoleFailed:          "Смена роли не удалась для IP %s: %v",
+		lang.LogRoleHistoryFailed:         "Получение журнала смены ролей не удалось для IP %s: %v",
+		lang.LogAdminUserRequest:          "Административный запрос %s %s с IP: %s",
+		lang.LogListUsersFailed:           "Получение списка пользователей не удалось для IP %s: %v",
+		lang.LogGetUserFailed:             "Получение пользователя не удалось для IP %s: %v",
+		lang.LogChangeStatusFailed:        "Смена статуса пользователя не удалась для IP %s: %v",
+		lang.LogDeleteUserFailed:          "Удаление пользователя не удалось для IP %s: %v",
+		lang.LogResetUserFailed:           "Сброс пользователя не удался для IP %s: %v",
+		lang.LogExportRequest:             "Запрос выгрузки персональных данных с IP: %s",
+		lang.LogExportFailed:              "Выгрузка персональных данных не удалась для IP %s: %v",
+		lang.LogCreateInvitationRequest:   "Запрос создания приглашения с IP: %s",
+		lang.LogCreateInvitationFailed:    "Создание приглашения не удалось для IP %s: %v",
+		lang.LogAcceptInvitationRequest:   "Запрос принятия приглашения с IP: %s",
+		lang.LogAcceptInvitationFailed:    "Принятие приглашения не удалось для IP %s: %v",
+		lang.LogAcceptInvitationSuccess:   "Приглашение принято с IP %s, email: %s",
+		lang.LogSessionsRequest:           "Запрос управления сессиями с IP: %s",
+		lang.LogListSessionsFailed:        "Получение списка сессий не удалось для IP %s: %v",
+		lang.LogRevokeSessionFailed:       "Завершение сессии не удалось для IP %s: %v",
+		lang.LogOAuthTokenRequest:         "Запрос токена API клиента с IP: %s",
+		lang.LogOAuthTokenFailed:          "Выдача токена API клиенту не удалась для IP %s: %v",
+		lang.LogAPIClientFailed:           "Операция с API клиентом не удалась для IP %s: %v",
+		lang.LogAPIKeysRequest:            "Запрос управления API ключами с IP: %s",
+		lang.LogAPIKeyFailed:              "Операция с API ключом не удалась для IP %s: %v",
+		lang.LogOIDCAuthorizeRequest:      "Запрос авторизации OpenID Connect с IP: %s",
+		lang.LogOIDCAuthorizeFailed:       "Авторизация OpenID Connect не удалась для IP %s: %v",
+		lang.LogUserInfoRequest:           "Запрос userinfo с IP: %s",
+		lang.LogUserInfoFailed:            "Запрос userinfo не удался для IP %s: %v",
+		lang.LogSSOLoginRequest:           "Запрос входа через внешний провайдер с IP: %s",
+		lang.LogSSOLoginFailed:            "Вход через внешний провайдер не удался для IP %s: %v",
+		lang.LogSCIMRequest:               "SCIM запрос %s %s от клиента %s с IP: %s",
+		lang.LogSCIMRequestFailed:         "SCIM запрос %s %s от клиента %s не выполнен: %v",
 
 		// Logging messages - Service level
-		lang.LogAttemptingRegistration: "Попытка регистрации пользователя с email: %s",
-		lang.LogCheckEmailExists:       "Ошибка проверки существования email %s: %v",
-		lang.LogEmailAlreadyExists:     "Регистрация не удалась: пользователь с email %s уже существует",
-		lang.LogPasswordHashError:      "Ошибка хеширования пароля для пользователя %s: %v",
-		lang.LogUserCreateError:        "Ошибка создания пользователя %s: %v",
-		lang.LogJWTGenerateError:       "Ошибка генерации JWT для пользователя %s: %v",
-		lang.LogRegistrationComplete:   "Регистрация пользователя успешно завершена для email: %s",
-		lang.LogAttemptingLogin:        "Попытка входа пользователя с email: %s",
-		lang.LogDatabaseErrorLogin:     "Ошибка БД при входе для email %s: %v",
-		lang.LogUserNotFoundLogin:      "Вход не удался: пользователь не найден с email %s",
-		lang.LogInvalidPassword:        "Вход не удался: неверный пароль для email %s",
-		lang.LogLoginComplete:          "Вход пользователя успешно завершен для email: %s",
-		lang.LogJWTParseError:          "Ошибка парсинга JWT токена: %v",
-		lang.LogJWTInvalid:             "Недействительный JWT токен или claims",
-		lang.LogUserFetchError:         "Ошибка получения пользователя по ID %s при валидации токена: %v",
-		lang.LogUserNotFoundValidation: "Пользователь не найден с ID %s при валидации токена",
+		lang.LogAttemptingRegistration:    "Попытка регистрации пользователя с email: %s",
+		lang.LogCheckEmailExists:          "Ошибка проверки существования email %s: %v",
+		lang.LogEmailAlreadyExists:        "Регистрация не удалась: пользователь с email %s уже существует",
+		lang.LogPasswordHashError:         "Ошибка хеширования пароля для пользователя %s: %v",
+		lang.LogUserCreateError:           "Ошибка создания пользователя %s: %v",
+		lang.LogJWTGenerateError:          "Ошибка генерации JWT для пользователя %s: %v",
+		lang.LogRegistrationComplete:      "Регистрация пользователя успешно завершена для email: %s",
+		lang.LogRegistrationRejected:      "Регистрация %s отклонена в режиме %s",
+		lang.LogRegistrationDomainDenied:  "Регистрация %s отклонена: домен в списке запрещенных",
+		lang.LogAttemptingLogin:           "Попытка входа пользователя с email: %s",
+		lang.LogDatabaseErrorLogin:        "Ошибка БД при входе для email %s: %v",
+		lang.LogUserNotFoundLogin:         "Вход не удался: пользователь не найден с email %s",
+		lang.LogInvalidPassword:           "Вход не удался: неверный пароль для email %s",
+		lang.LogLoginComplete:             "Вход пользователя успешно завершен для email: %s",
+		lang.LogJWTParseError:             "Ошибка парсинга JWT токена: %v",
+		lang.LogJWTInvalid:                "Недействительный JWT токен или claims",
+		lang.LogUserFetchError:            "Ошибка получения пользователя по ID %s при валидации токена: %v",
+		lang.LogUserNotFoundValidation:    "Пользователь не найден с ID %s при валидации токена",
+		lang.LogRefreshTokenIssueError:    "Ошибка выдачи refresh токена для пользователя %s: %v",
+		lang.LogRefreshTokenNotFound:      "Refresh токен не найден",
+		lang.LogRefreshTokenExpired:       "Refresh токен %s истек или отозван",
+		lang.LogRefreshTokenReuse:         "Обнаружено повторное использование refresh токена %s, отзываем цепочку %s",
+		lang.LogRefreshComplete:           "Обновление токенов успешно завершено для email: %s",
+		lang.LogJWTRevoked:                "JWT токен %s отозван",
+		lang.LogTokenRevoked:              "Токен %s пользователя %s добавлен в список отозванных",
+		lang.LogUserTokensRevoked:         "Все токены пользователя %s отозваны",
+		lang.LogRevocationSyncError:       "Ошибка синхронизации списка отозванных токенов: %v",
+		lang.LogLogoutError:               "Ошибка выхода пользователя %s: %v",
+		lang.LogLogoutComplete:            "Выход пользователя успешно завершен для email: %s",
+		lang.LogLogoutAllComplete:         "Выход на всех устройствах успешно завершен для пользователя %s",
+		lang.LogPasswordResetRequested:    "Запрос сброса пароля для email: %s",
+		lang.LogPasswordResetSent:         "Письмо для сброса пароля отправлено на %s",
+		lang.LogPasswordResetTokenInvalid: "Сброс пароля не удался: токен не найден, истек или уже использован",
+		lang.LogPasswordResetComplete:     "Пароль пользователя %s сброшен, все сессии завершены",
+		lang.LogPasswordResetExternal:     "Сброс пароля для %s отклонен: учетная запись без локального пароля",
+		lang.LogPasswordChangeComplete:    "Пароль пользователя %s изменен, все сессии завершены",
+		lang.LogEmailVerificationSent:     "Письмо для подтверждения email отправлено на %s",
+		lang.LogEmailVerificationInvalid:  "Подтверждение email не удалось: токен не найден, истек или уже использован",
+		lang.LogEmailVerificationComplete: "Email пользователя %s подтвержден",
+		lang.LogEmailNotVerified:          "Вход отклонен: email %s не подтвержден",
+		lang.LogMFAChallengeIssued:        "Пароль пользователя %s проверен, ожидается второй фактор",
+		lang.LogMFAChallengeInvalid:       "Проверка второго фактора отклонена: вход не найден, истек или исчерпал попытки",
+		lang.LogMFACodeInvalid:            "Неверный код второго фактора для пользователя %s",
+		lang.LogMFAEnrollmentStarted:      "Начато подключение двухфакторной аутентификации для пользователя %s",
+		lang.LogMFAEnabled:                "Двухфакторная аутентификация включена для пользователя %s",
+		lang.LogMFARecoveryCodeUsed:       "Пользователь %s вошел с кодом восстановления",
+		lang.LogLoginLocked:               "Вход отклонен: %s заблокирован до %s",
+		lang.LogLoginLockApplied:          "После %d неудачных попыток вход для %s заблокирован до %s",
+		lang.LogLoginUnlocked:             "Блокировка входа для %s снята",
+		lang.LogRoleChanged:               "Роль пользователя %s изменена с %s на %s пользователем %s",
+		lang.LogUserStatusChanged:         "Статус учетной записи %s изменен с %s на %s пользователем %s",
+		lang.LogUserDeleted:               "Пользователь %s удален пользователем %s",
+		lang.LogUserReset:                 "Учетная запись %s сброшена пользователем %s, второй фактор сброшен: %t",
+		lang.LogLoginInactive:             "Вход отклонен: учетная запись %s в статусе %s",
+		lang.LogInvitationSent:            "Приглашение с ролью %s отправлено на %s пользователем %s",
+		lang.LogInvitationInvalid:         "Приглашение не найдено, истекло или уже принято",
+		lang.LogInvitationAccepted:        "Приглашение %s принято, создан аккаунт %s",
+		lang.LogAccountExported:           "Персональные данные пользователя %s выгружены",
+		lang.LogErasureComplete:           "Персональные данные удаленного пользователя %s стерты",
+		lang.LogErasureFailed:             "Не удалось стереть персональные данные пользователя %s: %v",
+		lang.LogErasureBatch:              "Задача стирания персональных данных обработала учетных записей: %d",
+		lang.LogPasswordRehashed:          "Хеш пароля пользователя %s обновлен по текущим настройкам",
+		lang.LogPasswordRehashFailed:      "Не удалось обновить хеш пароля пользователя %s: %v",
+		lang.LogSessionStarted:            "Начата сессия %s пользователя %s с IP %s",
+		lang.LogSessionRevoked:            "Сессия %s завершена пользователем %s",
+		lang.LogSessionRevokedToken:       "Сессия %s завершена, токен %s отклонен",
+		lang.LogAPIClientCreated:          "API клиент %s (%s) с правами [%s] зарегистрирован пользователем %s",
+		lang.LogAPIClientRevoked:          "API клиент %s отозван пользователем %s",
+		lang.LogAPIClientInactive:         "Токен отозванного или удаленного API клиента %s отклонен",
+		lang.LogClientTokenIssued:         "API клиенту %s выдан токен с правами [%s]",
+		lang.LogClientScopeDenied:         "API клиент %s запросил невыданное право %s",
+		lang.LogClientAuthFailed:          "Неудачная аутентификация API клиента %s",
+		lang.LogJWTClientToken:            "Токен API клиента %s предъявлен вместо токена пользователя",
+		lang.LogAPIKeyCreated:             "Создан API ключ %s (%s) пользователя %s",
+		lang.LogAPIKeyRevoked:             "API ключ %s отозван пользователем %s",
+		lang.LogAPIKeyRejected:            "Отклонен неизвестный, отозванный или истекший API ключ %s",
+		lang.LogAPIKeyTouchFailed:         "Не удалось обновить время использования API ключа %s: %v",
+		lang.LogOIDCCodeIssued:            "Выдан код авторизации клиенту %s для пользователя %s",
+		lang.LogOIDCCodeInvalid:           "Отклонен код авторизации: не найден, истек или выдан не клиенту %s",
+		lang.LogOIDCPKCEFailed:            "Проверка PKCE не пройдена для клиента %s",
+		lang.LogOIDCTokensIssued:          "Выданы токены OpenID Connect клиенту %s для пользователя %s",
+		lang.LogOIDCSymmetricKey:          "OpenID Connect: токены подписываются общим секретом HS256, внешние сервисы не смогут проверить ID токены; задайте JWT_SIGNING_KEY_FILE",
+		lang.LogSSOStateInvalid:           "Отклонен возврат от провайдера %s: state не найден, истек или уже использован",
+		lang.LogSSOBrowserMismatch:        "Отклонен возврат от провайдера %s: вход начат в другом браузере",
+		lang.LogSSOExchangeFailed:         "Не удалось подтвердить вход через провайдер %s: %v",
+		lang.LogSSOIdentityLinked:         "Учетная запись провайдера %s привязана к пользователю %s",
+		lang.LogSSOUserProvisioned:        "Создан пользователь %s при первом входе через провайдер %s",
+		lang.LogSSOLoginComplete:          "Вход через провайдер %s завершен для пользователя %s",
+		lang.LogSSOAccountNotFound:        "Вход через провайдер %s отклонен: нет учетной записи %s, автоматическое создание выключено",
+		lang.LogSSOProviderLoaded:         "Загружены настройки провайдера %s: %s",
+		lang.LogAuthenticatorFailed:       "Ошибка проверки учетных данных %s: %v",
+		lang.LogLDAPUserNotFound:          "Пользователь %s не найден в каталоге LDAP",
+		lang.LogLDAPAmbiguousUser:         "В каталоге LDAP найдено несколько учетных записей %s, вход отклонен",
+		lang.LogLDAPInvalidPassword:       "Неверный пароль каталога LDAP для пользователя %s",
+		lang.LogLDAPAccessDenied:          "Пользователь каталога %s не входит в группы с доступом к порталу",
+		lang.LogLDAPUserProvisioned:       "Создан пользователь %s с ролью %s при первом входе через каталог LDAP",
+		lang.LogLDAPRoleSynced:            "Роль пользователя %s обновлена по группам каталога LDAP: %s -> %s",
+		lang.LogSCIMUserProvisioned:       "Создан пользователь %s по данным кадровой системы",
+		lang.LogSCIMUserUpdated:           "Пользователь %s обновлен по данным кадровой системы",
+		lang.LogSCIMUserDeactivated:       "Пользователь %s деактивирован по данным кадровой системы",
+		lang.LogSCIMUserReactivated:       "Пользователь %s снова активирован по данным кадровой системы",
+		lang.LogSCIMUserDeleted:           "Пользователь %s удален по данным кадровой системы",
+		lang.LogSCIMAdminForbidden:        "Кадровая система пыталась изменить учетную запись администратора %s",
+		lang.LogSCIMGroupCreated:          "Создана группа %s по данным кадровой системы",
+		lang.LogSCIMGroupUpdated:          "Группа %s обновлена по данным кадровой системы",
+		lang.LogSCIMGroupDeleted:          "Группа %s удалена по данным кадровой системы",
 
 		// Logging messages - Repository level
 		lang.LogUserCreateSuccess: "Пользователь успешно создан с email %s",
@@ -68,11 +349,58 @@ func NewRussianMessages() lang.Messages {
 		lang.LogDatabaseError:     "Ошибка БД при операции с %s %s: %v",
 		lang.LogEmailExistsCheck:  "Ошибка БД при проверке существования email %s: %v",
 
+		lang.LogRefreshTokenCreateFailed: "Ошибка сохранения refresh токена для пользователя %s: %v",
+		lang.LogRefreshTokenDBError:      "Ошибка БД при операции с refresh токеном %s: %v",
+		lang.LogRefreshFamilyRevoked:     "Цепочка refresh токенов %s отозвана",
+		lang.LogRevocationDBError:        "Ошибка БД при операции со списком отозванных токенов %s: %v",
+		lang.LogPasswordResetDBError:     "Ошибка БД при операции с токеном сброса пароля %s: %v",
+		lang.LogPasswordUpdated:          "Пароль пользователя %s обновлен",
+		lang.LogEmailVerificationDBError: "Ошибка БД при операции с токеном подтверждения email %s: %v",
+		lang.LogEmailMarkedVerified:      "Email пользователя %s отмечен подтвержденным",
+		lang.LogMFADBError:               "Ошибка БД при операции с двухфакторной аутентификацией %s: %v",
+		lang.LogLoginAttemptDBError:      "Ошибка БД при учете попыток входа %s: %v",
+		lang.LogRoleUpdated:              "Роль пользователя %s изменена на %s",
+		lang.LogUserStatusUpdated:        "Статус учетной записи %s изменен на %s",
+		lang.LogUserRemoved:              "Пользователь %s помечен удаленным",
+		lang.LogUserErased:               "Персональные данные пользователя %s стерты в БД",
+		lang.LogMFARemoved:               "Второй фактор пользователя %s удален",
+		lang.LogRoleChangeDBError:        "Ошибка БД при операции с журналом смены ролей %s: %v",
+		lang.LogInvitationDBError:        "Ошибка БД при операции с приглашением %s: %v",
+		lang.LogSessionDBError:           "Ошибка БД при операции с сессией %s: %v",
+		lang.LogAPIClientDBError:         "Ошибка БД при операции с API клиентом %s: %v",
+		lang.LogAPIKeyDBError:            "Ошибка БД при операции с API ключом %s: %v",
+		lang.LogAuthorizationCodeDBError: "Ошибка БД при операции с кодом авторизации %s: %v",
+		lang.LogSSOStateDBError:          "Ошибка БД при операции с состоянием входа через провайдер %s: %v",
+		lang.LogUserIdentityDBError:      "Ошибка БД при операции с внешней учетной записью %s: %v",
+		lang.LogGroupDBError:             "Ошибка БД при операции с группой %s: %v",
+		lang.LogEmailUpdated:             "Email пользователя %s обновлен",
+
 		// Logging messages - Middleware level
-		lang.LogJWTMissingHeader:     "JWT middleware: отсутствует заголовок Authorization с IP %s",
-		lang.LogJWTInvalidFormat:     "JWT middleware: неверный формат заголовка Authorization с IP %s",
-		lang.LogJWTValidationFailed:  "JWT middleware: валидация токена не удалась с IP %s: %v",
-		lang.LogJWTValidationSuccess: "JWT middleware: валидация токена успешна для IP %s, пользователь: %s",
+		lang.LogJWTMissingHeader:           "JWT middleware: отсутствует заголовок Authorization с IP %s",
+		lang.LogJWTInvalidFormat:           "JWT middleware: неверный формат заголовка Authorization с IP %s",
+		lang.LogJWTValidationFailed:        "JWT middleware: валидация токена не удалась с IP %s: %v",
+		lang.LogJWTValidationSuccess:       "JWT middleware: валидация токена успешна для IP %s, пользователь: %s",
+		lang.LogJWTMissingUser:             "Authorization middleware: пользователь не найден в контексте запроса с IP %s",
+		lang.LogAuthorizationDenied:        "Authorization middleware: доступ запрещен для IP %s, пользователь %s (роль %s), требуется %s",
+		lang.LogScopeDenied:                "Authorization middleware: доступ запрещен для IP %s, API клиент %s, требуется %s",
+		lang.LogJWTClientValidationSuccess: "JWT middleware: валидация токена успешна для IP %s, API клиент: %s",
+		lang.LogAPIKeyValidationSuccess:    "JWT middleware: API ключ %s принят для IP %s, пользователь: %s",
+		lang.LogAPIKeySessionRequired:      "Доступ запрещен: IP %s, пользователь %s обратился по API ключу к маршруту, требующему входа",
+		lang.LogRateLimitExceeded:          "Rate limit middleware: превышен лимит %s для %s, повтор через %d сек.",
+
+		// Logging messages - Keys
+		lang.LogSigningKeyLoaded:      "Загружен ключ подписи JWT kid=%s (%s)",
+		lang.LogVerificationKeyLoaded: "Загружен ключ проверки JWT kid=%s (%s)",
+		lang.LogJWTUnknownKeyID:       "Токен подписан неизвестным ключом kid=%s",
+
+		// Logging messages - Passwords
+		lang.LogBreachedListLoaded:  "Загружен список утекших паролей %s, хешей: %d",
+		lang.LogBreachedCheckFailed: "Не удалось проверить пароль по списку утекших: %v",
+
+		// Logging messages - Mail
+		lang.LogMailLogged:    "Письмо (не отправлено, MAIL_SENDER=log)\nFrom: %s\nTo: %s\nSubject: %s\n\n%s",
+		lang.LogMailWritten:   "Письмо для %s сохранено в %s",
+		lang.LogMailSendError: "Ошибка отправки письма на %s: %v",
 	}
 
 	return lang.NewMessageProvider(messages)
@@ -0,0 +1,65 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+)
+
+// FileSender сохраняет письма в файлы каталога (для локальной разработки и тестов)
+type FileSender struct {
+	dir      string
+	from     string
+	messages lang.Messages
+}
+
+// NewFileSender создает новый FileSender
+func NewFileSender(dir, from string, messages lang.Messages) *FileSender {
+	return &FileSender{
+		dir:      dir,
+		from:     from,
+		messages: messages,
+	}
+}
+
+// Send записывает письмо в отдельный файл в формате RFC 5322
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		log.Printf(s.messages.Get(lang.LogMailSendError), msg.To, err)
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	path := filepath.Join(s.dir, name)
+
+	content := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		msg.From, msg.To, msg.Subject, time.Now().Format(time.RFC1123Z), msg.Body)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		log.Printf(s.messages.Get(lang.LogMailSendError), msg.To, err)
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogMailWritten), msg.To, path)
+	return nil
+}
+
+// sanitizeFileName заменяет символы, недопустимые в имени файла
+func sanitizeFileName(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, value)
+}
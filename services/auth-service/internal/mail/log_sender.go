@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+)
+
+// LogSender выводит письма в лог вместо отправки (для локальной разработки)
+type LogSender struct {
+	from     string
+	messages lang.Messages
+}
+
+// NewLogSender создает новый LogSender
+func NewLogSender(from string, messages lang.Messages) *LogSender {
+	return &LogSender{
+		from:     from,
+		messages: messages,
+	}
+}
+
+// Send выводит письмо в лог
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+
+	log.Printf(s.messages.Get(lang.LogMailLogged), msg.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+)
+
+// Message представляет исходящее письмо
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender интерфейс для отправки писем.
+// Реализации для SMTP или внешнего API подключаются через этот интерфейс.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender создает отправителя писем по конфигурации
+func NewSender(cfg config.MailConfig, messages lang.Messages) Sender {
+	switch cfg.Sender {
+	case "file":
+		return NewFileSender(cfg.FileDir, cfg.From, messages)
+	default:
+		return NewLogSender(cfg.From, messages)
+	}
+}
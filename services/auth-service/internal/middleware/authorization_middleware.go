@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole создает middleware, пропускающее только пользователей с одной из указанных ролей.
+// У API клиентов ролей нет. Должно стоять после JWTMiddleware.
+func RequireRole(messages lang.Messages, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if client, ok := GetClient(c); ok {
+			return forbiddenClient(c, client, fmt.Sprint(roles), messages)
+		}
+
+		user, ok := GetUser(c)
+		if !ok {
+			return unauthorized(c, messages)
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				return c.Next()
+			}
+		}
+
+		return forbidden(c, user, fmt.Sprint(roles), messages)
+	}
+}
+
+// RequirePermission создает middleware, пропускающее только пользователей, чья роль
+// имеет все указанные права, и API клиентов, которым эти права выданы в токене.
+// Пользователю с API ключом нужны права и в роли, и в scope ключа.
+// Должно стоять после JWTMiddleware.
+func RequirePermission(messages lang.Messages, permissions ...models.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if client, ok := GetClient(c); ok {
+			return requireScopes(c, client, permissions, messages)
+		}
+
+		user, ok := GetUser(c)
+		if !ok {
+			return unauthorized(c, messages)
+		}
+
+		apiKey, hasAPIKey := GetAPIKey(c)
+		for _, permission := range permissions {
+			if !models.HasPermission(user.Role, permission) {
+				return forbidden(c, user, string(permission), messages)
+			}
+			if hasAPIKey && !apiKey.Allows(permission) {
+				return forbidden(c, user, string(permission), messages)
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireSession создает middleware для управления учетной записью: пропускает только
+// пользователей, вошедших по паролю, и отклоняет запросы по API ключу и токены API клиентов.
+// Должно стоять после JWTMiddleware.
+func RequireSession(messages lang.Messages) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if client, ok := GetClient(c); ok {
+			return forbiddenClient(c, client, "session", messages)
+		}
+
+		user, ok := GetUser(c)
+		if !ok {
+			return unauthorized(c, messages)
+		}
+
+		if _, ok := GetAPIKey(c); ok {
+			log.Printf(messages.Get(lang.LogAPIKeySessionRequired), c.IP(), user.Email)
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": messages.Get(lang.APIKeySessionRequired),
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireScope создает middleware для маршрутов, доступных любому пользователю:
+// пользователей пропускает, а от API клиентов требует все указанные права в токене.
+// Должно стоять после JWTMiddleware.
+func RequireScope(messages lang.Messages, scopes ...models.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if client, ok := GetClient(c); ok {
+			return requireScopes(c, client, scopes, messages)
+		}
+
+		if _, ok := GetUser(c); !ok {
+			return unauthorized(c, messages)
+		}
+
+		return c.Next()
+	}
+}
+
+// requireScopes пропускает API клиента, если его токену выданы все права
+func requireScopes(c *fiber.Ctx, client *models.AuthenticatedClient, scopes []models.Permission, messages lang.Messages) error {
+	for _, scope := range scopes {
+		if !client.HasScope(scope) {
+			return forbiddenClient(c, client, string(scope), messages)
+		}
+	}
+
+	return c.Next()
+}
+
+// unauthorized отвечает 401, если маршрут не защищен JWTMiddleware или пользователь не найден
+func unauthorized(c *fiber.Ctx, messages lang.Messages) error {
+	log.Printf(messages.Get(lang.LogJWTMissingUser), c.IP())
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"error": messages.Get(lang.TokenInvalid),
+	})
+}
+
+// forbidden отвечает 403, если у пользователя нет нужной роли или права
+func forbidden(c *fiber.Ctx, user *models.User, required string, messages lang.Messages) error {
+	log.Printf(messages.Get(lang.LogAuthorizationDenied), c.IP(), user.Email, user.Role, required)
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": messages.Get(lang.AccessDenied),
+	})
+}
+
+// forbiddenClient отвечает 403, если токену API клиента не выдано нужное право
+func forbiddenClient(c *fiber.Ctx, client *models.AuthenticatedClient, required string, messages lang.Messages) error {
+	log.Printf(messages.Get(lang.LogScopeDenied), c.IP(), client.ID.String(), required)
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": messages.Get(lang.AccessDenied),
+	})
+}
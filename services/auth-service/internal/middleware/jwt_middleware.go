@@ -5,13 +5,17 @@ import (
 	"strings"
 
 	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
 	"github.com/avangero/auth-service/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
-// JWTMiddleware создает middleware для проверки JWT токенов
-func JWTMiddleware(authService services.AuthService, messages lang.Messages) fiber.Handler {
+// JWTMiddleware создает middleware для проверки JWT токенов пользователей и API клиентов,
+// а также персональных API ключей в заголовке "Authorization: ApiKey <key>".
+// Пользователь сохраняется в контексте как "user", клиент - как "client",
+// ключ, по которому вошел пользователь, - как "api_key".
+func JWTMiddleware(authService services.AuthService, clientService services.ClientService, apiKeyService services.APIKeyService, messages lang.Messages) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		clientIP := c.IP()
 
@@ -24,8 +28,23 @@ func JWTMiddleware(authService services.AuthService, messages lang.Messages) fib
 			})
 		}
 
-		// Проверяем формат "Bearer <token>"
+		// Проверяем формат "Bearer <token>" или "ApiKey <key>"
 		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == "ApiKey" {
+			user, apiKey, err := apiKeyService.Authenticate(c.Context(), parts[1])
+			if err != nil {
+				log.Printf(messages.Get(lang.LogJWTValidationFailed), clientIP, err)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": messages.Get(lang.APIKeyInvalid),
+				})
+			}
+
+			// Токена нет: маршруты, которым нужна сессия, отклоняют такой запрос
+			c.Locals("user", user)
+			c.Locals("api_key", apiKey)
+			log.Printf(messages.Get(lang.LogAPIKeyValidationSuccess), apiKey.Prefix, clientIP, user.Email)
+			return c.Next()
+		}
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			log.Printf(messages.Get(lang.LogJWTInvalidFormat), clientIP)
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -35,6 +54,21 @@ func JWTMiddleware(authService services.AuthService, messages lang.Messages) fib
 
 		tokenString := parts[1]
 
+		// Токен API клиента не представляет пользователя и проверяется отдельно
+		if services.IsClientToken(tokenString) {
+			client, err := clientService.ValidateToken(c.Context(), tokenString)
+			if err != nil {
+				log.Printf(messages.Get(lang.LogJWTValidationFailed), clientIP, err)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": messages.Get(lang.TokenInvalid),
+				})
+			}
+
+			c.Locals("client", client)
+			log.Printf(messages.Get(lang.LogJWTClientValidationSuccess), clientIP, client.Name)
+			return c.Next()
+		}
+
 		// Валидируем токен через AuthService
 		user, err := authService.ValidateToken(c.Context(), tokenString)
 		if err != nil {
@@ -44,25 +78,53 @@ func JWTMiddleware(authService services.AuthService, messages lang.Messages) fib
 			})
 		}
 
-		// Сохраняем пользователя в контексте для использования в handlers
+		// Сохраняем пользователя и токен в контексте для использования в handlers
 		c.Locals("user", user)
+		c.Locals("token", tokenString)
 		log.Printf(messages.Get(lang.LogJWTValidationSuccess), clientIP, user.Email)
 
 		return c.Next()
 	}
 }
 
-// GetUserID извлекает ID пользователя из контекста
+// GetUser извлекает пользователя, сохраненного JWTMiddleware
+func GetUser(c *fiber.Ctx) (*models.User, bool) {
+	user, ok := c.Locals("user").(*models.User)
+	return user, ok
+}
+
+// GetClient извлекает API клиента, сохраненного JWTMiddleware
+func GetClient(c *fiber.Ctx) (*models.AuthenticatedClient, bool) {
+	client, ok := c.Locals("client").(*models.AuthenticatedClient)
+	return client, ok
+}
+
+// GetAPIKey извлекает API ключ, по которому JWTMiddleware аутентифицировал пользователя
+func GetAPIKey(c *fiber.Ctx) (*models.APIKey, bool) {
+	apiKey, ok := c.Locals("api_key").(*models.APIKey)
+	return apiKey, ok
+}
+
+// GetUserID извлекает ID пользователя из контекста; uuid.Nil, если пользователь не аутентифицирован
 func GetUserID(c *fiber.Ctx) uuid.UUID {
-	return c.Locals("user_id").(uuid.UUID)
+	if user, ok := GetUser(c); ok {
+		return user.ID
+	}
+	return uuid.Nil
 }
 
 // GetUserEmail извлекает email пользователя из контекста
 func GetUserEmail(c *fiber.Ctx) string {
-	return c.Locals("user_email").(string)
+	if user, ok := GetUser(c); ok {
+		return user.Email
+	}
+	return ""
 }
 
 // GetUserRole извлекает роль пользователя из контекста
 func GetUserRole(c *fiber.Ctx) string {
-	return c.Locals("user_role").(string)
+	if user, ok := GetUser(c); ok {
+		return user.Role
+	}
+	return ""
 }
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitMiddleware создает middleware, ограничивающее частоту запросов к маршруту
+// по IP клиента и по email из тела запроса. Лимиты хранятся в памяти процесса.
+func RateLimitMiddleware(limits config.RouteRateLimit, messages lang.Messages) fiber.Handler {
+	ipLimiter := newRateLimiter(limits.IP)
+	emailLimiter := newRateLimiter(limits.Email)
+
+	return func(c *fiber.Ctx) error {
+		now := time.Now()
+		clientIP := c.IP()
+
+		if retryAfter, ok := ipLimiter.allow(clientIP, now); !ok {
+			return tooManyRequests(c, "ip:"+clientIP, retryAfter, messages)
+		}
+
+		if email := requestEmail(c); email != "" {
+			if retryAfter, ok := emailLimiter.allow(email, now); !ok {
+				return tooManyRequests(c, "email:"+email, retryAfter, messages)
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// tooManyRequests отвечает 429 с заголовком Retry-After
+func tooManyRequests(c *fiber.Ctx, key string, retryAfter time.Duration, messages lang.Messages) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	log.Printf(messages.Get(lang.LogRateLimitExceeded), c.Path(), key, seconds)
+
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error": messages.Get(lang.TooManyRequests, seconds),
+	})
+}
+
+// requestEmail извлекает email из тела запроса; ошибки разбора оставляем обработчику
+func requestEmail(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email" form:"email"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+// rateLimiterPruneInterval как часто удалять восстановившиеся корзины из памяти
+const rateLimiterPruneInterval = time.Minute
+
+// tokenBucket корзина токенов одного ключа
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter набор корзин токенов с общими параметрами
+type rateLimiter struct {
+	limit      config.RateLimit
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	lastPruned time.Time
+}
+
+// newRateLimiter создает набор корзин с указанным лимитом
+func newRateLimiter(limit config.RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow забирает токен из корзины ключа. Если токенов нет, возвращает время до появления следующего.
+func (l *rateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	if l.limit.Burst <= 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+
+	capacity := float64(l.limit.Burst)
+	perToken := l.limit.Period / time.Duration(l.limit.Burst)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		l.buckets[key] = bucket
+	} else {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(now.Sub(bucket.updated))/float64(perToken))
+		bucket.updated = now
+	}
+
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) * float64(perToken)), false
+	}
+
+	bucket.tokens--
+	return 0, true
+}
+
+// prune удаляет корзины, которые успели восстановиться полностью: они не отличаются от новых.
+// Вызывается под блокировкой mu.
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPruned) < rateLimiterPruneInterval {
+		return
+	}
+	l.lastPruned = now
+
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= l.limit.Period {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIClient представляет сервис портала, который получает токены по OAuth2 client_credentials,
+// или внешний инструмент, в который пользователи входят через OpenID Connect.
+// ID служит client_id, секрет хранится только в виде SHA-256 хеша.
+type APIClient struct {
+	ID           uuid.UUID  `json:"client_id" db:"id"`
+	Name         string     `json:"name" db:"name"`
+	SecretHash   string     `json:"-" db:"secret_hash"`
+	Scope        string     `json:"scope" db:"scope"`                 // выданные права через пробел, как scope в OAuth2
+	RedirectURIs string     `json:"redirect_uris" db:"redirect_uris"` // адреса возврата OpenID Connect через пробел
+	CreatedBy    *uuid.UUID `json:"created_by" db:"created_by"`
+	Created      time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// IsActive проверяет, что клиент не отозван
+func (c *APIClient) IsActive() bool {
+	return c.RevokedAt == nil
+}
+
+// Scopes возвращает права, выданные клиенту
+func (c *APIClient) Scopes() []Permission {
+	return ParseScope(c.Scope)
+}
+
+// AllowsRedirect проверяет, зарегистрирован ли адрес возврата у клиента. Адрес сравнивается целиком.
+func (c *APIClient) AllowsRedirect(redirectURI string) bool {
+	for _, registered := range strings.Fields(c.RedirectURIs) {
+		if registered == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthenticatedClient представляет API клиента, предъявившего действительный токен,
+// и права, выданные именно этому токену
+type AuthenticatedClient struct {
+	ID     uuid.UUID
+	Name   string
+	Scopes []Permission
+}
+
+// HasScope проверяет, выдано ли токену клиента указанное право
+func (c *AuthenticatedClient) HasScope(scope Permission) bool {
+	for _, granted := range c.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScope разбирает строку scope с правами через пробел
+func ParseScope(scope string) []Permission {
+	var scopes []Permission
+	for _, field := range strings.Fields(scope) {
+		scopes = append(scopes, Permission(field))
+	}
+	return scopes
+}
+
+// FormatScope собирает права в строку scope через пробел
+func FormatScope(scopes []Permission) string {
+	fields := make([]string, len(scopes))
+	for i, scope := range scopes {
+		fields[i] = string(scope)
+	}
+	return strings.Join(fields, " ")
+}
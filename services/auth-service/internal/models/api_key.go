@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey представляет персональный API ключ пользователя для скриптов и интеграций.
+// Ключ целиком показывается один раз при создании, в БД хранятся его SHA-256 хеш
+// и начало (Prefix), по которому владелец узнает ключ в списке.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scope      string     `json:"scope" db:"scope"` // права через пробел; пусто - все права роли владельца
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	Created    time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// IsActive проверяет, что ключ не отозван и не истек
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Allows проверяет, разрешено ли ключу право. Ключ без scope ограничен только ролью владельца.
+func (k *APIKey) Allows(permission Permission) bool {
+	scopes := ParseScope(k.Scope)
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthorizationCode представляет одноразовый код авторизации OpenID Connect.
+// В БД хранится только SHA-256 хеш кода.
+type AuthorizationCode struct {
+	CodeHash      string     `db:"code_hash"`
+	ClientID      uuid.UUID  `db:"client_id"`
+	UserID        uuid.UUID  `db:"user_id"`
+	SessionID     uuid.UUID  `db:"session_id"` // сессия портала, в которой пользователь разрешил вход
+	RedirectURI   string     `db:"redirect_uri"`
+	Scope         string     `db:"scope"`
+	Nonce         string     `db:"nonce"`
+	CodeChallenge string     `db:"code_challenge"` // PKCE, всегда S256
+	ExpiresAt     time.Time  `db:"expires_at"`
+	Created       time.Time  `db:"created_at"`
+	UsedAt        *time.Time `db:"used_at"`
+}
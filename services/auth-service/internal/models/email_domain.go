@@ -0,0 +1,40 @@
+package models
+
+import "strings"
+
+// EmailDomainPolicy правила доменов email для самостоятельной регистрации.
+// Домены сравниваются целиком в нижнем регистре, поддомены нужно перечислять отдельно.
+type EmailDomainPolicy struct {
+	Allowed []string // если список не пуст, разрешены только эти домены
+	Denied  []string // запрещенные домены, проверяются раньше разрешенных
+}
+
+// IsDenied проверяет, входит ли домен email в список запрещенных
+func (p EmailDomainPolicy) IsDenied(email string) bool {
+	return containsDomain(p.Denied, EmailDomain(email))
+}
+
+// IsAllowed проверяет, входит ли домен email в список разрешенных (пустой список разрешает все)
+func (p EmailDomainPolicy) IsAllowed(email string) bool {
+	return len(p.Allowed) == 0 || containsDomain(p.Allowed, EmailDomain(email))
+}
+
+// Allows проверяет, можно ли зарегистрироваться с email
+func (p EmailDomainPolicy) Allows(email string) bool {
+	return !p.IsDenied(email) && p.IsAllowed(email)
+}
+
+// EmailDomain возвращает домен email в нижнем регистре
+func EmailDomain(email string) string {
+	return strings.ToLower(strings.TrimSpace(email[strings.LastIndex(email, "@")+1:]))
+}
+
+// containsDomain проверяет наличие домена в списке
+func containsDomain(domains []string, domain string) bool {
+	for _, candidate := range domains {
+		if candidate == domain {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group представляет группу сотрудников из кадровой системы, например отдел.
+// Группы создаются и наполняются только через SCIM и на роли пользователей не влияют.
+type Group struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	DisplayName string    `json:"display_name" db:"display_name"`
+	ExternalID  *string   `json:"external_id" db:"external_id"` // идентификатор группы в кадровой системе
+	Created     time.Time `json:"created_at" db:"created_at"`
+	Updated     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GroupMember представляет участника группы
+type GroupMember struct {
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	Email  string    `json:"email" db:"email"`
+}
+
+// Операции над составом группы
+const (
+	GroupMembersAdd     = "add"
+	GroupMembersRemove  = "remove"
+	GroupMembersReplace = "replace"
+)
+
+// GroupMembersChange изменение состава группы: добавить, исключить или заменить всех участников
+type GroupMembersChange struct {
+	Op      string
+	UserIDs []uuid.UUID
+}
+
+// GroupFilter задает отбор и страницу в списке групп
+type GroupFilter struct {
+	DisplayName string // точное совпадение, пустая строка - любое название
+	ExternalID  string // точное совпадение, пустая строка - любой
+	Limit       int
+	Offset      int
+}
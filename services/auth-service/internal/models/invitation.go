@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation представляет приглашение на портал с заранее назначенной ролью.
+// В БД хранится только хеш токена, сам токен отправляется приглашенному по почте.
+type Invitation struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Email      string     `json:"email" db:"email"`
+	Role       string     `json:"role" db:"role"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	InvitedBy  *uuid.UUID `json:"invited_by" db:"invited_by"` // nil, если пригласивший удален
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at" db:"accepted_at"`
+	Created    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive проверяет, что приглашение не принято и не истекло
+func (i *Invitation) IsActive(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
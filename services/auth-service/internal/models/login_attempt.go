@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// LoginAttempt представляет счетчик неудачных попыток входа по ключу (email или IP клиента)
+type LoginAttempt struct {
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
+
+// IsLocked проверяет, действует ли блокировка в момент now
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA представляет настройку TOTP второго фактора пользователя.
+// Пока ConfirmedAt пуст, секрет считается незавершенной регистрацией и при входе не требуется.
+type UserMFA struct {
+	UserID       uuid.UUID  `db:"user_id"`
+	Secret       string     `db:"secret"`
+	ConfirmedAt  *time.Time `db:"confirmed_at"`
+	LastUsedStep int64      `db:"last_used_step"` // последний принятый шаг TOTP, защищает от повторного ввода кода
+	Created      time.Time  `db:"created_at"`
+}
+
+// IsConfirmed проверяет, что пользователь подтвердил подключение второго фактора
+func (m *UserMFA) IsConfirmed() bool {
+	return m.ConfirmedAt != nil
+}
+
+// MFARecoveryCode представляет одноразовый код восстановления доступа.
+// В БД хранится только хеш кода, сами коды показываются пользователю один раз.
+type MFARecoveryCode struct {
+	ID       uuid.UUID  `db:"id"`
+	UserID   uuid.UUID  `db:"user_id"`
+	CodeHash string     `db:"code_hash"`
+	UsedAt   *time.Time `db:"used_at"`
+	Created  time.Time  `db:"created_at"`
+}
+
+// MFAChallenge представляет незавершенный вход: пароль проверен, ожидается второй фактор
+type MFAChallenge struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	Attempts  int        `db:"attempts"`
+	Created   time.Time  `db:"created_at"`
+}
+
+// IsActive проверяет, что вход не завершен, не истек и не исчерпал попытки
+func (c *MFAChallenge) IsActive(now time.Time, maxAttempts int) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt) && c.Attempts < maxAttempts
+}
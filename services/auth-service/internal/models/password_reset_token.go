@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken представляет одноразовый токен сброса пароля.
+// В БД хранится только хеш токена, сам токен отправляется пользователю по почте.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	Created   time.Time  `db:"created_at"`
+}
+
+// IsActive проверяет, что токен не использован и не истек
+func (t *PasswordResetToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,99 @@
+package models
+
+// Permission именованное право доступа, которое проверяют сервисы портала
+type Permission string
+
+// Права доступа
+const (
+	PermissionCoursesRead      Permission = "courses:read"       // просмотр каталога курсов
+	PermissionCoursesEnroll    Permission = "courses:enroll"     // самостоятельная запись на курс
+	PermissionProgressRead     Permission = "progress:read"      // собственный прогресс и портфолио навыков
+	PermissionCoursesManage    Permission = "courses:manage"     // создание и редактирование курсов
+	PermissionTrainingAssign   Permission = "training:assign"    // назначение обучения сотрудникам
+	PermissionTeamProgressRead Permission = "team:progress:read" // прогресс подчиненных
+	PermissionSkillsValidate   Permission = "skills:validate"    // подтверждение навыков сотрудников
+	PermissionUsersInvite      Permission = "users:invite"       // приглашение сотрудников в портал
+	PermissionUsersManage      Permission = "users:manage"       // управление учетными записями и ролями
+	PermissionUsersUnlock      Permission = "users:unlock"       // снятие блокировки входа
+	PermissionClientsManage    Permission = "clients:manage"     // регистрация API клиентов сервисов
+	PermissionTokensValidate   Permission = "tokens:validate"    // проверка токенов пользователей сервисами портала
+	PermissionSCIMProvision    Permission = "scim:provision"     // создание и деактивация учетных записей кадровой системой
+)
+
+// employeePermissions права, которые есть у каждой роли
+var employeePermissions = []Permission{
+	PermissionCoursesRead,
+	PermissionCoursesEnroll,
+	PermissionProgressRead,
+}
+
+// managerPermissions права руководителя в дополнение к правам сотрудника
+var managerPermissions = []Permission{
+	PermissionCoursesManage,
+	PermissionTrainingAssign,
+	PermissionTeamProgressRead,
+	PermissionSkillsValidate,
+	PermissionUsersInvite,
+}
+
+// rolePermissions таблица прав ролей
+var rolePermissions = map[string][]Permission{
+	RoleEmployee: employeePermissions,
+	RoleManager:  concatPermissions(employeePermissions, managerPermissions),
+	RoleAdmin: concatPermissions(employeePermissions, managerPermissions, []Permission{
+		PermissionUsersManage,
+		PermissionUsersUnlock,
+		PermissionClientsManage,
+	}),
+}
+
+// clientScopes права, которые можно выдать API клиенту: чтение данных портала и служебные
+// права. Проверка токенов пользователей и SCIM нужны только сервисам, поэтому их нет ни у
+// одной роли. Изменяющие права ролей не выдаются: их действия записываются на пользователя.
+var clientScopes = []Permission{
+	PermissionCoursesRead,
+	PermissionProgressRead,
+	PermissionTeamProgressRead,
+	PermissionTokensValidate,
+	PermissionSCIMProvision,
+}
+
+// IsValidRole проверяет, известна ли роль
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// PermissionsForRole возвращает права роли; для неизвестной роли список пуст
+func PermissionsForRole(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
+
+// HasPermission проверяет, есть ли у роли указанное право
+func HasPermission(role string, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope проверяет, можно ли выдать право API клиенту
+func IsValidScope(scope Permission) bool {
+	for _, allowed := range clientScopes {
+		if allowed == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// concatPermissions объединяет списки прав
+func concatPermissions(lists ...[]Permission) []Permission {
+	var result []Permission
+	for _, list := range lists {
+		result = append(result, list...)
+	}
+	return result
+}
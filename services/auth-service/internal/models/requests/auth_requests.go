@@ -1,10 +1,10 @@
 package requests
 
-// RegisterRequest представляет запрос на регистрацию
+// RegisterRequest представляет запрос на регистрацию.
+// Роль не принимается от клиента: самостоятельно регистрируются только сотрудники.
 type RegisterRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
-	Role     string `json:"role" validate:"required,oneof=employee manager"`
+	Email    string `json:"email" validate:"required,email,corporate_email"`
+	Password string `json:"password" validate:"required"` // проверяется политикой паролей
 }
 
 // LoginRequest представляет запрос на вход
@@ -12,3 +12,154 @@ type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+// RefreshRequest представляет запрос на обновление токенов
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// ForgotPasswordRequest представляет запрос на восстановление пароля
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest представляет запрос на установку нового пароля по токену
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"` // проверяется политикой паролей
+}
+
+// ChangePasswordRequest представляет запрос на смену пароля
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,nefield=CurrentPassword"` // проверяется политикой паролей
+	Email           string `json:"-"`                                                        // email владельца, заполняет обработчик
+}
+
+// VerifyEmailRequest представляет запрос на подтверждение email по ссылке из письма
+type VerifyEmailRequest struct {
+	Token string `query:"token" validate:"required"`
+}
+
+// ResendVerificationRequest представляет запрос на повторную отправку письма подтверждения email
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// MFALoginRequest представляет второй шаг входа: токен из ответа /login и код из приложения
+// или один из кодов восстановления
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// SSOCallbackRequest представляет возврат от внешнего провайдера входа: параметры code и state,
+// которые фронтенд получил на странице SSO_REDIRECT_URL
+type SSOCallbackRequest struct {
+	Code         string `json:"code" validate:"required,max=2048"`
+	State        string `json:"state" validate:"required,max=255"`
+	BrowserState string `json:"-"` // хеш state из cookie браузера, начавшего вход; заполняет обработчик
+}
+
+// MFAEnrollRequest представляет запрос на подключение второго фактора во время входа,
+// когда он обязателен, но еще не настроен
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFAConfirmRequest представляет запрос на подтверждение подключения второго фактора
+type MFAConfirmRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// UnlockRequest представляет запрос администратора на снятие блокировки входа
+type UnlockRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ChangeRoleRequest представляет запрос администратора на смену роли пользователя
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=employee manager admin"`
+}
+
+// CreateInvitationRequest представляет запрос на приглашение нового пользователя
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=employee manager admin"` // по умолчанию employee
+}
+
+// AcceptInvitationRequest представляет запрос на создание аккаунта по приглашению
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"` // проверяется политикой паролей
+}
+
+// ListUsersRequest представляет запрос администратора на список пользователей
+type ListUsersRequest struct {
+	Page        int    `query:"page" validate:"omitempty,min=1"`
+	PerPage     int    `query:"per_page" validate:"omitempty,min=1,max=100"`
+	Role        string `query:"role" validate:"omitempty,oneof=employee manager admin"`
+	Status      string `query:"status" validate:"omitempty,oneof=active suspended deactivated"`
+	Email       string `query:"email"`
+	CreatedFrom string `query:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,datetime=2006-01-02"` // включительно
+}
+
+// ChangeStatusRequest представляет запрос администратора на смену статуса учетной записи
+type ChangeStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active suspended deactivated"`
+	Reason string `json:"reason" validate:"max=500"` // обязательна для suspended и deactivated
+}
+
+// ResetUserRequest представляет запрос администратора на сброс учетной записи
+type ResetUserRequest struct {
+	ResetMFA bool `json:"reset_mfa"` // отключить второй фактор, например при потере телефона
+}
+
+// CreateClientRequest представляет запрос администратора на регистрацию API клиента.
+// Клиенту OpenID Connect права не нужны, достаточно адресов возврата.
+type CreateClientRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	Scopes       []string `json:"scopes" validate:"required_without=RedirectURIs,omitempty,client_scopes"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,max=10,dive,redirect_uri"`
+}
+
+// CreateAPIKeyRequest представляет запрос на создание персонального API ключа.
+// Без scopes ключ получает все права роли владельца, без expires_at (RFC 3339) действует до отзыва.
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" validate:"required,max=100"`
+	Scopes    []string `json:"scopes" validate:"max=20"`
+	ExpiresAt string   `json:"expires_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// ClientTokenRequest представляет запрос токена по OAuth2 client_credentials (RFC 6749, раздел 4.4).
+// client_id и client_secret приходят в теле или в заголовке Authorization: Basic.
+type ClientTokenRequest struct {
+	GrantType    string `form:"grant_type" json:"grant_type"`
+	ClientID     string `form:"client_id" json:"client_id"`
+	ClientSecret string `form:"client_secret" json:"client_secret"`
+	Scope        string `form:"scope" json:"scope"` // права через пробел, пусто - все права клиента
+
+	// Поля grant_type=authorization_code (OpenID Connect)
+	Code         string `form:"code" json:"code"`
+	RedirectURI  string `form:"redirect_uri" json:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier" json:"code_verifier"`
+}
+
+// AuthorizeRequest представляет запрос авторизации OpenID Connect (authorization code с PKCE).
+// Приходит в query GET /oauth/authorize и в теле POST /api/v1/oauth/authorize.
+type AuthorizeRequest struct {
+	ResponseType        string `query:"response_type" json:"response_type"`
+	ClientID            string `query:"client_id" json:"client_id"`
+	RedirectURI         string `query:"redirect_uri" json:"redirect_uri"`
+	Scope               string `query:"scope" json:"scope"`
+	State               string `query:"state" json:"state"`
+	Nonce               string `query:"nonce" json:"nonce"`
+	CodeChallenge       string `query:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `query:"code_challenge_method" json:"code_challenge_method"`
+}
+
+// ValidateTokenRequest представляет запрос сервиса на проверку токена пользователя
+type ValidateTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -1,11 +1,26 @@
 package responses
 
-import "github.com/avangero/auth-service/internal/models"
+import (
+	"time"
+
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+)
 
 // TokenResponse представляет ответ с JWT токеном
+// Если для входа требуется подтвержденный email, после регистрации токены не выдаются.
+// Если требуется второй фактор, вместо токенов выдается MFAToken для POST /api/v1/login/mfa.
 type TokenResponse struct {
-	Token string      `json:"token"`
-	User  models.User `json:"user"`
+	Token        string      `json:"token,omitempty"`
+	RefreshToken string      `json:"refresh_token,omitempty"`
+	ExpiresIn    int64       `json:"expires_in,omitempty"`
+	User         models.User `json:"user"`
+
+	EmailVerificationRequired bool     `json:"email_verification_required,omitempty"`
+	MFARequired               bool     `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired     bool     `json:"mfa_enrollment_required,omitempty"`
+	MFAToken                  string   `json:"mfa_token,omitempty"`
+	RecoveryCodes             []string `json:"recovery_codes,omitempty"` // только при подключении второго фактора во время входа
 }
 
 // ErrorResponse представляет ответ с ошибкой
@@ -15,6 +30,11 @@ type ErrorResponse struct {
 	Details string `json:"details,omitempty"`
 }
 
+// MessageResponse представляет ответ с информационным сообщением
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
 // StatusResponse представляет ответ о статусе
 type StatusResponse struct {
 	Service string `json:"service"`
@@ -24,6 +44,168 @@ type StatusResponse struct {
 
 // ValidationResponse представляет ответ валидации токена
 type ValidationResponse struct {
-	Valid bool        `json:"valid"`
-	User  models.User `json:"user"`
+	Valid       bool                `json:"valid"`
+	User        models.User         `json:"user"`
+	Permissions []models.Permission `json:"permissions"` // права роли пользователя для проверки в других сервисах
+}
+
+// APIClientCreatedResponse представляет зарегистрированного API клиента.
+// Секрет возвращается только здесь, в БД хранится его хеш.
+type APIClientCreatedResponse struct {
+	Client       models.APIClient `json:"client"`
+	ClientSecret string           `json:"client_secret"`
+}
+
+// APIKeyCreatedResponse представляет созданный API ключ.
+// Ключ целиком возвращается только здесь, в БД хранится его хеш.
+type APIKeyCreatedResponse struct {
+	APIKey models.APIKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
+
+// APIKeyListResponse представляет действующие API ключи пользователя
+type APIKeyListResponse struct {
+	APIKeys []models.APIKey `json:"api_keys"`
+}
+
+// ClientTokenResponse представляет ответ эндпоинта токенов OAuth2
+type ClientTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+	IDToken     string `json:"id_token,omitempty"` // только для grant_type=authorization_code
+}
+
+// AuthorizeResponse представляет результат авторизации OpenID Connect: адрес внешнего сервиса
+// с кодом или ошибкой, на который фронтенд перенаправляет пользователя
+type AuthorizeResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// UserInfoResponse представляет ответ userinfo OpenID Connect.
+// Email и EmailVerified возвращаются только при выданном scope email.
+type UserInfoResponse struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Role          string `json:"role"`
+}
+
+// OIDCDiscoveryResponse представляет документ /.well-known/openid-configuration
+type OIDCDiscoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// OAuthErrorResponse представляет ошибку эндпоинта токенов OAuth2 (RFC 6749, раздел 5.2)
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// MFAEnrollmentResponse представляет секрет TOTP для добавления в приложение-аутентификатор
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFARecoveryCodesResponse представляет коды восстановления, которые показываются один раз
+type MFARecoveryCodesResponse struct {
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UserListResponse представляет страницу списка пользователей
+type UserListResponse struct {
+	Users   []models.User `json:"users"`
+	Total   int           `json:"total"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+}
+
+// AccountExportResponse представляет все данные, которые сервис хранит о пользователе
+type AccountExportResponse struct {
+	ExportedAt   time.Time           `json:"exported_at"`
+	User         models.User         `json:"user"`
+	RoleChanges  []models.RoleChange `json:"role_changes"`
+	Invitations  []models.Invitation `json:"invitations"`
+	MFA          *MFAExport          `json:"mfa"`           // nil, если второй фактор не подключался
+	Sessions     []SessionExport     `json:"sessions"`      // выданные refresh токены без самих токенов
+	Devices      []models.Session    `json:"devices"`       // сессии входа с устройствами и IP адресами
+	APIKeys      []models.APIKey     `json:"api_keys"`      // персональные API ключи без их значений
+	Identities   []IdentityExport    `json:"identities"`    // учетные записи во внешних провайдерах входа
+	Groups       []GroupExport       `json:"groups"`        // группы сотрудников из кадровой системы
+	LoginAttempt *LoginAttemptExport `json:"login_attempt"` // nil, если неудачных попыток входа нет
+}
+
+// IdentityExport представляет привязанную учетную запись внешнего провайдера
+type IdentityExport struct {
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	LinkedAt    time.Time  `json:"linked_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// GroupExport представляет группу, в которой состоит пользователь
+type GroupExport struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+}
+
+// MFAExport представляет настройку второго фактора без секрета
+type MFAExport struct {
+	Enabled     bool       `json:"enabled"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SessionExport представляет выданный refresh токен без его значения и хеша
+type SessionExport struct {
+	ID        uuid.UUID  `json:"id"`
+	FamilyID  uuid.UUID  `json:"family_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// SessionResponse представляет активную сессию пользователя
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // сессия, которой принадлежит токен запроса
+}
+
+// SessionListResponse представляет список активных сессий пользователя
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+// LoginAttemptExport представляет счетчик неудачных попыток входа в аккаунт
+type LoginAttemptExport struct {
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+}
+
+// SSOProvidersResponse представляет список внешних провайдеров входа для кнопок на странице входа
+type SSOProvidersResponse struct {
+	Providers []string `json:"providers"`
 }
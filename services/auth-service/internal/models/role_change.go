@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Источники смены роли
+const (
+	RoleChangeSourceAdmin      = "admin"      // смена роли администратором
+	RoleChangeSourceInvitation = "invitation" // роль из приглашения при создании аккаунта
+	RoleChangeSourceDirectory  = "directory"  // роль по группам корпоративного каталога
+)
+
+// RoleChange представляет запись журнала смены ролей
+type RoleChange struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	OldRole   *string    `json:"old_role" db:"old_role"` // nil - роль назначена при создании аккаунта
+	NewRole   string     `json:"new_role" db:"new_role"`
+	ChangedBy *uuid.UUID `json:"changed_by" db:"changed_by"` // nil, если автор изменения удален
+	Source    string     `json:"source" db:"source"`
+	Created   time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientInfo представляет сведения о клиенте, от которого пришел запрос
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Session представляет сессию входа на одном устройстве.
+// ID совпадает с цепочкой refresh токенов (FamilyID) и попадает в access токен как sid.
+type Session struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IP         string     `json:"ip" db:"ip"`
+	Created    time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"` // время последнего обновления токенов
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`     // истечение последнего refresh токена
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// IsActive проверяет, что сессия не завершена и не истекла
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
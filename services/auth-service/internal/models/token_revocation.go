@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken представляет отозванный access токен (по jti).
+// Запись нужна только до истечения срока действия токена.
+type RevokedToken struct {
+	JTI       uuid.UUID `db:"jti"`
+	UserID    uuid.UUID `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+	RevokedAt time.Time `db:"revoked_at"`
+}
+
+// UserTokenRevocation представляет отзыв всех токенов пользователя,
+// выпущенных до момента RevokedBefore ("выйти на всех устройствах")
+type UserTokenRevocation struct {
+	UserID        uuid.UUID `db:"user_id"`
+	RevokedBefore time.Time `db:"revoked_before"`
+}
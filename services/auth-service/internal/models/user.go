@@ -6,20 +6,69 @@ import (
 	"github.com/google/uuid"
 )
 
+// Роли пользователей
+const (
+	RoleEmployee = "employee"
+	RoleManager  = "manager"
+	RoleAdmin    = "admin"
+)
+
+// Статусы учетной записи
+const (
+	StatusActive      = "active"
+	StatusSuspended   = "suspended"   // временно приостановлена, например на время проверки
+	StatusDeactivated = "deactivated" // сотрудник уволен
+)
+
 // User представляет модель пользователя в системе
 type User struct {
 	ID       uuid.UUID `json:"id" db:"id"`
 	Email    string    `json:"email" db:"email" validate:"required,email"`
 	Password string    `json:"-" db:"password_hash"` // не возвращается в JSON
-	Role     string    `json:"role" db:"role" validate:"required,oneof=employee manager"`
+	Role     string    `json:"role" db:"role" validate:"required,oneof=employee manager admin"`
 	Created  time.Time `json:"created_at" db:"created_at"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at" db:"email_verified_at"` // nil, пока email не подтвержден
+
+	Status          string     `json:"status" db:"status"`
+	StatusReason    *string    `json:"status_reason,omitempty" db:"status_reason"`         // причина приостановки или деактивации
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty" db:"status_changed_at"` // nil, пока статус не менялся
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // nil, пока учетная запись не удалена
+	ErasedAt  *time.Time `json:"erased_at,omitempty" db:"erased_at"`   // nil, пока персональные данные не стерты
+}
+
+// IsEmailVerified проверяет, подтвердил ли пользователь email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasLocalPassword проверяет, есть ли у пользователя пароль в БД. Учетные записи, созданные
+// каталогом, SSO или кадровой системой, хранятся без него и входят через внешний источник
+func (u *User) HasLocalPassword() bool {
+	return u.Password != ""
+}
+
+// IsActive проверяет, что учетная запись не приостановлена и не деактивирована
+func (u *User) IsActive() bool {
+	return u.Status != StatusSuspended && u.Status != StatusDeactivated
+}
+
+// UserFilter задает отбор и страницу в списке пользователей
+type UserFilter struct {
+	Role        string     // пустая строка - любая роль
+	Status      string     // пустая строка - любой статус
+	Email       string     // подстрока email без учета регистра
+	CreatedFrom *time.Time // включительно
+	CreatedTo   *time.Time // не включительно
+	Limit       int
+	Offset      int
 }
 
 // RegisterRequest представляет запрос на регистрацию
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
-	Role     string `json:"role" validate:"required,oneof=employee manager"`
 }
 
 // LoginRequest представляет запрос на вход
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdentityProviderSCIM провайдер, под которым хранится externalId пользователя из кадровой системы
+const IdentityProviderSCIM = "scim"
+
+// UserIdentity представляет учетную запись пользователя во внешнем провайдере входа.
+// Пользователь находится по паре провайдер + sub, поэтому смена email в провайдере не мешает входу.
+type UserIdentity struct {
+	Provider    string     `json:"provider" db:"provider"`
+	Subject     string     `json:"-" db:"subject"`
+	UserID      uuid.UUID  `json:"-" db:"user_id"`
+	Email       string     `json:"email" db:"email"`
+	Created     time.Time  `json:"created_at" db:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at" db:"last_login_at"`
+}
+
+// SSOState представляет незавершенный вход через внешний провайдер.
+// В БД хранится только SHA-256 хеш state, который провайдер возвращает вместе с кодом.
+type SSOState struct {
+	StateHash    string    `db:"state_hash"`
+	Provider     string    `db:"provider"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	Created      time.Time `db:"created_at"`
+}
@@ -0,0 +1,90 @@
+package passwords
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/avangero/auth-service/internal/lang"
+)
+
+// prefixLength число символов SHA-1 хеша, которые уходят в источник (k-anonymity)
+const prefixLength = 5
+
+// BreachedPasswords источник утекших паролей в формате k-anonymity: по первым пяти
+// hex-символам SHA-1 хеша возвращает остальные 35 символов всех известных хешей
+// с этим префиксом. Сам пароль и полный хеш источник не получает, поэтому файл можно
+// заменить удаленным сервисом с тем же протоколом (например, range API Have I Been Pwned).
+type BreachedPasswords interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// IsBreached проверяет пароль по источнику утекших паролей
+func IsBreached(ctx context.Context, source BreachedPasswords, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := source.Range(ctx, hash[:prefixLength])
+	if err != nil {
+		return false, err
+	}
+
+	for _, suffix := range suffixes {
+		if suffix == hash[prefixLength:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fileBreachedPasswords источник утекших паролей из локального файла, загруженного в память
+type fileBreachedPasswords struct {
+	ranges map[string][]string
+}
+
+// NewFileBreachedPasswords загружает файл SHA-1 хешей утекших паролей: по одному хешу в строке,
+// необязательный счетчик через двоеточие (HASH:COUNT, как в выгрузке Have I Been Pwned).
+// Пустые строки и строки с # пропускаются.
+func NewFileBreachedPasswords(path string, messages lang.Messages) (BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", messages.Get(lang.BreachedListInvalid), err)
+	}
+	defer file.Close()
+
+	source := &fileBreachedPasswords{ranges: make(map[string][]string)}
+	count := 0
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("%s: %s:%d", messages.Get(lang.BreachedListInvalid), path, line)
+		}
+
+		source.ranges[hash[:prefixLength]] = append(source.ranges[hash[:prefixLength]], hash[prefixLength:])
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", messages.Get(lang.BreachedListInvalid), err)
+	}
+
+	log.Printf(messages.Get(lang.LogBreachedListLoaded), path, count)
+	return source, nil
+}
+
+// Range возвращает суффиксы хешей с указанным префиксом
+func (s *fileBreachedPasswords) Range(ctx context.Context, prefix string) ([]string, error) {
+	return s.ranges[strings.ToUpper(prefix)], nil
+}
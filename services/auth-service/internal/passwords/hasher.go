@@ -0,0 +1,199 @@
+package passwords
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/avangero/auth-service/internal/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrUnknownHash хеш создан другим алгоритмом или поврежден
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Hasher интерфейс хеширования паролей. Алгоритм и параметры хранятся в самой строке хеша,
+// поэтому хеши, созданные с прежними настройками, продолжают проверяться.
+type Hasher interface {
+	// Hash хеширует пароль текущим алгоритмом с текущими параметрами
+	Hash(password string) (string, error)
+	// Verify сравнивает пароль с хешем; ErrUnknownHash - хеш не распознан
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash сообщает, что хеш создан другим алгоритмом или слабее текущих параметров
+	NeedsRehash(hash string) bool
+}
+
+// NewHasher создает хешер по настройкам: новые пароли хешируются алгоритмом cfg.Algorithm,
+// хеши второго поддерживаемого алгоритма проверяются и помечаются для перехеширования.
+func NewHasher(cfg config.PasswordHashConfig, bcryptCost int) Hasher {
+	bcryptHasher := NewBcryptHasher(bcryptCost)
+	argon2idHasher := NewArgon2idHasher(Argon2idParams{
+		Memory:      cfg.Argon2Memory,
+		Iterations:  cfg.Argon2Iterations,
+		Parallelism: cfg.Argon2Parallelism,
+	})
+
+	if cfg.Algorithm == config.PasswordHashBcrypt {
+		return &chainHasher{current: bcryptHasher, legacy: []Hasher{argon2idHasher}}
+	}
+	return &chainHasher{current: argon2idHasher, legacy: []Hasher{bcryptHasher}}
+}
+
+// chainHasher хеширует текущим алгоритмом и проверяет хеши всех поддерживаемых
+type chainHasher struct {
+	current Hasher
+	legacy  []Hasher
+}
+
+// Hash хеширует пароль текущим алгоритмом
+func (h *chainHasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify проверяет пароль алгоритмом, которым создан хеш
+func (h *chainHasher) Verify(hash, password string) (bool, error) {
+	for _, hasher := range append([]Hasher{h.current}, h.legacy...) {
+		ok, err := hasher.Verify(hash, password)
+		if !errors.Is(err, ErrUnknownHash) {
+			return ok, err
+		}
+	}
+	return false, ErrUnknownHash
+}
+
+// NeedsRehash сравнивает хеш с текущим алгоритмом и параметрами
+func (h *chainHasher) NeedsRehash(hash string) bool {
+	return h.current.NeedsRehash(hash)
+}
+
+// bcryptHasher хеширование bcrypt ($2a$, $2b$, $2y$)
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher создает хешер bcrypt с указанной стоимостью
+func NewBcryptHasher(cost int) Hasher {
+	return &bcryptHasher{cost: cost}
+}
+
+// Hash хеширует пароль bcrypt
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(hash), err
+}
+
+// Verify сравнивает пароль с хешем bcrypt
+func (h *bcryptHasher) Verify(hash, password string) (bool, error) {
+	if !isBcrypt(hash) {
+		return false, ErrUnknownHash
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NeedsRehash проверяет, что хеш bcrypt создан со стоимостью не ниже текущей
+func (h *bcryptHasher) NeedsRehash(hash string) bool {
+	if !isBcrypt(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < h.cost
+}
+
+// isBcrypt распознает хеш bcrypt по префиксу
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Argon2idParams параметры argon2id
+type Argon2idParams struct {
+	Memory      uint32 // память в КиБ
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Размеры соли и ключа argon2id в байтах
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// argon2idHasher хеширование argon2id в формате PHC:
+// $argon2id$v=19$m=<память>,t=<итерации>,p=<потоки>$<соль>$<ключ>
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher создает хешер argon2id с указанными параметрами
+func NewArgon2idHasher(params Argon2idParams) Hasher {
+	return &argon2idHasher{params: params}
+}
+
+// Hash хеширует пароль argon2id со случайной солью
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, argon2idKeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify сравнивает пароль с хешем argon2id за постоянное время
+func (h *argon2idHasher) Verify(hash, password string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+}
+
+// NeedsRehash проверяет, что ни один параметр хеша не ниже текущего
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory < h.params.Memory || params.Iterations < h.params.Iterations || params.Parallelism < h.params.Parallelism
+}
+
+// decodeArgon2id разбирает хеш argon2id в формате PHC
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+	var version int
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	return params, salt, key, nil
+}
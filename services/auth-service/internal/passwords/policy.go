@@ -0,0 +1,133 @@
+package passwords
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+)
+
+// Правила политики паролей. Значения совпадают с тегами валидации,
+// по которым lang.Messages подбирает текст ошибки.
+const (
+	RuleMinLength = "password_min"
+	RuleMaxBytes  = "password_max"
+	RuleLower     = "password_lower"
+	RuleUpper     = "password_upper"
+	RuleDigit     = "password_digit"
+	RuleSymbol    = "password_symbol"
+	RuleEmail     = "password_email"
+	RuleBreached  = "password_breached"
+)
+
+// Значения по умолчанию
+const (
+	DefaultMinLength = 8
+	MaxBytes         = 72 // bcrypt игнорирует байты пароля после 72-го
+)
+
+// Violation нарушенное правило политики и его параметр (например, минимальная длина)
+type Violation struct {
+	Rule  string
+	Param string
+}
+
+// Policy интерфейс проверки нового пароля
+type Policy interface {
+	// Check возвращает все нарушенные правила; email владельца может быть пустым
+	Check(ctx context.Context, password, email string) []Violation
+}
+
+// policy реализация Policy
+type policy struct {
+	cfg      config.PasswordPolicyConfig
+	breached BreachedPasswords
+	messages lang.Messages
+}
+
+// NewPolicy создает политику паролей. breached может быть nil - тогда список утекших паролей не проверяется.
+func NewPolicy(cfg config.PasswordPolicyConfig, breached BreachedPasswords, messages lang.Messages) Policy {
+	return &policy{
+		cfg:      cfg,
+		breached: breached,
+		messages: messages,
+	}
+}
+
+// DefaultPolicy возвращает политику с настройками по умолчанию без проверки утекших паролей
+func DefaultPolicy(messages lang.Messages) Policy {
+	return NewPolicy(config.PasswordPolicyConfig{MinLength: DefaultMinLength, MaxBytes: MaxBytes}, nil, messages)
+}
+
+// Check проверяет пароль по всем правилам политики
+func (p *policy) Check(ctx context.Context, password, email string) []Violation {
+	var violations []Violation
+
+	// Длина считается в символах, а ограничение bcrypt - в байтах
+	if utf8.RuneCountInString(password) < p.cfg.MinLength {
+		violations = append(violations, Violation{Rule: RuleMinLength, Param: strconv.Itoa(p.cfg.MinLength)})
+	}
+	if len(password) > p.cfg.MaxBytes {
+		violations = append(violations, Violation{Rule: RuleMaxBytes, Param: strconv.Itoa(p.cfg.MaxBytes)})
+	}
+
+	for _, class := range p.cfg.RequiredClasses {
+		if rule, ok := missingClass(password, class); ok {
+			violations = append(violations, Violation{Rule: rule})
+		}
+	}
+
+	if email != "" && MatchesEmail(password, email) {
+		violations = append(violations, Violation{Rule: RuleEmail})
+	}
+
+	if p.breached != nil {
+		breached, err := IsBreached(ctx, p.breached, password)
+		if err != nil {
+			// Недоступный список не должен блокировать смену пароля
+			log.Printf(p.messages.Get(lang.LogBreachedCheckFailed), err)
+		} else if breached {
+			violations = append(violations, Violation{Rule: RuleBreached})
+		}
+	}
+
+	return violations
+}
+
+// missingClass возвращает правило, если в пароле нет ни одного символа класса
+func missingClass(password, class string) (string, bool) {
+	var rule string
+	var matches func(rune) bool
+
+	switch class {
+	case config.PasswordClassLower:
+		rule, matches = RuleLower, unicode.IsLower
+	case config.PasswordClassUpper:
+		rule, matches = RuleUpper, unicode.IsUpper
+	case config.PasswordClassDigit:
+		rule, matches = RuleDigit, unicode.IsDigit
+	case config.PasswordClassSymbol:
+		rule, matches = RuleSymbol, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+		}
+	default:
+		return "", false
+	}
+
+	return rule, strings.IndexFunc(password, matches) < 0
+}
+
+// MatchesEmail проверяет, совпадает ли пароль с email или его частью до @ без учета регистра.
+// Используется там, где email владельца известен только сервису, а не запросу.
+func MatchesEmail(password, email string) bool {
+	localPart := email
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		localPart = email[:at]
+	}
+	return strings.EqualFold(password, email) || strings.EqualFold(password, localPart)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// APIClientRepository интерфейс для работы с API клиентами сервисов
+type APIClientRepository interface {
+	Create(ctx context.Context, client *models.APIClient) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.APIClient, error)
+	List(ctx context.Context) ([]models.APIClient, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// apiClientRepository реализация APIClientRepository
+type apiClientRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewAPIClientRepository создает новый экземпляр APIClientRepository
+func NewAPIClientRepository(db *sqlx.DB, messages lang.Messages) APIClientRepository {
+	return &apiClientRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет API клиента в БД
+func (r *apiClientRepository) Create(ctx context.Context, client *models.APIClient) error {
+	query := `
+		INSERT INTO api_clients (id, name, secret_hash, scope, redirect_uris, created_by, created_at)
+		VALUES (:id, :name, :secret_hash, :scope, :redirect_uris, :created_by, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, client); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIClientDBError), client.ID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID находит API клиента по client_id, включая отозванных
+func (r *apiClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIClient, error) {
+	var client models.APIClient
+	query := "SELECT * FROM api_clients WHERE id = $1"
+
+	if err := r.db.GetContext(ctx, &client, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии клиента
+		}
+		log.Printf(r.messages.Get(lang.LogAPIClientDBError), id.String(), err)
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// List возвращает всех API клиентов, новые первыми
+func (r *apiClientRepository) List(ctx context.Context) ([]models.APIClient, error) {
+	clients := []models.APIClient{}
+	query := "SELECT * FROM api_clients ORDER BY created_at DESC"
+
+	if err := r.db.SelectContext(ctx, &clients, query); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIClientDBError), "list", err)
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// Revoke отзывает API клиента; выданные ему токены перестают приниматься сразу
+func (r *apiClientRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := "UPDATE api_clients SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL"
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIClientDBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
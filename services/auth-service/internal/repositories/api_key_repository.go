@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// APIKeyRepository интерфейс для работы с персональными API ключами
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.APIKey, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// apiKeyRepository реализация APIKeyRepository
+type apiKeyRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewAPIKeyRepository создает новый экземпляр APIKeyRepository
+func NewAPIKeyRepository(db *sqlx.DB, messages lang.Messages) APIKeyRepository {
+	return &apiKeyRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет API ключ в БД
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scope, expires_at, created_at)
+		VALUES (:id, :user_id, :name, :prefix, :key_hash, :scope, :expires_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, key); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), key.ID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID находит API ключ по ID, включая отозванные и истекшие
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	query := "SELECT * FROM api_keys WHERE id = $1"
+
+	if err := r.db.GetContext(ctx, &key, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии ключа
+		}
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), id.String(), err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// GetByHash находит API ключ по хешу предъявленного значения
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	query := "SELECT * FROM api_keys WHERE key_hash = $1"
+
+	if err := r.db.GetContext(ctx, &key, query, keyHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии ключа
+		}
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), "hash", err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// ListActiveByUser возвращает неотозванные и неистекшие ключи пользователя, новые первыми
+func (r *apiKeyRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	query := `
+		SELECT * FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)
+		ORDER BY created_at DESC`
+
+	if err := r.db.SelectContext(ctx, &keys, query, userID, now); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// ListByUser возвращает все ключи пользователя, включая отозванные и истекшие
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	query := "SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at"
+
+	if err := r.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Touch запоминает время последнего использования ключа
+func (r *apiKeyRepository) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := "UPDATE api_keys SET last_used_at = $1 WHERE id = $2"
+
+	if _, err := r.db.ExecContext(ctx, query, usedAt, id); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Revoke отзывает API ключ
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := "UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL"
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		log.Printf(r.messages.Get(lang.LogAPIKeyDBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// AuthorizationCodeRepository интерфейс для работы с кодами авторизации OpenID Connect
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *models.AuthorizationCode) error
+	Consume(ctx context.Context, codeHash string) (*models.AuthorizationCode, error)
+}
+
+// authorizationCodeRepository реализация AuthorizationCodeRepository
+type authorizationCodeRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewAuthorizationCodeRepository создает новый экземпляр AuthorizationCodeRepository
+func NewAuthorizationCodeRepository(db *sqlx.DB, messages lang.Messages) AuthorizationCodeRepository {
+	return &authorizationCodeRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет код авторизации в БД
+func (r *authorizationCodeRepository) Create(ctx context.Context, code *models.AuthorizationCode) error {
+	query := `
+		INSERT INTO authorization_codes (code_hash, client_id, user_id, session_id, redirect_uri, scope, nonce, code_challenge, expires_at, created_at)
+		VALUES (:code_hash, :client_id, :user_id, :session_id, :redirect_uri, :scope, :nonce, :code_challenge, :expires_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, code); err != nil {
+		log.Printf(r.messages.Get(lang.LogAuthorizationCodeDBError), code.ClientID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Consume атомарно помечает код использованным и возвращает его.
+// Если код не найден или уже использован, возвращает nil, nil.
+func (r *authorizationCodeRepository) Consume(ctx context.Context, codeHash string) (*models.AuthorizationCode, error) {
+	var code models.AuthorizationCode
+	query := `
+		UPDATE authorization_codes SET used_at = NOW()
+		WHERE code_hash = $1 AND used_at IS NULL
+		RETURNING *`
+
+	if err := r.db.GetContext(ctx, &code, query, codeHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Код не найден или уже использован
+		}
+		log.Printf(r.messages.Get(lang.LogAuthorizationCodeDBError), "hash", err)
+		return nil, err
+	}
+
+	return &code, nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// EmailVerificationRepository интерфейс для работы с токенами подтверждения email
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *models.EmailVerificationToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// emailVerificationRepository реализация EmailVerificationRepository
+type emailVerificationRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewEmailVerificationRepository создает новый экземпляр EmailVerificationRepository
+func NewEmailVerificationRepository(db *sqlx.DB, messages lang.Messages) EmailVerificationRepository {
+	return &emailVerificationRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет токен подтверждения email в БД
+func (r *emailVerificationRepository) Create(ctx context.Context, token *models.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES (:id, :user_id, :token_hash, :expires_at, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogEmailVerificationDBError), token.UserID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByHash находит токен подтверждения email по хешу
+func (r *emailVerificationRepository) GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	var token models.EmailVerificationToken
+	query := "SELECT * FROM email_verification_tokens WHERE token_hash = $1"
+
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии токена
+		}
+		log.Printf(r.messages.Get(lang.LogEmailVerificationDBError), "hash", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed атомарно помечает токен использованным.
+// Возвращает false, если токен уже был использован параллельным запросом.
+func (r *emailVerificationRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := "UPDATE email_verification_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogEmailVerificationDBError), id.String(), err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogEmailVerificationDBError), id.String(), err)
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// InvalidateForUser погашает все неиспользованные токены пользователя
+func (r *emailVerificationRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	query := "UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL"
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogEmailVerificationDBError), userID.String(), err)
+		return err
+	}
+
+	return nil
+}
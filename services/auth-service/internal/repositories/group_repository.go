@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// GroupRepository интерфейс для работы с группами сотрудников и их участниками
+type GroupRepository interface {
+	Create(ctx context.Context, group *models.Group) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Group, error)
+	List(ctx context.Context, filter models.GroupFilter) ([]models.Group, int, error)
+	Update(ctx context.Context, group *models.Group, changes []models.GroupMembersChange) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Group, error)
+	AddMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error
+}
+
+// groupRepository реализация GroupRepository
+type groupRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewGroupRepository создает новый экземпляр GroupRepository
+func NewGroupRepository(db *sqlx.DB, messages lang.Messages) GroupRepository {
+	return &groupRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет новую группу
+func (r *groupRepository) Create(ctx context.Context, group *models.Group) error {
+	query := `
+		INSERT INTO groups (id, display_name, external_id, created_at, updated_at)
+		VALUES (:id, :display_name, :external_id, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, group); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), group.DisplayName, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID находит группу по ID
+func (r *groupRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Group, error) {
+	var group models.Group
+	query := "SELECT * FROM groups WHERE id = $1"
+
+	if err := r.db.GetContext(ctx, &group, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Группа не найдена
+		}
+		log.Printf(r.messages.Get(lang.LogGroupDBError), id.String(), err)
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// List возвращает страницу групп по фильтру и общее число подходящих групп
+func (r *groupRepository) List(ctx context.Context, filter models.GroupFilter) ([]models.Group, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.DisplayName != "" {
+		addCondition("display_name = $%d", filter.DisplayName)
+	}
+	if filter.ExternalID != "" {
+		addCondition("external_id = $%d", filter.ExternalID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM groups"+where, args...); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), "list", err)
+		return nil, 0, err
+	}
+
+	groups := []models.Group{}
+	query := fmt.Sprintf("SELECT * FROM groups%s ORDER BY created_at, id LIMIT $%d OFFSET $%d", where, len(args)+1, len(args)+2)
+	if err := r.db.SelectContext(ctx, &groups, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), "list", err)
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
+
+// Update сохраняет название, внешний идентификатор и изменения состава группы в одной
+// транзакции: PATCH и PUT из кадровой системы применяются целиком или не применяются вовсе
+func (r *groupRepository) Update(ctx context.Context, group *models.Group, changes []models.GroupMembersChange) error {
+	return r.inTx(ctx, group.ID, func(tx *sqlx.Tx) error {
+		query := "UPDATE groups SET display_name = $1, external_id = $2 WHERE id = $3"
+		if _, err := tx.ExecContext(ctx, query, group.DisplayName, group.ExternalID, group.ID); err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			var err error
+			switch change.Op {
+			case models.GroupMembersAdd:
+				err = addGroupMembers(ctx, tx, group.ID, change.UserIDs)
+			case models.GroupMembersRemove:
+				err = removeGroupMembers(ctx, tx, group.ID, change.UserIDs)
+			case models.GroupMembersReplace:
+				if _, err = tx.ExecContext(ctx, "DELETE FROM group_members WHERE group_id = $1", group.ID); err == nil {
+					err = addGroupMembers(ctx, tx, group.ID, change.UserIDs)
+				}
+			default:
+				err = fmt.Errorf("group members: %s", change.Op)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete удаляет группу вместе со списком участников
+func (r *groupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM groups WHERE id = $1", id); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// ListMembers возвращает участников группы. Удаленные пользователи не возвращаются.
+func (r *groupRepository) ListMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error) {
+	members := []models.GroupMember{}
+	query := `
+		SELECT u.id AS user_id, u.email FROM group_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.group_id = $1 AND u.deleted_at IS NULL
+		ORDER BY u.email`
+
+	if err := r.db.SelectContext(ctx, &members, query, groupID); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), groupID.String(), err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// ListByUser возвращает группы, в которых состоит пользователь
+func (r *groupRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	groups := []models.Group{}
+	query := `
+		SELECT g.* FROM groups g
+		JOIN group_members m ON m.group_id = g.id
+		WHERE m.user_id = $1
+		ORDER BY g.display_name`
+
+	if err := r.db.SelectContext(ctx, &groups, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// AddMembers добавляет пользователей в группу. Уже состоящие в группе пропускаются.
+func (r *groupRepository) AddMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	return r.inTx(ctx, groupID, func(tx *sqlx.Tx) error {
+		return addGroupMembers(ctx, tx, groupID, userIDs)
+	})
+}
+
+// inTx меняет группу в одной транзакции и обновляет время изменения группы
+func (r *groupRepository) inTx(ctx context.Context, groupID uuid.UUID, change func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), groupID.String(), err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := change(tx); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), groupID.String(), err)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE groups SET updated_at = NOW() WHERE id = $1", groupID); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), groupID.String(), err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf(r.messages.Get(lang.LogGroupDBError), groupID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// addGroupMembers добавляет участников внутри транзакции
+func addGroupMembers(ctx context.Context, tx *sqlx.Tx, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		query := "INSERT INTO group_members (group_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, query, groupID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeGroupMembers исключает участников внутри транзакции
+func removeGroupMembers(ctx context.Context, tx *sqlx.Tx, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM group_members WHERE group_id = $1 AND user_id = $2", groupID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// InvitationRepository интерфейс для работы с приглашениями
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.Invitation, error)
+	MarkAccepted(ctx context.Context, id uuid.UUID) (bool, error)
+	ListByEmail(ctx context.Context, email string) ([]models.Invitation, error)
+}
+
+// invitationRepository реализация InvitationRepository
+type invitationRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewInvitationRepository создает новый экземпляр InvitationRepository
+func NewInvitationRepository(db *sqlx.DB, messages lang.Messages) InvitationRepository {
+	return &invitationRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет приглашение в БД
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	query := `
+		INSERT INTO invitations (id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES (:id, :email, :role, :token_hash, :invited_by, :expires_at, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, invitation)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogInvitationDBError), invitation.Email, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByHash находит приглашение по хешу токена
+func (r *invitationRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	query := "SELECT * FROM invitations WHERE token_hash = $1"
+
+	err := r.db.GetContext(ctx, &invitation, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии приглашения
+		}
+		log.Printf(r.messages.Get(lang.LogInvitationDBError), "hash", err)
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// MarkAccepted атомарно помечает приглашение принятым.
+// Возвращает false, если приглашение уже было принято параллельным запросом.
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := "UPDATE invitations SET accepted_at = NOW() WHERE id = $1 AND accepted_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogInvitationDBError), id.String(), err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogInvitationDBError), id.String(), err)
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// ListByEmail возвращает все приглашения, отправленные на email, новые первыми
+func (r *invitationRepository) ListByEmail(ctx context.Context, email string) ([]models.Invitation, error) {
+	invitations := []models.Invitation{}
+	query := "SELECT * FROM invitations WHERE email = $1 ORDER BY created_at DESC"
+
+	if err := r.db.SelectContext(ctx, &invitations, query, email); err != nil {
+		log.Printf(r.messages.Get(lang.LogInvitationDBError), email, err)
+		return nil, err
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/avangero/auth-service/internal/models"
+)
+
+// memoryPruneInterval как часто удалять устаревшие счетчики из памяти
+const memoryPruneInterval = time.Minute
+
+// memoryLoginAttemptRepository реализация LoginAttemptRepository в памяти процесса.
+// Подходит для одного экземпляра сервиса и тестов: счетчики не переживают перезапуск.
+type memoryLoginAttemptRepository struct {
+	mu         sync.Mutex
+	attempts   map[string]models.LoginAttempt
+	lastPruned time.Time
+}
+
+// NewMemoryLoginAttemptRepository создает новый экземпляр LoginAttemptRepository в памяти
+func NewMemoryLoginAttemptRepository() LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{
+		attempts: make(map[string]models.LoginAttempt),
+	}
+}
+
+// Get находит счетчик попыток по ключу
+func (r *memoryLoginAttemptRepository) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &attempt, nil
+}
+
+// RegisterFailure увеличивает счетчик неудачных попыток.
+// Если последняя ошибка была раньше windowStart, счетчик начинается заново.
+func (r *memoryLoginAttemptRepository) RegisterFailure(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(now, windowStart)
+
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(windowStart) {
+		attempt = models.LoginAttempt{Key: key, LockedUntil: attempt.LockedUntil}
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	r.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+// Lock блокирует вход по ключу до указанного момента
+func (r *memoryLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+
+	return nil
+}
+
+// Reset сбрасывает счетчик и снимает блокировку
+func (r *memoryLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// prune удаляет счетчики без свежих ошибок и действующей блокировки, чтобы карта не росла бесконечно.
+// Вызывается под блокировкой mu.
+func (r *memoryLoginAttemptRepository) prune(now, windowStart time.Time) {
+	if now.Sub(r.lastPruned) < memoryPruneInterval {
+		return
+	}
+	r.lastPruned = now
+
+	for key, attempt := range r.attempts {
+		if attempt.LastFailureAt.Before(windowStart) && !attempt.IsLocked(now) {
+			delete(r.attempts, key)
+		}
+	}
+}
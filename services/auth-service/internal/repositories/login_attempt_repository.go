@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// LoginAttemptRepository интерфейс для хранения счетчиков неудачных попыток входа
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*models.LoginAttempt, error)
+	RegisterFailure(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// loginAttemptRepository реализация LoginAttemptRepository на PostgreSQL
+type loginAttemptRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewLoginAttemptRepository создает хранилище счетчиков попыток входа по конфигурации
+func NewLoginAttemptRepository(lockoutConfig config.LockoutConfig, db *sqlx.DB, messages lang.Messages) LoginAttemptRepository {
+	switch lockoutConfig.Store {
+	case "memory":
+		return NewMemoryLoginAttemptRepository()
+	default:
+		return NewPostgresLoginAttemptRepository(db, messages)
+	}
+}
+
+// NewPostgresLoginAttemptRepository создает новый экземпляр LoginAttemptRepository на PostgreSQL
+func NewPostgresLoginAttemptRepository(db *sqlx.DB, messages lang.Messages) LoginAttemptRepository {
+	return &loginAttemptRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Get находит счетчик попыток по ключу
+func (r *loginAttemptRepository) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	query := "SELECT * FROM login_attempts WHERE key = $1"
+
+	err := r.db.GetContext(ctx, &attempt, query, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии неудачных попыток
+		}
+		log.Printf(r.messages.Get(lang.LogLoginAttemptDBError), key, err)
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// RegisterFailure атомарно увеличивает счетчик неудачных попыток.
+// Если последняя ошибка была раньше windowStart, счетчик начинается заново.
+func (r *loginAttemptRepository) RegisterFailure(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`
+
+	err := r.db.GetContext(ctx, &attempt, query, key, now, windowStart)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogLoginAttemptDBError), key, err)
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// Lock блокирует вход по ключу до указанного момента
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := "UPDATE login_attempts SET locked_until = $2 WHERE key = $1"
+
+	_, err := r.db.ExecContext(ctx, query, key, until)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogLoginAttemptDBError), key, err)
+		return err
+	}
+
+	return nil
+}
+
+// Reset сбрасывает счетчик и снимает блокировку
+func (r *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	query := "DELETE FROM login_attempts WHERE key = $1"
+
+	_, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogLoginAttemptDBError), key, err)
+		return err
+	}
+
+	return nil
+}
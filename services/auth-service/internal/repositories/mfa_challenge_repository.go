@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MFAChallengeRepository интерфейс для работы с незавершенными входами, ожидающими второй фактор
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, challenge *models.MFAChallenge) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.MFAChallenge, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	IncrementAttempts(ctx context.Context, id uuid.UUID) error
+}
+
+// mfaChallengeRepository реализация MFAChallengeRepository
+type mfaChallengeRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewMFAChallengeRepository создает новый экземпляр MFAChallengeRepository
+func NewMFAChallengeRepository(db *sqlx.DB, messages lang.Messages) MFAChallengeRepository {
+	return &mfaChallengeRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет незавершенный вход в БД
+func (r *mfaChallengeRepository) Create(ctx context.Context, challenge *models.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, token_hash, expires_at, created_at)
+		VALUES (:id, :user_id, :token_hash, :expires_at, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, challenge)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), challenge.UserID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByHash находит незавершенный вход по хешу токена
+func (r *mfaChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	query := "SELECT * FROM mfa_challenges WHERE token_hash = $1"
+
+	err := r.db.GetContext(ctx, &challenge, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии входа
+		}
+		log.Printf(r.messages.Get(lang.LogMFADBError), "hash", err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// MarkUsed атомарно завершает вход.
+// Возвращает false, если вход уже завершен параллельным запросом.
+func (r *mfaChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := "UPDATE mfa_challenges SET used_at = NOW() WHERE id = $1 AND used_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), id.String(), err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), id.String(), err)
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// IncrementAttempts увеличивает счетчик неудачных попыток ввода кода
+func (r *mfaChallengeRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	query := "UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1"
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
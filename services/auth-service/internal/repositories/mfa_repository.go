@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MFARepository интерфейс для работы с TOTP секретами и кодами восстановления
+type MFARepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserMFA, error)
+	SavePending(ctx context.Context, mfa *models.UserMFA) error
+	Confirm(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []models.MFARecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// mfaRepository реализация MFARepository
+type mfaRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewMFARepository создает новый экземпляр MFARepository
+func NewMFARepository(db *sqlx.DB, messages lang.Messages) MFARepository {
+	return &mfaRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// GetByUserID находит настройку второго фактора пользователя
+func (r *mfaRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	query := "SELECT * FROM user_mfa WHERE user_id = $1"
+
+	err := r.db.GetContext(ctx, &mfa, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии настройки
+		}
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return nil, err
+	}
+
+	return &mfa, nil
+}
+
+// SavePending сохраняет новый неподтвержденный секрет.
+// Подтвержденный секрет не перезаписывается.
+func (r *mfaRepository) SavePending(ctx context.Context, mfa *models.UserMFA) error {
+	query := `
+		INSERT INTO user_mfa (user_id, secret, created_at)
+		VALUES (:user_id, :secret, :created_at)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, created_at = EXCLUDED.created_at, last_used_step = 0
+		WHERE user_mfa.confirmed_at IS NULL`
+
+	_, err := r.db.NamedExecContext(ctx, query, mfa)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), mfa.UserID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Confirm атомарно подтверждает подключение второго фактора и запоминает использованный шаг.
+// Возвращает false, если подключение уже подтверждено параллельным запросом.
+func (r *mfaRepository) Confirm(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := "UPDATE user_mfa SET confirmed_at = NOW(), last_used_step = $2 WHERE user_id = $1 AND confirmed_at IS NULL"
+
+	return r.execAffected(ctx, userID, query, userID, step)
+}
+
+// UseStep атомарно запоминает принятый шаг TOTP.
+// Возвращает false, если этот или более поздний шаг уже был использован.
+func (r *mfaRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := "UPDATE user_mfa SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2"
+
+	return r.execAffected(ctx, userID, query, userID, step)
+}
+
+// ReplaceRecoveryCodes заменяет все коды восстановления пользователя новым набором
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []models.MFARecoveryCode) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+		VALUES (:id, :user_id, :code_hash, :created_at)`
+
+	for i := range codes {
+		if _, err := tx.NamedExecContext(ctx, query, &codes[i]); err != nil {
+			log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// UseRecoveryCode атомарно погашает код восстановления.
+// Возвращает false, если код не найден или уже использован.
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	query := "UPDATE mfa_recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL"
+
+	return r.execAffected(ctx, userID, query, userID, codeHash)
+}
+
+// Delete отключает второй фактор: удаляет секрет и коды восстановления
+func (r *mfaRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_mfa WHERE user_id = $1", userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogMFARemoved), userID.String())
+	return nil
+}
+
+// execAffected выполняет обновление и сообщает, была ли затронута хотя бы одна строка
+func (r *mfaRepository) execAffected(ctx context.Context, userID uuid.UUID, query string, args ...interface{}) (bool, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogMFADBError), userID.String(), err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
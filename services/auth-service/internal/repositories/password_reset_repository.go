@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// PasswordResetRepository интерфейс для работы с токенами сброса пароля
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// passwordResetRepository реализация PasswordResetRepository
+type passwordResetRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewPasswordResetRepository создает новый экземпляр PasswordResetRepository
+func NewPasswordResetRepository(db *sqlx.DB, messages lang.Messages) PasswordResetRepository {
+	return &passwordResetRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет токен сброса пароля в БД
+func (r *passwordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES (:id, :user_id, :token_hash, :expires_at, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogPasswordResetDBError), token.UserID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByHash находит токен сброса пароля по хешу
+func (r *passwordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	query := "SELECT * FROM password_reset_tokens WHERE token_hash = $1"
+
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии токена
+		}
+		log.Printf(r.messages.Get(lang.LogPasswordResetDBError), "hash", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed атомарно помечает токен использованным.
+// Возвращает false, если токен уже был использован параллельным запросом.
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := "UPDATE password_reset_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogPasswordResetDBError), id.String(), err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogPasswordResetDBError), id.String(), err)
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// InvalidateForUser погашает все неиспользованные токены пользователя
+func (r *passwordResetRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	query := "UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL"
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogPasswordResetDBError), userID.String(), err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RefreshTokenRepository интерфейс для работы с refresh токенами
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeByUser(ctx context.Context, userID uuid.UUID) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error)
+}
+
+// refreshTokenRepository реализация RefreshTokenRepository
+type refreshTokenRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewRefreshTokenRepository создает новый экземпляр RefreshTokenRepository
+func NewRefreshTokenRepository(db *sqlx.DB, messages lang.Messages) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет refresh токен в БД
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES (:id, :user_id, :family_id, :token_hash, :expires_at, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRefreshTokenCreateFailed), token.UserID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByHash находит refresh токен по хешу
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	query := "SELECT * FROM refresh_tokens WHERE token_hash = $1"
+
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Возвращаем nil, nil при отсутствии токена
+		}
+		log.Printf(r.messages.Get(lang.LogRefreshTokenDBError), "hash", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed атомарно помечает токен использованным.
+// Возвращает false, если токен уже был использован или отозван (например, при гонке запросов).
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := "UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRefreshTokenDBError), id.String(), err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRefreshTokenDBError), id.String(), err)
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeFamily отзывает все токены цепочки
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL"
+
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRefreshTokenDBError), familyID.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogRefreshFamilyRevoked), familyID.String())
+	return nil
+}
+
+// RevokeByUser отзывает все refresh токены пользователя
+func (r *refreshTokenRepository) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL"
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRefreshTokenDBError), userID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// ListByUser возвращает все сохраненные refresh токены пользователя, новые первыми
+func (r *refreshTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error) {
+	tokens := []models.RefreshToken{}
+	query := "SELECT * FROM refresh_tokens WHERE user_id = $1 ORDER BY created_at DESC"
+
+	if err := r.db.SelectContext(ctx, &tokens, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogRefreshTokenDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
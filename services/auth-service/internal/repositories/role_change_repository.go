@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RoleChangeRepository интерфейс для работы с журналом смены ролей
+type RoleChangeRepository interface {
+	Create(ctx context.Context, change *models.RoleChange) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RoleChange, error)
+}
+
+// roleChangeRepository реализация RoleChangeRepository
+type roleChangeRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewRoleChangeRepository создает новый экземпляр RoleChangeRepository
+func NewRoleChangeRepository(db *sqlx.DB, messages lang.Messages) RoleChangeRepository {
+	return &roleChangeRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create добавляет запись в журнал смены ролей
+func (r *roleChangeRepository) Create(ctx context.Context, change *models.RoleChange) error {
+	query := `
+		INSERT INTO role_changes (id, user_id, old_role, new_role, changed_by, source, created_at)
+		VALUES (:id, :user_id, :old_role, :new_role, :changed_by, :source, :created_at)`
+
+	_, err := r.db.NamedExecContext(ctx, query, change)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRoleChangeDBError), change.UserID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// ListByUser возвращает историю смены ролей пользователя, начиная с последней
+func (r *roleChangeRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RoleChange, error) {
+	changes := []models.RoleChange{}
+	query := "SELECT * FROM role_changes WHERE user_id = $1 ORDER BY created_at DESC"
+
+	if err := r.db.SelectContext(ctx, &changes, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogRoleChangeDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// SessionRepository интерфейс для работы с сессиями входа
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error)
+	ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.Session, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	Touch(ctx context.Context, id uuid.UUID, client models.ClientInfo, expiresAt time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeByUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// sessionRepository реализация SessionRepository
+type sessionRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewSessionRepository создает новый экземпляр SessionRepository
+func NewSessionRepository(db *sqlx.DB, messages lang.Messages) SessionRepository {
+	return &sessionRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет новую сессию
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES (:id, :user_id, :user_agent, :ip, :created_at, :last_seen_at, :expires_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, session); err != nil {
+		log.Printf(r.messages.Get(lang.LogSessionDBError), session.ID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID находит сессию по ID
+func (r *sessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	query := "SELECT * FROM sessions WHERE id = $1"
+
+	if err := r.db.GetContext(ctx, &session, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Printf(r.messages.Get(lang.LogSessionDBError), id.String(), err)
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// ListActiveByUser возвращает незавершенные и неистекшие сессии пользователя, последние активные первыми
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := `
+		SELECT * FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC`
+
+	if err := r.db.SelectContext(ctx, &sessions, query, userID, now); err != nil {
+		log.Printf(r.messages.Get(lang.LogSessionDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// ListByUser возвращает все сессии пользователя, включая завершенные
+func (r *sessionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := "SELECT * FROM sessions WHERE user_id = $1 ORDER BY created_at"
+
+	if err := r.db.SelectContext(ctx, &sessions, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogSessionDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Touch отмечает использование сессии: обновляет адрес, клиент и срок действия
+func (r *sessionRepository) Touch(ctx context.Context, id uuid.UUID, client models.ClientInfo, expiresAt time.Time) error {
+	query := "UPDATE sessions SET ip = $1, user_agent = $2, last_seen_at = NOW(), expires_at = $3 WHERE id = $4"
+
+	if _, err := r.db.ExecContext(ctx, query, client.IP, client.UserAgent, expiresAt, id); err != nil {
+		log.Printf(r.messages.Get(lang.LogSessionDBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Revoke завершает сессию
+func (r *sessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := "UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL"
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		log.Printf(r.messages.Get(lang.LogSessionDBError), id.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeByUser завершает все сессии пользователя
+func (r *sessionRepository) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	query := "UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL"
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogSessionDBError), userID.String(), err)
+		return err
+	}
+
+	return nil
+}
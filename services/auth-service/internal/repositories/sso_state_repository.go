@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// SSOStateRepository интерфейс для работы с незавершенными входами через внешний провайдер
+type SSOStateRepository interface {
+	Create(ctx context.Context, state *models.SSOState) error
+	Consume(ctx context.Context, stateHash string) (*models.SSOState, error)
+}
+
+// ssoStateRepository реализация SSOStateRepository
+type ssoStateRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewSSOStateRepository создает новый экземпляр SSOStateRepository
+func NewSSOStateRepository(db *sqlx.DB, messages lang.Messages) SSOStateRepository {
+	return &ssoStateRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Create сохраняет состояние входа в БД и удаляет истекшие: брошенные входы не копятся
+func (r *ssoStateRepository) Create(ctx context.Context, state *models.SSOState) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM sso_states WHERE expires_at < $1", state.Created); err != nil {
+		log.Printf(r.messages.Get(lang.LogSSOStateDBError), state.Provider, err)
+		return err
+	}
+
+	query := `
+		INSERT INTO sso_states (state_hash, provider, nonce, code_verifier, expires_at, created_at)
+		VALUES (:state_hash, :provider, :nonce, :code_verifier, :expires_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, state); err != nil {
+		log.Printf(r.messages.Get(lang.LogSSOStateDBError), state.Provider, err)
+		return err
+	}
+
+	return nil
+}
+
+// Consume атомарно удаляет состояние входа и возвращает его.
+// Если состояние не найдено или уже использовано, возвращает nil, nil.
+func (r *ssoStateRepository) Consume(ctx context.Context, stateHash string) (*models.SSOState, error) {
+	var state models.SSOState
+	query := "DELETE FROM sso_states WHERE state_hash = $1 RETURNING *"
+
+	if err := r.db.GetContext(ctx, &state, query, stateHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Состояние не найдено или уже использовано
+		}
+		log.Printf(r.messages.Get(lang.LogSSOStateDBError), "hash", err)
+		return nil, err
+	}
+
+	return &state, nil
+}
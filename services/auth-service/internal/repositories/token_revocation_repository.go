@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// TokenRevocationRepository интерфейс для работы со списком отозванных токенов
+type TokenRevocationRepository interface {
+	RevokeToken(ctx context.Context, token *models.RevokedToken) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, revokedBefore time.Time) error
+	ListRevokedTokens(ctx context.Context, now time.Time) ([]models.RevokedToken, error)
+	ListUserRevocations(ctx context.Context, since time.Time) ([]models.UserTokenRevocation, error)
+}
+
+// tokenRevocationRepository реализация TokenRevocationRepository
+type tokenRevocationRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewTokenRevocationRepository создает новый экземпляр TokenRevocationRepository
+func NewTokenRevocationRepository(db *sqlx.DB, messages lang.Messages) TokenRevocationRepository {
+	return &tokenRevocationRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// RevokeToken добавляет токен в список отозванных
+func (r *tokenRevocationRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES (:jti, :user_id, :expires_at, :revoked_at)
+		ON CONFLICT (jti) DO NOTHING`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRevocationDBError), token.JTI.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllForUser отзывает все токены пользователя, выпущенные до revokedBefore
+func (r *tokenRevocationRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, revokedBefore time.Time) error {
+	query := `
+		INSERT INTO user_token_revocations (user_id, revoked_before)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)`
+
+	_, err := r.db.ExecContext(ctx, query, userID, revokedBefore)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogRevocationDBError), userID.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// ListRevokedTokens возвращает отозванные токены, срок действия которых еще не истек
+func (r *tokenRevocationRepository) ListRevokedTokens(ctx context.Context, now time.Time) ([]models.RevokedToken, error) {
+	var tokens []models.RevokedToken
+	query := "SELECT * FROM revoked_tokens WHERE expires_at > $1"
+
+	if err := r.db.SelectContext(ctx, &tokens, query, now); err != nil {
+		log.Printf(r.messages.Get(lang.LogRevocationDBError), "list", err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// ListUserRevocations возвращает отзывы по пользователям, сделанные после since
+func (r *tokenRevocationRepository) ListUserRevocations(ctx context.Context, since time.Time) ([]models.UserTokenRevocation, error) {
+	var revocations []models.UserTokenRevocation
+	query := "SELECT * FROM user_token_revocations WHERE revoked_before > $1"
+
+	if err := r.db.SelectContext(ctx, &revocations, query, since); err != nil {
+		log.Printf(r.messages.Get(lang.LogRevocationDBError), "list", err)
+		return nil, err
+	}
+
+	return revocations, nil
+}
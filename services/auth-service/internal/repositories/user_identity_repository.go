@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// UserIdentityRepository интерфейс для работы с учетными записями во внешних провайдерах входа
+type UserIdentityRepository interface {
+	Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	Save(ctx context.Context, identity *models.UserIdentity) error
+	GetByUser(ctx context.Context, provider string, userID uuid.UUID) (*models.UserIdentity, error)
+	DeleteByUser(ctx context.Context, provider string, userID uuid.UUID) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.UserIdentity, error)
+}
+
+// userIdentityRepository реализация UserIdentityRepository
+type userIdentityRepository struct {
+	db       *sqlx.DB
+	messages lang.Messages
+}
+
+// NewUserIdentityRepository создает новый экземпляр UserIdentityRepository
+func NewUserIdentityRepository(db *sqlx.DB, messages lang.Messages) UserIdentityRepository {
+	return &userIdentityRepository{
+		db:       db,
+		messages: messages,
+	}
+}
+
+// Get находит учетную запись по провайдеру и sub
+func (r *userIdentityRepository) Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	query := "SELECT * FROM user_identities WHERE provider = $1 AND subject = $2"
+
+	if err := r.db.GetContext(ctx, &identity, query, provider, subject); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Учетная запись еще не привязана
+		}
+		log.Printf(r.messages.Get(lang.LogUserIdentityDBError), provider, err)
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// Save привязывает учетную запись провайдера к пользователю или обновляет привязку,
+// email и время последнего входа
+func (r *userIdentityRepository) Save(ctx context.Context, identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (provider, subject, user_id, email, created_at, last_login_at)
+		VALUES (:provider, :subject, :user_id, :email, :created_at, :last_login_at)
+		ON CONFLICT (provider, subject) DO UPDATE
+		SET user_id = EXCLUDED.user_id, email = EXCLUDED.email, last_login_at = EXCLUDED.last_login_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, identity); err != nil {
+		log.Printf(r.messages.Get(lang.LogUserIdentityDBError), identity.Provider, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByUser находит учетную запись пользователя у провайдера
+func (r *userIdentityRepository) GetByUser(ctx context.Context, provider string, userID uuid.UUID) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	query := "SELECT * FROM user_identities WHERE provider = $1 AND user_id = $2 ORDER BY created_at DESC LIMIT 1"
+
+	if err := r.db.GetContext(ctx, &identity, query, provider, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Пользователь не привязан к провайдеру
+		}
+		log.Printf(r.messages.Get(lang.LogUserIdentityDBError), provider, err)
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// DeleteByUser отвязывает пользователя от провайдера
+func (r *userIdentityRepository) DeleteByUser(ctx context.Context, provider string, userID uuid.UUID) error {
+	query := "DELETE FROM user_identities WHERE provider = $1 AND user_id = $2"
+
+	if _, err := r.db.ExecContext(ctx, query, provider, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogUserIdentityDBError), provider, err)
+		return err
+	}
+
+	return nil
+}
+
+// ListByUser возвращает все учетные записи пользователя во внешних провайдерах
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.UserIdentity, error) {
+	identities := []models.UserIdentity{}
+	query := "SELECT * FROM user_identities WHERE user_id = $1 ORDER BY created_at"
+
+	if err := r.db.SelectContext(ctx, &identities, query, userID); err != nil {
+		log.Printf(r.messages.Get(lang.LogUserIdentityDBError), userID.String(), err)
+		return nil, err
+	}
+
+	return identities, nil
+}
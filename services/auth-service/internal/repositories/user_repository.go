@@ -3,7 +3,10 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/avangero/auth-service/internal/lang"
 	"github.com/avangero/auth-service/internal/models"
@@ -14,9 +17,19 @@ import (
 // UserRepository интерфейс для работы с пользователями
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
+	CreateWithIdentity(ctx context.Context, user *models.User, identity *models.UserIdentity) error
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	EmailExists(ctx context.Context, email string) (bool, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) error
+	List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, reason *string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]models.User, error)
+	Erase(ctx context.Context, user *models.User, tombstoneEmail string) error
 }
 
 // userRepository реализация UserRepository
@@ -33,26 +46,61 @@ func NewUserRepository(db *sqlx.DB, messages lang.Messages) UserRepository {
 	}
 }
 
+// insertUserQuery добавляет пользователя вместе со статусом, заданным при создании
+const insertUserQuery = `
+	INSERT INTO users (id, email, password_hash, role, created_at, email_verified_at, status, status_reason, status_changed_at)
+	VALUES (:id, :email, :password_hash, :role, :created_at, :email_verified_at, :status, :status_reason, :status_changed_at)`
+
 // Create создает нового пользователя в БД
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	query := `
-		INSERT INTO users (id, email, password_hash, role, created_at) 
-		VALUES (:id, :email, :password_hash, :role, :created_at)`
+	_, err := r.db.NamedExecContext(ctx, insertUserQuery, user)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogUserCreateFailed), user.Email, err)
+		return err
+	}
 
-	_, err := r.db.NamedExecContext(ctx, query, user)
+	log.Printf(r.messages.Get(lang.LogUserCreateSuccess), user.Email)
+	return nil
+}
+
+// CreateWithIdentity создает пользователя и его учетную запись во внешней системе в одной
+// транзакции: если привязка не сохранится, пользователь тоже не создается. nil identity - без привязки.
+func (r *userRepository) CreateWithIdentity(ctx context.Context, user *models.User, identity *models.UserIdentity) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Printf(r.messages.Get(lang.LogUserCreateFailed), user.Email, err)
 		return err
 	}
+	defer tx.Rollback()
+
+	if _, err := tx.NamedExecContext(ctx, insertUserQuery, user); err != nil {
+		log.Printf(r.messages.Get(lang.LogUserCreateFailed), user.Email, err)
+		return err
+	}
+
+	if identity != nil {
+		query := `
+			INSERT INTO user_identities (provider, subject, user_id, email, created_at, last_login_at)
+			VALUES (:provider, :subject, :user_id, :email, :created_at, :last_login_at)`
+		if _, err := tx.NamedExecContext(ctx, query, identity); err != nil {
+			log.Printf(r.messages.Get(lang.LogUserIdentityDBError), identity.Provider, err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf(r.messages.Get(lang.LogUserCreateFailed), user.Email, err)
+		return err
+	}
 
 	log.Printf(r.messages.Get(lang.LogUserCreateSuccess), user.Email)
 	return nil
 }
 
-// GetByEmail находит пользователя по email
+// GetByEmail находит пользователя по email. Удаленные учетные записи не возвращаются.
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	query := "SELECT * FROM users WHERE email = $1"
+	query := "SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL"
 
 	err := r.db.GetContext(ctx, &user, query, email)
 	if err != nil {
@@ -67,10 +115,10 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 	return &user, nil
 }
 
-// GetByID находит пользователя по ID
+// GetByID находит пользователя по ID. Удаленные учетные записи не возвращаются.
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	query := "SELECT * FROM users WHERE id = $1"
+	query := "SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL"
 
 	err := r.db.GetContext(ctx, &user, query, id)
 	if err != nil {
@@ -88,7 +136,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 // EmailExists проверяет существование пользователя с данным email
 func (r *userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	var exists bool
-	query := "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)"
+	query := "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND deleted_at IS NULL)"
 
 	err := r.db.GetContext(ctx, &exists, query, email)
 	if err != nil {
@@ -98,3 +146,202 @@ func (r *userRepository) EmailExists(ctx context.Context, email string) (bool, e
 
 	return exists, nil
 }
+
+// UpdatePassword обновляет хеш пароля пользователя
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := "UPDATE users SET password_hash = $1 WHERE id = $2"
+
+	_, err := r.db.ExecContext(ctx, query, passwordHash, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", id.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogPasswordUpdated), id.String())
+	return nil
+}
+
+// MarkEmailVerified отмечает email пользователя подтвержденным.
+// Повторный вызов не сдвигает дату первого подтверждения.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	query := "UPDATE users SET email_verified_at = NOW() WHERE id = $1 AND email_verified_at IS NULL"
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", id.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogEmailMarkedVerified), id.String())
+	return nil
+}
+
+// UpdateEmail меняет email пользователя, например после смены фамилии в кадровой системе.
+// Новый адрес считается неподтвержденным.
+func (r *userRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	query := "UPDATE users SET email = $1, email_verified_at = NULL WHERE id = $2"
+
+	_, err := r.db.ExecContext(ctx, query, email, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", id.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogEmailUpdated), id.String())
+	return nil
+}
+
+// UpdateRole меняет роль пользователя
+func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	query := "UPDATE users SET role = $1 WHERE id = $2"
+
+	_, err := r.db.ExecContext(ctx, query, role, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", id.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogRoleUpdated), id.String(), role)
+	return nil
+}
+
+// List возвращает страницу пользователей по фильтру и общее число подходящих записей
+func (r *userRepository) List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Role != "" {
+		addCondition("role = $%d", filter.Role)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.Email != "" {
+		addCondition(`email ILIKE '%%' || $%d || '%%' ESCAPE '\'`, escapeLike(filter.Email))
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at < $%d", *filter.CreatedTo)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM users"+where, args...); err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "users", "list", err)
+		return nil, 0, err
+	}
+
+	users := []models.User{}
+	query := fmt.Sprintf("SELECT * FROM users%s ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d", where, len(args)+1, len(args)+2)
+	if err := r.db.SelectContext(ctx, &users, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "users", "list", err)
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// UpdateStatus меняет статус учетной записи и запоминает причину и время смены
+func (r *userRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, reason *string) error {
+	query := "UPDATE users SET status = $1, status_reason = $2, status_changed_at = NOW() WHERE id = $3"
+
+	_, err := r.db.ExecContext(ctx, query, status, reason, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", id.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogUserStatusUpdated), id.String(), status)
+	return nil
+}
+
+// Delete помечает пользователя удаленным. Строка остается в БД, чтобы история
+// обучения в других сервисах не потеряла ссылку; персональные данные стирает Erase.
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := "UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", id.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogUserRemoved), id.String())
+	return nil
+}
+
+// ListDeleted возвращает удаленных до deletedBefore пользователей, чьи данные еще не стерты
+func (r *userRepository) ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]models.User, error) {
+	users := []models.User{}
+	query := `
+		SELECT * FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND erased_at IS NULL
+		ORDER BY deleted_at
+		LIMIT $2`
+
+	if err := r.db.SelectContext(ctx, &users, query, deletedBefore, limit); err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "users", "deleted", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Erase заменяет email и пароль удаленного пользователя обезличенными значениями
+// и удаляет связанные с ним токены, второй фактор и приглашения.
+// ID пользователя сохраняется, поэтому обезличенная история обучения остается целой.
+func (r *userRepository) Erase(ctx context.Context, user *models.User, tombstoneEmail string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", user.ID.String(), err)
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"UPDATE users SET email = $1, password_hash = '', status_reason = NULL, erased_at = NOW() WHERE id = $2", []interface{}{tombstoneEmail, user.ID}},
+		{"DELETE FROM refresh_tokens WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM sessions WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM api_keys WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM authorization_codes WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM user_identities WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM group_members WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM password_reset_tokens WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM email_verification_tokens WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM mfa_challenges WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM mfa_recovery_codes WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM user_mfa WHERE user_id = $1", []interface{}{user.ID}},
+		{"DELETE FROM invitations WHERE email = $1", []interface{}{user.Email}},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", user.ID.String(), err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf(r.messages.Get(lang.LogDatabaseError), "ID", user.ID.String(), err)
+		return err
+	}
+
+	log.Printf(r.messages.Get(lang.LogUserErased), user.ID.String())
+	return nil
+}
+
+// escapeLike экранирует служебные символы шаблона LIKE
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
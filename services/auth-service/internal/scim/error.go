@@ -0,0 +1,55 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Значения scimType для ответов 400 и 409 (RFC 7644, раздел 3.12)
+const (
+	ErrorInvalidFilter = "invalidFilter"
+	ErrorInvalidSyntax = "invalidSyntax"
+	ErrorInvalidPath   = "invalidPath"
+	ErrorInvalidValue  = "invalidValue"
+	ErrorUniqueness    = "uniqueness"
+)
+
+// Error ошибка запроса с HTTP статусом, которую клиент SCIM умеет разобрать
+type Error struct {
+	Status int
+	Type   string // scimType, пустая строка для 403 и 404
+	Detail string
+}
+
+// NewError создает ошибку запроса
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{Status: status, Type: scimType, Detail: detail}
+}
+
+// NotFound создает ошибку 404 для отсутствующего ресурса
+func NotFound(detail string) *Error {
+	return NewError(http.StatusNotFound, "", detail)
+}
+
+// Error возвращает описание ошибки
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// ErrorResponse тело ответа с ошибкой
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// NewErrorResponse создает тело ответа с ошибкой. Статус по RFC передается строкой.
+func NewErrorResponse(status int, scimType, detail string) *ErrorResponse {
+	return &ErrorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
@@ -0,0 +1,93 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidFilter фильтр или путь PATCH не поддерживается
+var ErrInvalidFilter = errors.New("scim: unsupported filter")
+
+// Filter условие отбора. Поддерживается только сравнение eq со строкой:
+// этого достаточно кадровым системам, чтобы найти уже созданную учетную запись.
+type Filter struct {
+	Attribute string // имя атрибута без URN схемы, например userName или emails.value
+	Value     string
+}
+
+// ParseFilter разбирает выражение вида userName eq "ivanov@example.com"
+func ParseFilter(expression string) (*Filter, error) {
+	attribute, rest, ok := strings.Cut(strings.TrimSpace(expression), " ")
+	if !ok {
+		return nil, ErrInvalidFilter
+	}
+	operator, operand, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(operator, "eq") {
+		return nil, ErrInvalidFilter
+	}
+
+	// Значение - строка JSON: кавычки внутри экранированы обратной косой чертой
+	var value string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(operand)), &value); err != nil {
+		return nil, ErrInvalidFilter
+	}
+
+	return &Filter{Attribute: stripSchema(attribute), Value: value}, nil
+}
+
+// Is проверяет имя атрибута фильтра без учета регистра
+func (f *Filter) Is(attribute string) bool {
+	return strings.EqualFold(f.Attribute, attribute)
+}
+
+// Path путь атрибута в операции PATCH, например members[value eq "id"] или name.givenName
+type Path struct {
+	Attribute    string
+	Filter       *Filter // отбор элементов многозначного атрибута, nil - все элементы
+	SubAttribute string
+}
+
+// ParsePath разбирает путь операции PATCH
+func ParsePath(path string) (*Path, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, ErrInvalidFilter
+	}
+
+	result := &Path{}
+	if open := strings.IndexByte(path, '['); open >= 0 {
+		end := strings.LastIndexByte(path, ']')
+		if end < open {
+			return nil, ErrInvalidFilter
+		}
+		filter, err := ParseFilter(path[open+1 : end])
+		if err != nil {
+			return nil, err
+		}
+		result.Filter = filter
+		result.SubAttribute = strings.TrimPrefix(path[end+1:], ".")
+		path = path[:open]
+	}
+
+	path = stripSchema(path)
+	if attribute, sub, ok := strings.Cut(path, "."); ok && result.Filter == nil {
+		path, result.SubAttribute = attribute, sub
+	}
+	result.Attribute = path
+	return result, nil
+}
+
+// Is проверяет имя атрибута пути без учета регистра
+func (p *Path) Is(attribute string) bool {
+	return strings.EqualFold(p.Attribute, attribute)
+}
+
+// stripSchema убирает URN схемы перед именем атрибута:
+// urn:ietf:params:scim:schemas:core:2.0:User:userName -> userName
+func stripSchema(attribute string) string {
+	if index := strings.LastIndexByte(attribute, ':'); index >= 0 {
+		return attribute[index+1:]
+	}
+	return attribute
+}
@@ -0,0 +1,240 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType тип содержимого запросов и ответов SCIM (RFC 7644, раздел 3.1)
+const ContentType = "application/scim+json"
+
+// Схемы ресурсов и сообщений SCIM
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Типы ресурсов для meta.resourceType
+const (
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+// Операции PATCH. Сравниваются без учета регистра: Azure AD присылает Add и Replace.
+const (
+	OpAdd     = "add"
+	OpReplace = "replace"
+	OpRemove  = "remove"
+)
+
+// Размер страницы списка: по умолчанию и наибольший допустимый
+const (
+	DefaultCount = 100
+	MaxResults   = 200
+)
+
+// User ресурс пользователя. userName совпадает с email пользователя портала.
+// Остальные атрибуты схемы (имя, телефоны, адреса) портал не хранит и игнорирует.
+type User struct {
+	Schemas    []string `json:"schemas"`
+	ID         string   `json:"id,omitempty"`
+	ExternalID string   `json:"externalId,omitempty"`
+	UserName   string   `json:"userName" validate:"required,email"`
+	Emails     []Email  `json:"emails,omitempty"`
+	Active     *bool    `json:"active,omitempty"` // nil - атрибут не передан
+	Meta       *Meta    `json:"meta,omitempty"`
+}
+
+// Email адрес пользователя
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Group ресурс группы
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	DisplayName string   `json:"displayName" validate:"required,max=255"`
+	Members     []Member `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Member участник группы. value - id пользователя.
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// Meta служебные атрибуты ресурса
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+// ListResponse страница результатов поиска
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// NewListResponse создает страницу результатов
+func NewListResponse(query ListQuery, total int, resources []interface{}) *ListResponse {
+	if resources == nil {
+		resources = []interface{}{}
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   query.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// ListQuery параметры поиска ресурсов
+type ListQuery struct {
+	Filter         string // пустая строка - без отбора
+	StartIndex     int    // с единицы
+	Count          int
+	ExcludeMembers bool // excludedAttributes=members, участники групп не загружаются
+}
+
+// NewListQuery разбирает параметры поиска из строки запроса. Некорректные startIndex
+// и count заменяются ближайшими допустимыми значениями (RFC 7644, раздел 3.4.2.4).
+func NewListQuery(filter, startIndex, count, excludedAttributes string) ListQuery {
+	query := ListQuery{
+		Filter:     strings.TrimSpace(filter),
+		StartIndex: 1,
+		Count:      DefaultCount,
+	}
+
+	if value, err := strconv.Atoi(startIndex); err == nil && value > 1 {
+		query.StartIndex = value
+	}
+	if value, err := strconv.Atoi(count); err == nil {
+		query.Count = min(max(value, 0), MaxResults)
+	}
+
+	for _, attribute := range strings.Split(excludedAttributes, ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			query.ExcludeMembers = true
+		}
+	}
+
+	return query
+}
+
+// PatchRequest запрос на частичное изменение ресурса
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations" validate:"required,min=1,dive"`
+}
+
+// PatchOperation одна операция PATCH
+type PatchOperation struct {
+	Op    string          `json:"op" validate:"required"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ServiceProviderConfig возможности сервиса, по которым клиент выбирает способ синхронизации
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkSupport            `json:"bulk"`
+	Filter                FilterSupport          `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+}
+
+// Supported признак поддержки возможности
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+// BulkSupport поддержка пакетных операций
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// FilterSupport поддержка фильтров
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// AuthenticationScheme способ аутентификации клиента
+type AuthenticationScheme struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Primary bool   `json:"primary"`
+}
+
+// NewServiceProviderConfig описывает поддерживаемую часть протокола: PATCH и фильтр eq есть,
+// пакетных операций, сортировки, ETag и смены пароля нет
+func NewServiceProviderConfig() *ServiceProviderConfig {
+	return &ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   Supported{Supported: true},
+		Filter:  FilterSupport{Supported: true, MaxResults: MaxResults},
+		AuthenticationSchemes: []AuthenticationScheme{
+			{Type: "oauthbearertoken", Name: "OAuth Bearer Token", Primary: true},
+		},
+	}
+}
+
+// ParseBool разбирает логическое значение PATCH. Azure AD передает его строкой "True" или "False".
+func ParseBool(raw json.RawMessage) (bool, bool) {
+	var value bool
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value, true
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return false, false
+	}
+	value, err := strconv.ParseBool(strings.ToLower(text))
+	return value, err == nil
+}
+
+// ParseString разбирает строковое значение PATCH
+func ParseString(raw json.RawMessage) (string, bool) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// ParseMembers разбирает участников группы: список или один объект
+func ParseMembers(raw json.RawMessage) ([]Member, bool) {
+	var members []Member
+	if err := json.Unmarshal(raw, &members); err == nil {
+		return members, true
+	}
+
+	var member Member
+	if err := json.Unmarshal(raw, &member); err != nil {
+		return nil, false
+	}
+	return []Member{member}, true
+}
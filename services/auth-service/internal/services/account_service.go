@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// Количество учетных записей, которые задача стирания обрабатывает за один запуск
+const erasureBatchSize = 100
+
+// AccountService интерфейс для работы с персональными данными пользователя
+type AccountService interface {
+	Export(ctx context.Context, userID uuid.UUID) (*responses.AccountExportResponse, error)
+	EraseDeleted(ctx context.Context) (int, error)
+}
+
+// accountService реализация AccountService
+type accountService struct {
+	userRepo       repositories.UserRepository
+	roleChangeRepo repositories.RoleChangeRepository
+	invitationRepo repositories.InvitationRepository
+	mfaRepo        repositories.MFARepository
+	refreshRepo    repositories.RefreshTokenRepository
+	sessionRepo    repositories.SessionRepository
+	apiKeyRepo     repositories.APIKeyRepository
+	identityRepo   repositories.UserIdentityRepository
+	groupRepo      repositories.GroupRepository
+	loginAttempts  repositories.LoginAttemptRepository
+	erasureConfig  config.ErasureConfig
+	messages       lang.Messages
+}
+
+// NewAccountService создает новый экземпляр AccountService
+func NewAccountService(
+	userRepo repositories.UserRepository,
+	roleChangeRepo repositories.RoleChangeRepository,
+	invitationRepo repositories.InvitationRepository,
+	mfaRepo repositories.MFARepository,
+	refreshRepo repositories.RefreshTokenRepository,
+	sessionRepo repositories.SessionRepository,
+	apiKeyRepo repositories.APIKeyRepository,
+	identityRepo repositories.UserIdentityRepository,
+	groupRepo repositories.GroupRepository,
+	loginAttempts repositories.LoginAttemptRepository,
+	erasureConfig config.ErasureConfig,
+	messages lang.Messages,
+) AccountService {
+	return &accountService{
+		userRepo:       userRepo,
+		roleChangeRepo: roleChangeRepo,
+		invitationRepo: invitationRepo,
+		mfaRepo:        mfaRepo,
+		refreshRepo:    refreshRepo,
+		sessionRepo:    sessionRepo,
+		apiKeyRepo:     apiKeyRepo,
+		identityRepo:   identityRepo,
+		groupRepo:      groupRepo,
+		loginAttempts:  loginAttempts,
+		erasureConfig:  erasureConfig,
+		messages:       messages,
+	}
+}
+
+// Export собирает все данные, которые сервис хранит о пользователе.
+// Секреты (хеши паролей и токенов, TOTP секрет) в выгрузку не попадают.
+func (s *accountService) Export(ctx context.Context, userID uuid.UUID) (*responses.AccountExportResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), userID.String(), err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(s.messages.Get(lang.UserNotFound))
+	}
+
+	roleChanges, err := s.roleChangeRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &responses.AccountExportResponse{
+		ExportedAt:  time.Now(),
+		User:        *user,
+		RoleChanges: roleChanges,
+		Invitations: invitations,
+		Sessions:    []responses.SessionExport{},
+		Identities:  []responses.IdentityExport{},
+		Groups:      []responses.GroupExport{},
+	}
+
+	mfa, err := s.mfaRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa != nil {
+		export.MFA = &responses.MFAExport{
+			Enabled:     mfa.IsConfirmed(),
+			ConfirmedAt: mfa.ConfirmedAt,
+			CreatedAt:   mfa.Created,
+		}
+	}
+
+	tokens, err := s.refreshRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		export.Sessions = append(export.Sessions, responses.SessionExport{
+			ID:        token.ID,
+			FamilyID:  token.FamilyID,
+			CreatedAt: token.Created,
+			ExpiresAt: token.ExpiresAt,
+			UsedAt:    token.UsedAt,
+			RevokedAt: token.RevokedAt,
+		})
+	}
+
+	devices, err := s.sessionRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	export.Devices = devices
+
+	apiKeys, err := s.apiKeyRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	export.APIKeys = apiKeys
+
+	identities, err := s.identityRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, identity := range identities {
+		export.Identities = append(export.Identities, responses.IdentityExport{
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			LinkedAt:    identity.Created,
+			LastLoginAt: identity.LastLoginAt,
+		})
+	}
+
+	groups, err := s.groupRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		export.Groups = append(export.Groups, responses.GroupExport{
+			ID:          group.ID,
+			DisplayName: group.DisplayName,
+		})
+	}
+
+	attempt, err := s.loginAttempts.Get(ctx, emailKey(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	if attempt != nil {
+		export.LoginAttempt = &responses.LoginAttemptExport{
+			Failures:      attempt.Failures,
+			LastFailureAt: attempt.LastFailureAt,
+			LockedUntil:   attempt.LockedUntil,
+		}
+	}
+
+	log.Printf(s.messages.Get(lang.LogAccountExported), user.Email)
+	return export, nil
+}
+
+// EraseDeleted стирает персональные данные пользователей, удаленных раньше срока хранения.
+// Email заменяется обезличенным адресом, ID остается для истории обучения.
+// Возвращает количество обработанных учетных записей; ошибка одной записи не останавливает остальные.
+func (s *accountService) EraseDeleted(ctx context.Context) (int, error) {
+	users, err := s.userRepo.ListDeleted(ctx, time.Now().Add(-s.erasureConfig.GracePeriod), erasureBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for i := range users {
+		user := &users[i]
+		if err := s.userRepo.Erase(ctx, user, tombstoneEmail(user.ID)); err != nil {
+			log.Printf(s.messages.Get(lang.LogErasureFailed), user.ID.String(), err)
+			continue
+		}
+
+		// Счетчик попыток входа хранится по email и может находиться вне БД
+		if err := s.loginAttempts.Reset(ctx, emailKey(user.Email)); err != nil {
+			log.Printf(s.messages.Get(lang.LogErasureFailed), user.ID.String(), err)
+		}
+
+		log.Printf(s.messages.Get(lang.LogErasureComplete), user.ID.String())
+		erased++
+	}
+
+	return erased, nil
+}
+
+// RunErasureJob периодически запускает стирание персональных данных до отмены ctx
+func RunErasureJob(ctx context.Context, account AccountService, interval time.Duration, messages lang.Messages) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			erased, err := account.EraseDeleted(ctx)
+			if err != nil {
+				log.Printf(messages.Get(lang.LogErasureFailed), "batch", err)
+				continue
+			}
+			if erased > 0 {
+				log.Printf(messages.Get(lang.LogErasureBatch), erased)
+			}
+		}
+	}
+}
+
+// tombstoneEmail обезличенный email стертой учетной записи
+func tombstoneEmail(userID uuid.UUID) string {
+	return fmt.Sprintf("erased-%s@erased.invalid", userID.String())
+}
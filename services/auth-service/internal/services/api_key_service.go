@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+const (
+	// APIKeyPrefix начало каждого персонального API ключа, отличает его от JWT
+	APIKeyPrefix = "ak_"
+	// apiKeyDisplayLength длина начала ключа, которое сохраняется для отображения в списке
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+	// maxAPIKeysPerUser максимальное число действующих ключей у пользователя
+	maxAPIKeysPerUser = 20
+	// apiKeyTouchInterval как часто обновляется время последнего использования ключа
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyService интерфейс для персональных API ключей пользователей
+type APIKeyService interface {
+	Create(ctx context.Context, user *models.User, req *requests.CreateAPIKeyRequest) (*responses.APIKeyCreatedResponse, error)
+	List(ctx context.Context, userID uuid.UUID) (*responses.APIKeyListResponse, error)
+	Revoke(ctx context.Context, user *models.User, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*models.User, *models.APIKey, error)
+}
+
+// apiKeyService реализация APIKeyService
+type apiKeyService struct {
+	apiKeyRepo repositories.APIKeyRepository
+	userRepo   repositories.UserRepository
+	messages   lang.Messages
+}
+
+// NewAPIKeyService создает новый экземпляр APIKeyService
+func NewAPIKeyService(apiKeyRepo repositories.APIKeyRepository, userRepo repositories.UserRepository, messages lang.Messages) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		messages:   messages,
+	}
+}
+
+// Create выпускает API ключ пользователя. Ключу можно выдать только права роли владельца.
+func (s *apiKeyService) Create(ctx context.Context, user *models.User, req *requests.CreateAPIKeyRequest) (*responses.APIKeyCreatedResponse, error) {
+	now := time.Now()
+
+	active, err := s.apiKeyRepo.ListActiveByUser(ctx, user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) >= maxAPIKeysPerUser {
+		return nil, errors.New(s.messages.Get(lang.APIKeyLimitReached))
+	}
+
+	var scopes []models.Permission
+	for _, scope := range req.Scopes {
+		if !models.HasPermission(user.Role, models.Permission(scope)) {
+			return nil, errors.New(s.messages.Get(lang.APIKeyScopeDenied))
+		}
+		scopes = appendScope(scopes, models.Permission(scope))
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil || !parsed.After(now) {
+			return nil, errors.New(s.messages.Get(lang.APIKeyExpiryInvalid))
+		}
+		expiresAt = &parsed
+	}
+
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	key := APIKeyPrefix + secret
+
+	apiKey := &models.APIKey{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Name:      req.Name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashOpaqueToken(key),
+		Scope:     models.FormatScope(scopes),
+		ExpiresAt: expiresAt,
+		Created:   now,
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogAPIKeyCreated), apiKey.ID.String(), apiKey.Name, user.Email)
+	return &responses.APIKeyCreatedResponse{APIKey: *apiKey, Key: key}, nil
+}
+
+// List возвращает действующие API ключи пользователя без их значений
+func (s *apiKeyService) List(ctx context.Context, userID uuid.UUID) (*responses.APIKeyListResponse, error) {
+	keys, err := s.apiKeyRepo.ListActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.APIKeyListResponse{APIKeys: keys}, nil
+}
+
+// Revoke отзывает API ключ пользователя. Чужой или уже недействующий ключ считается ненайденным.
+func (s *apiKeyService) Revoke(ctx context.Context, user *models.User, keyID uuid.UUID) error {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	if apiKey == nil || apiKey.UserID != user.ID || !apiKey.IsActive(time.Now()) {
+		return errors.New(s.messages.Get(lang.APIKeyNotFound))
+	}
+
+	if err := s.apiKeyRepo.Revoke(ctx, apiKey.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogAPIKeyRevoked), apiKey.ID.String(), user.Email)
+	return nil
+}
+
+// Authenticate находит владельца предъявленного API ключа. Ключ приостановленного
+// или деактивированного пользователя не принимается, как и его JWT.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*models.User, *models.APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) || len(key) <= apiKeyDisplayLength {
+		return nil, nil, errors.New(s.messages.Get(lang.APIKeyInvalid))
+	}
+
+	now := time.Now()
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, hashOpaqueToken(key))
+	if err != nil {
+		return nil, nil, err
+	}
+	if apiKey == nil || !apiKey.IsActive(now) {
+		log.Printf(s.messages.Get(lang.LogAPIKeyRejected), key[:apiKeyDisplayLength])
+		return nil, nil, errors.New(s.messages.Get(lang.APIKeyInvalid))
+	}
+
+	user, err := s.userRepo.GetByID(ctx, apiKey.UserID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), apiKey.UserID.String(), err)
+		return nil, nil, err
+	}
+	if user == nil {
+		log.Printf(s.messages.Get(lang.LogUserNotFoundValidation), apiKey.UserID.String())
+		return nil, nil, errors.New(s.messages.Get(lang.APIKeyInvalid))
+	}
+	if !user.IsActive() {
+		log.Printf(s.messages.Get(lang.LogLoginInactive), user.Email, user.Status)
+		return nil, nil, errors.New(s.messages.Get(lang.APIKeyInvalid))
+	}
+
+	// Время использования обновляется не на каждый запрос, а не чаще apiKeyTouchInterval
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.Touch(ctx, apiKey.ID, now); err != nil {
+			log.Printf(s.messages.Get(lang.LogAPIKeyTouchFailed), apiKey.ID.String(), err)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return user, apiKey, nil
+}
@@ -6,155 +6,322 @@ import (
 	"log"
 	"time"
 
+	"github.com/avangero/auth-service/internal/config"
 	"github.com/avangero/auth-service/internal/lang"
 	"github.com/avangero/auth-service/internal/models"
 	"github.com/avangero/auth-service/internal/models/requests"
 	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/avangero/auth-service/internal/repositories"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthService интерфейс для сервиса аутентификации
 type AuthService interface {
-	Register(ctx context.Context, req *requests.RegisterRequest) (*responses.TokenResponse, error)
-	Login(ctx context.Context, req *requests.LoginRequest) (*responses.TokenResponse, error)
+	Register(ctx context.Context, req *requests.RegisterRequest, client models.ClientInfo) (*responses.TokenResponse, error)
+	RegisterInvited(ctx context.Context, invitation *models.Invitation, password string, client models.ClientInfo) (*responses.TokenResponse, error)
+	Login(ctx context.Context, req *requests.LoginRequest, client models.ClientInfo) (*responses.TokenResponse, error)
+	Refresh(ctx context.Context, req *requests.RefreshRequest, client models.ClientInfo) (*responses.TokenResponse, error)
+	Logout(ctx context.Context, tokenString string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
 	ValidateToken(ctx context.Context, tokenString string) (*models.User, error)
 	GenerateToken(user *models.User) (string, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
-}
-
-// JWTClaims представляет данные в JWT токене
-type JWTClaims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   string    `json:"role"`
-	jwt.RegisteredClaims
+	LoginMFA(ctx context.Context, req *requests.MFALoginRequest, client models.ClientInfo) (*responses.TokenResponse, error)
+	LoginExternal(ctx context.Context, user *models.User, client models.ClientInfo) (*responses.TokenResponse, error)
 }
 
 // authService реализация AuthService
 type authService struct {
-	userRepo   repositories.UserRepository
-	jwtSecret  string
-	bcryptCost int
-	messages   lang.Messages
+	userRepo      repositories.UserRepository
+	tokenService  TokenService
+	verification  EmailVerificationService
+	mfa           MFAService
+	loginGuard    LoginGuard
+	authConfig    config.AuthConfig
+	hasher        passwords.Hasher
+	authenticator Authenticator
+	messages      lang.Messages
 }
 
 // NewAuthService создает новый экземпляр AuthService
-func NewAuthService(userRepo repositories.UserRepository, jwtSecret string, bcryptCost int, messages lang.Messages) AuthService {
+func NewAuthService(
+	userRepo repositories.UserRepository,
+	tokenService TokenService,
+	verification EmailVerificationService,
+	mfa MFAService,
+	loginGuard LoginGuard,
+	authConfig config.AuthConfig,
+	hasher passwords.Hasher,
+	authenticator Authenticator,
+	messages lang.Messages,
+) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		jwtSecret:  jwtSecret,
-		bcryptCost: bcryptCost,
-		messages:   messages,
+		userRepo:      userRepo,
+		tokenService:  tokenService,
+		verification:  verification,
+		mfa:           mfa,
+		loginGuard:    loginGuard,
+		authConfig:    authConfig,
+		hasher:        hasher,
+		authenticator: authenticator,
+		messages:      messages,
 	}
 }
 
-// Register регистрирует нового пользователя
-func (s *authService) Register(ctx context.Context, req *requests.RegisterRequest) (*responses.TokenResponse, error) {
-	log.Printf(s.messages.Get(lang.LogAttemptingRegistration), req.Email)
+// Register регистрирует нового пользователя с ролью сотрудника
+func (s *authService) Register(ctx context.Context, req *requests.RegisterRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
+	if err := checkRegistrationAllowed(s.authConfig, req.Email, s.messages); err != nil {
+		return nil, err
+	}
 
-	// Проверяем существование пользователя
-	exists, err := s.userRepo.EmailExists(ctx, req.Email)
+	user, err := s.createUser(ctx, req.Email, req.Password, models.RoleEmployee, nil)
 	if err != nil {
-		log.Printf(s.messages.Get(lang.LogCheckEmailExists), req.Email, err)
 		return nil, err
 	}
-	if exists {
-		log.Printf(s.messages.Get(lang.LogEmailAlreadyExists), req.Email)
-		return nil, errors.New(s.messages.Get(lang.UserAlreadyExists))
+
+	// Письмо с подтверждением можно запросить повторно, поэтому ошибка отправки
+	// не отменяет уже состоявшуюся регистрацию
+	_ = s.verification.SendVerification(ctx, user)
+
+	// Пока email не подтвержден, войти нельзя - токены не выдаем
+	if s.authConfig.RequireEmailVerification {
+		log.Printf(s.messages.Get(lang.LogRegistrationComplete), req.Email)
+		return &responses.TokenResponse{User: *user, EmailVerificationRequired: true}, nil
 	}
 
-	// Хешируем пароль
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), s.bcryptCost)
+	// Генерируем пару токенов в новой сессии
+	response, err := s.startSession(ctx, user, client)
 	if err != nil {
-		log.Printf(s.messages.Get(lang.LogPasswordHashError), req.Email, err)
 		return nil, err
 	}
 
-	// Создаем пользователя
-	user := &models.User{
-		ID:       uuid.New(),
-		Email:    req.Email,
-		Password: string(hashedPassword),
-		Role:     req.Role,
-		Created:  time.Now(),
+	log.Printf(s.messages.Get(lang.LogRegistrationComplete), req.Email)
+	return response, nil
+}
+
+// RegisterInvited создает аккаунт по приглашению с ролью из приглашения.
+// Ссылка пришла на приглашенный адрес, поэтому email сразу считается подтвержденным.
+func (s *authService) RegisterInvited(ctx context.Context, invitation *models.Invitation, password string, client models.ClientInfo) (*responses.TokenResponse, error) {
+	verifiedAt := time.Now()
+	user, err := s.createUser(ctx, invitation.Email, password, invitation.Role, &verifiedAt)
+	if err != nil {
+		return nil, err
 	}
 
-	// Сохраняем в БД
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		log.Printf(s.messages.Get(lang.LogUserCreateError), req.Email, err)
+	// Приглашенному менеджеру может понадобиться сразу подключить второй фактор
+	challenge, err := s.mfa.Challenge(ctx, user)
+	if err != nil {
 		return nil, err
 	}
+	if challenge != nil {
+		return challenge, nil
+	}
 
-	// Генерируем токен
-	token, err := s.generateJWT(user)
+	// Генерируем пару токенов в новой сессии
+	response, err := s.startSession(ctx, user, client)
 	if err != nil {
-		log.Printf(s.messages.Get(lang.LogJWTGenerateError), req.Email, err)
 		return nil, err
 	}
 
-	log.Printf(s.messages.Get(lang.LogRegistrationComplete), req.Email)
-	return &responses.TokenResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	log.Printf(s.messages.Get(lang.LogRegistrationComplete), user.Email)
+	return response, nil
 }
 
 // Login аутентифицирует пользователя
-func (s *authService) Login(ctx context.Context, req *requests.LoginRequest) (*responses.TokenResponse, error) {
+func (s *authService) Login(ctx context.Context, req *requests.LoginRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
 	log.Printf(s.messages.Get(lang.LogAttemptingLogin), req.Email)
 
-	// Ищем пользователя
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	// Заблокированный аккаунт или IP не доходит до сравнения хеша пароля
+	if err := s.loginGuard.Check(ctx, req.Email, client.IP); err != nil {
+		return nil, err
+	}
+
+	// Проверяем пароль в БД или в корпоративном каталоге
+	user, err := s.authenticator.Authenticate(ctx, req.Email, req.Password)
 	if err != nil {
-		log.Printf(s.messages.Get(lang.LogDatabaseErrorLogin), req.Email, err)
 		// Всегда возвращаем общую ошибку для безопасности
 		return nil, errors.New(s.messages.Get(lang.InvalidCredentials))
 	}
 
-	// Проверяем, найден ли пользователь
+	// Попытки для несуществующих email тоже считаются, чтобы блокировка не раскрывала наличие аккаунта
 	if user == nil {
-		log.Printf(s.messages.Get(lang.LogUserNotFoundLogin), req.Email)
-		return nil, errors.New(s.messages.Get(lang.InvalidCredentials))
+		return nil, s.failLogin(ctx, req.Email, client.IP)
 	}
 
-	// Проверяем пароль
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		log.Printf(s.messages.Get(lang.LogInvalidPassword), req.Email)
-		return nil, errors.New(s.messages.Get(lang.InvalidCredentials))
+	if err := s.loginGuard.RecordSuccess(ctx, req.Email); err != nil {
+		return nil, err
+	}
+
+	// Статус проверяется после пароля, чтобы не раскрывать его посторонним
+	if err := s.checkActive(user); err != nil {
+		return nil, err
+	}
+
+	// Проверяем подтверждение email после пароля, чтобы не раскрывать наличие аккаунта
+	if err := s.checkEmailVerified(user); err != nil {
+		return nil, err
 	}
 
-	// Генерируем токен
-	token, err := s.generateJWT(user)
+	// Если нужен второй фактор, токены выдаются только после POST /api/v1/login/mfa
+	challenge, err := s.mfa.Challenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	// Генерируем пару токенов в новой сессии
+	response, err := s.startSession(ctx, user, client)
 	if err != nil {
-		log.Printf(s.messages.Get(lang.LogJWTGenerateError), req.Email, err)
 		return nil, err
 	}
 
 	log.Printf(s.messages.Get(lang.LogLoginComplete), req.Email)
-	return &responses.TokenResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	return response, nil
+}
+
+// LoginMFA завершает вход проверкой второго фактора
+func (s *authService) LoginMFA(ctx context.Context, req *requests.MFALoginRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
+	verification, err := s.mfa.VerifyChallenge(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, verification.UserID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), verification.UserID.String(), err)
+		return nil, err
+	}
+
+	if user == nil {
+		log.Printf(s.messages.Get(lang.LogUserNotFoundValidation), verification.UserID.String())
+		return nil, errors.New(s.messages.Get(lang.MFAChallengeInvalid))
+	}
+
+	if err := s.checkActive(user); err != nil {
+		return nil, err
+	}
+
+	// Генерируем пару токенов в новой сессии
+	response, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	response.RecoveryCodes = verification.RecoveryCodes
+
+	log.Printf(s.messages.Get(lang.LogLoginComplete), user.Email)
+	return response, nil
+}
+
+// LoginExternal завершает вход пользователя, которого подтвердил внешний провайдер (SSO).
+// Пароль не проверяется, но статус учетной записи и второй фактор - так же, как в Login.
+func (s *authService) LoginExternal(ctx context.Context, user *models.User, client models.ClientInfo) (*responses.TokenResponse, error) {
+	if err := s.checkActive(user); err != nil {
+		return nil, err
+	}
+
+	// Если нужен второй фактор, токены выдаются только после POST /api/v1/login/mfa
+	challenge, err := s.mfa.Challenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	// Генерируем пару токенов в новой сессии
+	response, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogLoginComplete), user.Email)
+	return response, nil
+}
+
+// Refresh обменивает refresh токен на новую пару токенов (ротация)
+func (s *authService) Refresh(ctx context.Context, req *requests.RefreshRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
+	// Проверяем и погашаем предъявленный refresh токен
+	record, err := s.tokenService.ConsumeRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Получаем актуальные данные пользователя из БД
+	user, err := s.userRepo.GetByID(ctx, record.UserID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), record.UserID.String(), err)
+		return nil, err
+	}
+
+	if user == nil {
+		log.Printf(s.messages.Get(lang.LogUserNotFoundValidation), record.UserID.String())
+		return nil, errors.New(s.messages.Get(lang.RefreshTokenInvalid))
+	}
+
+	if err := s.checkActive(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEmailVerified(user); err != nil {
+		return nil, err
+	}
+
+	// Сессия продлевается вместе с цепочкой и запоминает последний клиент
+	if err := s.tokenService.TouchSession(ctx, record.FamilyID, client); err != nil {
+		return nil, err
+	}
+
+	// Новый refresh токен остается в той же цепочке
+	response, err := s.issueTokens(ctx, user, record.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogRefreshComplete), user.Email)
+	return response, nil
+}
+
+// Logout отзывает текущий access токен и его цепочку refresh токенов
+func (s *authService) Logout(ctx context.Context, tokenString string) error {
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenService.RevokeSession(ctx, claims); err != nil {
+		log.Printf(s.messages.Get(lang.LogLogoutError), claims.Email, err)
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogLogoutComplete), claims.Email)
+	return nil
+}
+
+// LogoutAll отзывает все токены пользователя на всех устройствах
+func (s *authService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.tokenService.RevokeAllForUser(ctx, userID); err != nil {
+		log.Printf(s.messages.Get(lang.LogLogoutError), userID.String(), err)
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogLogoutAllComplete), userID.String())
+	return nil
 }
 
 // ValidateToken проверяет валидность JWT токена и возвращает пользователя
 func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*models.User, error) {
 	// Парсим токен
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
-	})
-
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
 	if err != nil {
-		log.Printf(s.messages.Get(lang.LogJWTParseError), err)
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
-		log.Printf(s.messages.Get(lang.LogJWTInvalid))
+	// Токен API клиента не представляет пользователя
+	if claims.IsClient() {
+		log.Printf(s.messages.Get(lang.LogJWTClientToken), claims.ClientID)
 		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
 	}
 
@@ -170,12 +337,17 @@ func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*m
 		return nil, errors.New(s.messages.Get(lang.UserNotFound))
 	}
 
+	// Токены, выпущенные до приостановки или деактивации, перестают действовать сразу
+	if err := s.checkActive(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
 // GenerateToken генерирует JWT токен для пользователя (публичный метод)
 func (s *authService) GenerateToken(user *models.User) (string, error) {
-	return s.generateJWT(user)
+	return s.tokenService.GenerateAccessToken(user, uuid.Nil)
 }
 
 // GetUserByID получает пользователя по ID
@@ -183,19 +355,136 @@ func (s *authService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.Us
 	return s.userRepo.GetByID(ctx, id)
 }
 
-// generateJWT генерирует JWT токен для пользователя (приватный метод)
-func (s *authService) generateJWT(user *models.User) (string, error) {
-	claims := JWTClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   user.ID.String(),
-		},
+// createUser проверяет уникальность email, хеширует пароль и сохраняет пользователя
+func (s *authService) createUser(ctx context.Context, email, password, role string, emailVerifiedAt *time.Time) (*models.User, error) {
+	log.Printf(s.messages.Get(lang.LogAttemptingRegistration), email)
+
+	// Проверяем существование пользователя
+	exists, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogCheckEmailExists), email, err)
+		return nil, err
+	}
+	if exists {
+		log.Printf(s.messages.Get(lang.LogEmailAlreadyExists), email)
+		return nil, errors.New(s.messages.Get(lang.UserAlreadyExists))
+	}
+
+	// Хешируем пароль
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogPasswordHashError), email, err)
+		return nil, err
+	}
+
+	// Создаем пользователя
+	user := &models.User{
+		ID:              uuid.New(),
+		Email:           email,
+		Password:        hashedPassword,
+		Role:            role,
+		Created:         time.Now(),
+		EmailVerifiedAt: emailVerifiedAt,
+		Status:          models.StatusActive,
+	}
+
+	// Сохраняем в БД
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		log.Printf(s.messages.Get(lang.LogUserCreateError), email, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// failLogin учитывает неудачную попытку входа и возвращает общую ошибку.
+// Ошибка учета уже записана в лог и не должна менять ответ клиенту.
+func (s *authService) failLogin(ctx context.Context, email, clientIP string) error {
+	_ = s.loginGuard.RecordFailure(ctx, email, clientIP)
+	return errors.New(s.messages.Get(lang.InvalidCredentials))
+}
+
+// RegistrationDomainPolicy собирает правила доменов email для самостоятельной регистрации.
+// Список разрешенных доменов действует только в режиме domain, запрещенные - в любом режиме.
+func RegistrationDomainPolicy(cfg config.AuthConfig) models.EmailDomainPolicy {
+	policy := models.EmailDomainPolicy{Denied: cfg.RegistrationDeniedDomains}
+	if cfg.RegistrationMode == config.RegistrationDomain {
+		policy.Allowed = cfg.RegistrationAllowedDomains
+	}
+	return policy
+}
+
+// checkRegistrationAllowed проверяет, разрешена ли самостоятельная регистрация email в текущем режиме.
+// Те же правила действуют для учетных записей, которые создаются при первом входе через SSO.
+func checkRegistrationAllowed(authConfig config.AuthConfig, email string, messages lang.Messages) error {
+	if authConfig.RegistrationMode == config.RegistrationInviteOnly {
+		log.Printf(messages.Get(lang.LogRegistrationRejected), email, authConfig.RegistrationMode)
+		return errors.New(messages.Get(lang.RegistrationInviteOnly))
+	}
+
+	policy := RegistrationDomainPolicy(authConfig)
+	if policy.IsDenied(email) {
+		log.Printf(messages.Get(lang.LogRegistrationDomainDenied), email)
+		return errors.New(messages.Get(lang.RegistrationDomainDenied))
+	}
+	if !policy.IsAllowed(email) {
+		log.Printf(messages.Get(lang.LogRegistrationRejected), email, authConfig.RegistrationMode)
+		return errors.New(messages.Get(lang.RegistrationDomainNotAllowed))
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return nil
+}
+
+// checkActive запрещает вход и выдачу токенов приостановленной или деактивированной учетной записи
+func (s *authService) checkActive(user *models.User) error {
+	if user.IsActive() {
+		return nil
+	}
+
+	log.Printf(s.messages.Get(lang.LogLoginInactive), user.Email, user.Status)
+	if user.Status == models.StatusDeactivated {
+		return errors.New(s.messages.Get(lang.AccountDeactivated))
+	}
+	return errors.New(s.messages.Get(lang.AccountSuspended))
+}
+
+// checkEmailVerified запрещает выдачу токенов неподтвержденному email, если это требуется конфигурацией
+func (s *authService) checkEmailVerified(user *models.User) error {
+	if !s.authConfig.RequireEmailVerification || user.IsEmailVerified() {
+		return nil
+	}
+
+	log.Printf(s.messages.Get(lang.LogEmailNotVerified), user.Email)
+	return errors.New(s.messages.Get(lang.EmailVerificationRequired))
+}
+
+// startSession начинает новую сессию и выпускает первую пару токенов ее цепочки
+func (s *authService) startSession(ctx context.Context, user *models.User, client models.ClientInfo) (*responses.TokenResponse, error) {
+	sessionID, err := s.tokenService.StartSession(ctx, user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, sessionID)
+}
+
+// issueTokens выпускает access токен и refresh токен в указанной цепочке
+func (s *authService) issueTokens(ctx context.Context, user *models.User, familyID uuid.UUID) (*responses.TokenResponse, error) {
+	accessToken, err := s.tokenService.GenerateAccessToken(user, familyID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogJWTGenerateError), user.Email, err)
+		return nil, err
+	}
+
+	refreshToken, err := s.tokenService.IssueRefreshToken(ctx, user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.tokenService.AccessTokenTTL().Seconds()),
+		User:         *user,
+	}, nil
 }
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/directory"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// Authenticator проверяет email и пароль при входе. Блокировка после неудачных попыток,
+// статус учетной записи и второй фактор проверяются в AuthService одинаково для всех реализаций.
+type Authenticator interface {
+	// Authenticate возвращает пользователя портала по верным учетным данным.
+	// nil, nil - пользователь не найден или пароль неверный.
+	Authenticate(ctx context.Context, email, password string) (*models.User, error)
+}
+
+// passwordAuthenticator проверка пароля по хешу из таблицы users
+type passwordAuthenticator struct {
+	userRepo repositories.UserRepository
+	hasher   passwords.Hasher
+	messages lang.Messages
+}
+
+// NewPasswordAuthenticator создает проверку пароля по хешу из БД
+func NewPasswordAuthenticator(userRepo repositories.UserRepository, hasher passwords.Hasher, messages lang.Messages) Authenticator {
+	return &passwordAuthenticator{
+		userRepo: userRepo,
+		hasher:   hasher,
+		messages: messages,
+	}
+}
+
+// Authenticate сравнивает пароль с хешем и обновляет устаревший хеш
+func (a *passwordAuthenticator) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		log.Printf(a.messages.Get(lang.LogDatabaseErrorLogin), email, err)
+		return nil, err
+	}
+	if user == nil {
+		log.Printf(a.messages.Get(lang.LogUserNotFoundLogin), email)
+		return nil, nil
+	}
+
+	// У пользователей из каталога или SSO локального пароля нет: пустой хеш не распознается
+	if ok, err := a.hasher.Verify(user.Password, password); err != nil || !ok {
+		log.Printf(a.messages.Get(lang.LogInvalidPassword), email)
+		return nil, nil
+	}
+
+	// Пароль известен только сейчас, поэтому устаревший хеш обновляется при входе
+	a.rehashPassword(ctx, user, password)
+	return user, nil
+}
+
+// rehashPassword перехеширует пароль, если хеш создан другим алгоритмом или слабее текущих настроек.
+// Ошибка не мешает входу: старый хеш остается рабочим и будет обновлен при следующем входе.
+func (a *passwordAuthenticator) rehashPassword(ctx context.Context, user *models.User, password string) {
+	if !a.hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hash, err := a.hasher.Hash(password)
+	if err == nil {
+		err = a.userRepo.UpdatePassword(ctx, user.ID, hash)
+	}
+	if err != nil {
+		log.Printf(a.messages.Get(lang.LogPasswordRehashFailed), user.ID.String(), err)
+		return
+	}
+
+	user.Password = hash
+	log.Printf(a.messages.Get(lang.LogPasswordRehashed), user.ID.String())
+}
+
+// directoryAuthenticator проверка пароля в корпоративном каталоге. Роль определяется
+// группами каталога, пользователь портала создается при первом входе.
+type directoryAuthenticator struct {
+	directory      directory.Directory
+	userRepo       repositories.UserRepository
+	roleChangeRepo repositories.RoleChangeRepository
+	ldapConfig     config.LDAPConfig
+	messages       lang.Messages
+}
+
+// NewDirectoryAuthenticator создает проверку пароля в корпоративном каталоге
+func NewDirectoryAuthenticator(
+	directory directory.Directory,
+	userRepo repositories.UserRepository,
+	roleChangeRepo repositories.RoleChangeRepository,
+	ldapConfig config.LDAPConfig,
+	messages lang.Messages,
+) Authenticator {
+	return &directoryAuthenticator{
+		directory:      directory,
+		userRepo:       userRepo,
+		roleChangeRepo: roleChangeRepo,
+		ldapConfig:     ldapConfig,
+		messages:       messages,
+	}
+}
+
+// Authenticate проверяет пароль в каталоге и находит, создает или обновляет пользователя портала
+func (a *directoryAuthenticator) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	entry, err := a.directory.Authenticate(ctx, email, password)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	role, ok := a.directoryRole(entry.Groups)
+	if !ok {
+		log.Printf(a.messages.Get(lang.LogLDAPAccessDenied), entry.Email)
+		return nil, nil
+	}
+
+	user, err := a.userRepo.GetByEmail(ctx, entry.Email)
+	if err != nil {
+		log.Printf(a.messages.Get(lang.LogDatabaseErrorLogin), entry.Email, err)
+		return nil, err
+	}
+	if user == nil {
+		return a.provisionUser(ctx, entry.Email, role)
+	}
+
+	if err := a.syncRole(ctx, user, role); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// directoryRole определяет роль по группам каталога. Без LDAP_EMPLOYEE_GROUPS доступ есть
+// у всех пользователей каталога; иначе нужна одна из групп сотрудников или менеджеров.
+func (a *directoryAuthenticator) directoryRole(groups []string) (string, bool) {
+	member := func(allowed []string) bool {
+		return slices.ContainsFunc(groups, func(group string) bool {
+			return slices.ContainsFunc(allowed, func(dn string) bool { return strings.EqualFold(dn, group) })
+		})
+	}
+
+	switch {
+	case member(a.ldapConfig.ManagerGroups):
+		return models.RoleManager, true
+	case len(a.ldapConfig.EmployeeGroups) == 0 || member(a.ldapConfig.EmployeeGroups):
+		return models.RoleEmployee, true
+	default:
+		return "", false
+	}
+}
+
+// provisionUser создает пользователя без локального пароля: пароль хранится только в каталоге.
+// Email подтвержден каталогом, поэтому письмо с подтверждением не требуется.
+func (a *directoryAuthenticator) provisionUser(ctx context.Context, email, role string) (*models.User, error) {
+	now := time.Now()
+	user := &models.User{
+		ID:              uuid.New(),
+		Email:           email,
+		Role:            role,
+		Created:         now,
+		EmailVerifiedAt: &now,
+		Status:          models.StatusActive,
+	}
+
+	if err := a.userRepo.Create(ctx, user); err != nil {
+		log.Printf(a.messages.Get(lang.LogUserCreateError), email, err)
+		return nil, err
+	}
+
+	if err := a.recordRoleChange(ctx, user.ID, nil, role); err != nil {
+		return nil, err
+	}
+
+	log.Printf(a.messages.Get(lang.LogLDAPUserProvisioned), email, role)
+	return user, nil
+}
+
+// syncRole переносит роль из групп каталога. Роль admin назначается только вручную
+// и каталогом не меняется. Access токены других сессий получат новую роль при обновлении.
+func (a *directoryAuthenticator) syncRole(ctx context.Context, user *models.User, role string) error {
+	if user.Role == role || user.Role == models.RoleAdmin {
+		return nil
+	}
+
+	if err := a.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		return err
+	}
+
+	oldRole := user.Role
+	if err := a.recordRoleChange(ctx, user.ID, &oldRole, role); err != nil {
+		return err
+	}
+
+	log.Printf(a.messages.Get(lang.LogLDAPRoleSynced), user.Email, oldRole, role)
+	user.Role = role
+	return nil
+}
+
+// recordRoleChange записывает роль из каталога в журнал смены ролей
+func (a *directoryAuthenticator) recordRoleChange(ctx context.Context, userID uuid.UUID, oldRole *string, role string) error {
+	return a.roleChangeRepo.Create(ctx, &models.RoleChange{
+		ID:      uuid.New(),
+		UserID:  userID,
+		OldRole: oldRole,
+		NewRole: role,
+		Source:  models.RoleChangeSourceDirectory,
+		Created: time.Now(),
+	})
+}
+
+// chainAuthenticator проверяет учетные данные по очереди, пока одна из проверок не подтвердит их
+type chainAuthenticator struct {
+	authenticators []Authenticator
+	messages       lang.Messages
+}
+
+// NewChainAuthenticator создает проверку по нескольким источникам в указанном порядке.
+// Недоступный источник не мешает входу через остальные.
+func NewChainAuthenticator(authenticators []Authenticator, messages lang.Messages) Authenticator {
+	if len(authenticators) == 1 {
+		return authenticators[0]
+	}
+	return &chainAuthenticator{
+		authenticators: authenticators,
+		messages:       messages,
+	}
+}
+
+// Authenticate возвращает пользователя от первой проверки, принявшей пароль. Если ни одна
+// не приняла, а какая-то завершилась ошибкой, возвращается ошибка: неверный пароль
+// не засчитывается, пока источник недоступен.
+func (a *chainAuthenticator) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	var lastErr error
+	for _, authenticator := range a.authenticators {
+		user, err := authenticator.Authenticate(ctx, email, password)
+		if err != nil {
+			log.Printf(a.messages.Get(lang.LogAuthenticatorFailed), email, err)
+			lastErr = err
+			continue
+		}
+		if user != nil {
+			return user, nil
+		}
+	}
+	return nil, lastErr
+}
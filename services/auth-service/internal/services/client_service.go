@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// Поддерживаемые grant_type эндпоинта токенов
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeAuthorizationCode = "authorization_code" // OpenID Connect
+)
+
+// Коды ошибок эндпоинта токенов (RFC 6749, раздел 5.2)
+const (
+	OAuthInvalidRequest       = "invalid_request"
+	OAuthInvalidClient        = "invalid_client"
+	OAuthUnsupportedGrantType = "unsupported_grant_type"
+	OAuthInvalidScope         = "invalid_scope"
+	OAuthInvalidGrant         = "invalid_grant"
+	OAuthUnauthorizedClient   = "unauthorized_client"
+
+	// Коды ошибок запроса авторизации (RFC 6749, раздел 4.1.2.1)
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+)
+
+// OAuthError ошибка выдачи токена с кодом из RFC 6749
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Description
+}
+
+// ClientService интерфейс для API клиентов сервисов и выдачи им токенов
+type ClientService interface {
+	Create(ctx context.Context, actor *models.User, req *requests.CreateClientRequest) (*responses.APIClientCreatedResponse, error)
+	List(ctx context.Context) ([]models.APIClient, error)
+	Revoke(ctx context.Context, actor *models.User, clientID uuid.UUID) error
+	IssueToken(ctx context.Context, req *requests.ClientTokenRequest) (*responses.ClientTokenResponse, error)
+	Authenticate(ctx context.Context, clientID, secret string) (*models.APIClient, error)
+	ValidateToken(ctx context.Context, tokenString string) (*models.AuthenticatedClient, error)
+}
+
+// clientService реализация ClientService
+type clientService struct {
+	clientRepo   repositories.APIClientRepository
+	tokenService TokenService
+	messages     lang.Messages
+}
+
+// NewClientService создает новый экземпляр ClientService
+func NewClientService(clientRepo repositories.APIClientRepository, tokenService TokenService, messages lang.Messages) ClientService {
+	return &clientService{
+		clientRepo:   clientRepo,
+		tokenService: tokenService,
+		messages:     messages,
+	}
+}
+
+// Create регистрирует API клиента и возвращает его секрет. Секрет показывается один раз.
+func (s *clientService) Create(ctx context.Context, actor *models.User, req *requests.CreateClientRequest) (*responses.APIClientCreatedResponse, error) {
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]models.Permission, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopes = appendScope(scopes, models.Permission(scope))
+	}
+
+	client := &models.APIClient{
+		ID:           uuid.New(),
+		Name:         req.Name,
+		SecretHash:   hashOpaqueToken(secret),
+		Scope:        models.FormatScope(scopes),
+		RedirectURIs: strings.Join(req.RedirectURIs, " "),
+		CreatedBy:    &actor.ID,
+		Created:      time.Now(),
+	}
+
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogAPIClientCreated), client.ID.String(), client.Name, client.Scope, actor.Email)
+	return &responses.APIClientCreatedResponse{Client: *client, ClientSecret: secret}, nil
+}
+
+// List возвращает всех API клиентов без секретов
+func (s *clientService) List(ctx context.Context) ([]models.APIClient, error) {
+	return s.clientRepo.List(ctx)
+}
+
+// Revoke отзывает API клиента: новые токены ему не выдаются, выданные перестают приниматься
+func (s *clientService) Revoke(ctx context.Context, actor *models.User, clientID uuid.UUID) error {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if client == nil || !client.IsActive() {
+		return errors.New(s.messages.Get(lang.APIClientNotFound))
+	}
+
+	if err := s.clientRepo.Revoke(ctx, client.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogAPIClientRevoked), client.ID.String(), actor.Email)
+	return nil
+}
+
+// IssueToken выдает токен по OAuth2 client_credentials. Без scope в запросе
+// токен получает все права клиента, иначе только запрошенные.
+func (s *clientService) IssueToken(ctx context.Context, req *requests.ClientTokenRequest) (*responses.ClientTokenResponse, error) {
+	if req.GrantType != GrantTypeClientCredentials {
+		return nil, s.oauthError(OAuthUnsupportedGrantType, lang.OAuthGrantTypeUnsupported)
+	}
+	if req.ClientID == "" || req.ClientSecret == "" {
+		return nil, s.oauthError(OAuthInvalidRequest, lang.OAuthClientCredentialsMissing)
+	}
+
+	client, err := s.Authenticate(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// Клиенту только для входа через OpenID Connect токены сервиса не выдаются
+	if len(client.Scopes()) == 0 {
+		return nil, s.oauthError(OAuthUnauthorizedClient, lang.OAuthClientNotAllowed)
+	}
+
+	scopes := client.Scopes()
+	if req.Scope != "" {
+		granted := scopes
+		scopes = nil
+		for _, scope := range models.ParseScope(req.Scope) {
+			if !containsScope(granted, scope) {
+				log.Printf(s.messages.Get(lang.LogClientScopeDenied), client.ID.String(), scope)
+				return nil, s.oauthError(OAuthInvalidScope, lang.OAuthScopeInvalid)
+			}
+			scopes = appendScope(scopes, scope)
+		}
+	}
+
+	token, err := s.tokenService.GenerateClientToken(client, scopes)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogJWTGenerateError), client.ID.String(), err)
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogClientTokenIssued), client.ID.String(), models.FormatScope(scopes))
+	return &responses.ClientTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.tokenService.AccessTokenTTL().Seconds()),
+		Scope:       models.FormatScope(scopes),
+	}, nil
+}
+
+// ValidateToken проверяет токен API клиента. Клиент читается из БД при каждой проверке,
+// поэтому отзыв клиента действует сразу.
+func (s *clientService) ValidateToken(ctx context.Context, tokenString string) (*models.AuthenticatedClient, error) {
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	// Токен, выданный внешнему сервису от имени пользователя, годится только для userinfo
+	if !claims.IsClient() || claims.UserID != uuid.Nil {
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+
+	clientID, err := uuid.Parse(claims.ClientID)
+	if err != nil {
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || !client.IsActive() {
+		log.Printf(s.messages.Get(lang.LogAPIClientInactive), claims.ClientID)
+		return nil, errors.New(s.messages.Get(lang.TokenRevoked))
+	}
+
+	return &models.AuthenticatedClient{
+		ID:     client.ID,
+		Name:   client.Name,
+		Scopes: models.ParseScope(claims.Scope),
+	}, nil
+}
+
+// Authenticate проверяет client_id и секрет. Неизвестный, отозванный клиент
+// и неверный секрет дают одну и ту же ошибку.
+func (s *clientService) Authenticate(ctx context.Context, rawClientID, secret string) (*models.APIClient, error) {
+	clientID, err := uuid.Parse(rawClientID)
+	if err != nil {
+		return nil, s.oauthError(OAuthInvalidClient, lang.OAuthClientInvalid)
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil || !client.IsActive() ||
+		subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashOpaqueToken(secret))) != 1 {
+		log.Printf(s.messages.Get(lang.LogClientAuthFailed), rawClientID)
+		return nil, s.oauthError(OAuthInvalidClient, lang.OAuthClientInvalid)
+	}
+
+	return client, nil
+}
+
+// oauthError создает ошибку эндпоинта токенов с описанием из сообщений
+func (s *clientService) oauthError(code string, description lang.MessageKey) error {
+	return &OAuthError{Code: code, Description: s.messages.Get(description)}
+}
+
+// appendScope добавляет право в список, пропуская повторы
+func appendScope(scopes []models.Permission, scope models.Permission) []models.Permission {
+	if containsScope(scopes, scope) {
+		return scopes
+	}
+	return append(scopes, scope)
+}
+
+// containsScope проверяет, есть ли право в списке
+func containsScope(scopes []models.Permission, scope models.Permission) bool {
+	for _, existing := range scopes {
+		if existing == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// EmailVerificationService интерфейс для сервиса подтверждения email
+type EmailVerificationService interface {
+	SendVerification(ctx context.Context, user *models.User) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, req *requests.ResendVerificationRequest) error
+}
+
+// emailVerificationService реализация EmailVerificationService
+type emailVerificationService struct {
+	userRepo           repositories.UserRepository
+	verificationRepo   repositories.EmailVerificationRepository
+	mailer             mail.Sender
+	verificationConfig config.EmailVerificationConfig
+	messages           lang.Messages
+}
+
+// NewEmailVerificationService создает новый экземпляр EmailVerificationService
+func NewEmailVerificationService(
+	userRepo repositories.UserRepository,
+	verificationRepo repositories.EmailVerificationRepository,
+	mailer mail.Sender,
+	verificationConfig config.EmailVerificationConfig,
+	messages lang.Messages,
+) EmailVerificationService {
+	return &emailVerificationService{
+		userRepo:           userRepo,
+		verificationRepo:   verificationRepo,
+		mailer:             mailer,
+		verificationConfig: verificationConfig,
+		messages:           messages,
+	}
+}
+
+// SendVerification выпускает одноразовый токен и отправляет письмо со ссылкой подтверждения
+func (s *emailVerificationService) SendVerification(ctx context.Context, user *models.User) error {
+	verificationToken, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	record := &models.EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashOpaqueToken(verificationToken),
+		ExpiresAt: now.Add(s.verificationConfig.TokenTTL),
+		Created:   now,
+	}
+
+	if err := s.verificationRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: s.messages.Get(lang.EmailVerificationEmailSubject),
+		Body:    s.messages.Get(lang.EmailVerificationEmailBody, s.verificationLink(verificationToken), int(s.verificationConfig.TokenTTL.Hours())),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf(s.messages.Get(lang.LogMailSendError), user.Email, err)
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogEmailVerificationSent), user.Email)
+	return nil
+}
+
+// VerifyEmail подтверждает email по одноразовому токену из письма
+func (s *emailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+	record, err := s.verificationRepo.GetByHash(ctx, hashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+
+	if record == nil || !record.IsActive(time.Now()) {
+		log.Printf(s.messages.Get(lang.LogEmailVerificationInvalid))
+		return errors.New(s.messages.Get(lang.EmailVerificationTokenInvalid))
+	}
+
+	// Токен мог быть использован параллельным запросом между чтением и обновлением
+	marked, err := s.verificationRepo.MarkUsed(ctx, record.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		log.Printf(s.messages.Get(lang.LogEmailVerificationInvalid))
+		return errors.New(s.messages.Get(lang.EmailVerificationTokenInvalid))
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, record.UserID); err != nil {
+		return err
+	}
+
+	// Остальные выданные ссылки больше не нужны
+	if err := s.verificationRepo.InvalidateForUser(ctx, record.UserID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogEmailVerificationComplete), record.UserID.String())
+	return nil
+}
+
+// ResendVerification повторно отправляет письмо подтверждения.
+// Для несуществующего или уже подтвержденного email ошибка не возвращается,
+// чтобы не раскрывать наличие аккаунта.
+func (s *emailVerificationService) ResendVerification(ctx context.Context, req *requests.ResendVerificationRequest) error {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || user.IsEmailVerified() {
+		return nil
+	}
+
+	// Ошибку отправки не возвращаем клиенту по той же причине, что и отсутствие пользователя.
+	// Причина уже записана в лог репозиторием или отправителем писем
+	_ = s.SendVerification(ctx, user)
+	return nil
+}
+
+// verificationLink формирует ссылку подтверждения email
+func (s *emailVerificationService) verificationLink(verificationToken string) string {
+	link, err := url.Parse(s.verificationConfig.URL)
+	if err != nil {
+		return s.verificationConfig.URL + "?token=" + url.QueryEscape(verificationToken)
+	}
+
+	query := link.Query()
+	query.Set("token", verificationToken)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
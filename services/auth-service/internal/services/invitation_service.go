@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// InvitationService интерфейс для сервиса приглашений
+type InvitationService interface {
+	Invite(ctx context.Context, inviter *models.User, req *requests.CreateInvitationRequest) (*models.Invitation, error)
+	Accept(ctx context.Context, req *requests.AcceptInvitationRequest, client models.ClientInfo) (*responses.TokenResponse, error)
+}
+
+// invitationService реализация InvitationService
+type invitationService struct {
+	userRepo         repositories.UserRepository
+	invitationRepo   repositories.InvitationRepository
+	roleChangeRepo   repositories.RoleChangeRepository
+	authService      AuthService
+	mailer           mail.Sender
+	invitationConfig config.InvitationConfig
+	messages         lang.Messages
+}
+
+// NewInvitationService создает новый экземпляр InvitationService
+func NewInvitationService(
+	userRepo repositories.UserRepository,
+	invitationRepo repositories.InvitationRepository,
+	roleChangeRepo repositories.RoleChangeRepository,
+	authService AuthService,
+	mailer mail.Sender,
+	invitationConfig config.InvitationConfig,
+	messages lang.Messages,
+) InvitationService {
+	return &invitationService{
+		userRepo:         userRepo,
+		invitationRepo:   invitationRepo,
+		roleChangeRepo:   roleChangeRepo,
+		authService:      authService,
+		mailer:           mailer,
+		invitationConfig: invitationConfig,
+		messages:         messages,
+	}
+}
+
+// Invite создает приглашение с указанной ролью и отправляет ссылку на email.
+// Без права users:manage можно приглашать только сотрудников.
+func (s *invitationService) Invite(ctx context.Context, inviter *models.User, req *requests.CreateInvitationRequest) (*models.Invitation, error) {
+	role := req.Role
+	if role == "" {
+		role = models.RoleEmployee
+	}
+	if role != models.RoleEmployee && !models.HasPermission(inviter.Role, models.PermissionUsersManage) {
+		return nil, errors.New(s.messages.Get(lang.InvitationRoleDenied))
+	}
+
+	exists, err := s.userRepo.EmailExists(ctx, req.Email)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogCheckEmailExists), req.Email, err)
+		return nil, err
+	}
+	if exists {
+		log.Printf(s.messages.Get(lang.LogEmailAlreadyExists), req.Email)
+		return nil, errors.New(s.messages.Get(lang.UserAlreadyExists))
+	}
+
+	invitationToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &models.Invitation{
+		ID:        uuid.New(),
+		Email:     req.Email,
+		Role:      role,
+		TokenHash: hashOpaqueToken(invitationToken),
+		InvitedBy: &inviter.ID,
+		ExpiresAt: now.Add(s.invitationConfig.TokenTTL),
+		Created:   now,
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	// Без письма приглашение бесполезно, поэтому ошибку отправки возвращаем
+	msg := mail.Message{
+		To:      invitation.Email,
+		Subject: s.messages.Get(lang.InvitationEmailSubject),
+		Body:    s.messages.Get(lang.InvitationEmailBody, inviter.Email, invitation.Role, s.invitationLink(invitationToken), int(s.invitationConfig.TokenTTL.Hours())),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf(s.messages.Get(lang.LogMailSendError), invitation.Email, err)
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogInvitationSent), invitation.Role, invitation.Email, inviter.Email)
+	return invitation, nil
+}
+
+// Accept создает аккаунт по приглашению с ролью, назначенной пригласившим
+func (s *invitationService) Accept(ctx context.Context, req *requests.AcceptInvitationRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
+	invitation, err := s.invitationRepo.GetByHash(ctx, hashOpaqueToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation == nil || !invitation.IsActive(time.Now()) {
+		log.Printf(s.messages.Get(lang.LogInvitationInvalid))
+		return nil, errors.New(s.messages.Get(lang.InvitationInvalid))
+	}
+
+	// В запросе нет email, поэтому правило политики проверяется по адресу из приглашения
+	if passwords.MatchesEmail(req.Password, invitation.Email) {
+		return nil, errors.New(s.messages.Get(lang.ValidationPasswordEmail))
+	}
+
+	// Повторное принятие упрется в уникальность email, поэтому приглашение
+	// погашается после создания аккаунта и не сгорает при временной ошибке
+	response, err := s.authService.RegisterInvited(ctx, invitation, req.Password, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.invitationRepo.MarkAccepted(ctx, invitation.ID); err != nil {
+		return nil, err
+	}
+
+	// Роль выше сотрудника фиксируется в журнале так же, как смена роли администратором
+	if invitation.Role != models.RoleEmployee {
+		change := &models.RoleChange{
+			ID:        uuid.New(),
+			UserID:    response.User.ID,
+			NewRole:   invitation.Role,
+			ChangedBy: invitation.InvitedBy,
+			Source:    models.RoleChangeSourceInvitation,
+			Created:   time.Now(),
+		}
+		if err := s.roleChangeRepo.Create(ctx, change); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf(s.messages.Get(lang.LogInvitationAccepted), invitation.ID.String(), invitation.Email)
+	return response, nil
+}
+
+// invitationLink формирует ссылку принятия приглашения
+func (s *invitationService) invitationLink(invitationToken string) string {
+	link, err := url.Parse(s.invitationConfig.URL)
+	if err != nil {
+		return s.invitationConfig.URL + "?token=" + url.QueryEscape(invitationToken)
+	}
+
+	query := link.Query()
+	query.Set("token", invitationToken)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
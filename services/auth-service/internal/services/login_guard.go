@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/repositories"
+)
+
+// LoginGuard интерфейс защиты входа от перебора паролей.
+// Неудачные попытки считаются отдельно по аккаунту и по IP клиента.
+type LoginGuard interface {
+	Check(ctx context.Context, email, clientIP string) error
+	RecordFailure(ctx context.Context, email, clientIP string) error
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+}
+
+// loginGuard реализация LoginGuard
+type loginGuard struct {
+	attempts      repositories.LoginAttemptRepository
+	lockoutConfig config.LockoutConfig
+	messages      lang.Messages
+}
+
+// NewLoginGuard создает новый экземпляр LoginGuard
+func NewLoginGuard(attempts repositories.LoginAttemptRepository, lockoutConfig config.LockoutConfig, messages lang.Messages) LoginGuard {
+	return &loginGuard{
+		attempts:      attempts,
+		lockoutConfig: lockoutConfig,
+		messages:      messages,
+	}
+}
+
+// Check возвращает ошибку, если вход для аккаунта или IP временно заблокирован
+func (g *loginGuard) Check(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+
+	if err := g.checkKey(ctx, emailKey(email), lang.AccountLocked, now); err != nil {
+		return err
+	}
+
+	if clientIP == "" {
+		return nil
+	}
+	return g.checkKey(ctx, ipKey(clientIP), lang.ClientLocked, now)
+}
+
+// RecordFailure учитывает неудачную попытку и при превышении порога блокирует вход
+func (g *loginGuard) RecordFailure(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+
+	if err := g.registerFailure(ctx, emailKey(email), g.lockoutConfig.MaxFailures, now); err != nil {
+		return err
+	}
+
+	if clientIP == "" {
+		return nil
+	}
+	return g.registerFailure(ctx, ipKey(clientIP), g.lockoutConfig.IPMaxFailures, now)
+}
+
+// RecordSuccess сбрасывает счетчик аккаунта после успешной проверки пароля.
+// Счетчик IP не сбрасывается: иначе перебор можно чередовать со входом в свой аккаунт.
+func (g *loginGuard) RecordSuccess(ctx context.Context, email string) error {
+	return g.attempts.Reset(ctx, emailKey(email))
+}
+
+// Unlock снимает блокировку аккаунта досрочно
+func (g *loginGuard) Unlock(ctx context.Context, email string) error {
+	if err := g.attempts.Reset(ctx, emailKey(email)); err != nil {
+		return err
+	}
+
+	log.Printf(g.messages.Get(lang.LogLoginUnlocked), email)
+	return nil
+}
+
+// checkKey проверяет блокировку по одному ключу
+func (g *loginGuard) checkKey(ctx context.Context, key string, lockedKey lang.MessageKey, now time.Time) error {
+	attempt, err := g.attempts.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if attempt == nil || !attempt.IsLocked(now) {
+		return nil
+	}
+
+	log.Printf(g.messages.Get(lang.LogLoginLocked), key, attempt.LockedUntil.Format(time.RFC3339))
+	minutes := int(math.Ceil(attempt.LockedUntil.Sub(now).Minutes()))
+	return errors.New(g.messages.Get(lockedKey, minutes))
+}
+
+// registerFailure увеличивает счетчик по ключу и блокирует вход после maxFailures ошибок.
+// Каждая следующая ошибка после порога удваивает блокировку до MaxDuration.
+func (g *loginGuard) registerFailure(ctx context.Context, key string, maxFailures int, now time.Time) error {
+	attempt, err := g.attempts.RegisterFailure(ctx, key, now, now.Add(-g.lockoutConfig.FailureWindow))
+	if err != nil {
+		return err
+	}
+
+	if attempt.Failures < maxFailures {
+		return nil
+	}
+
+	until := now.Add(g.lockDuration(attempt.Failures - maxFailures))
+	if err := g.attempts.Lock(ctx, key, until); err != nil {
+		return err
+	}
+
+	log.Printf(g.messages.Get(lang.LogLoginLockApplied), attempt.Failures, key, until.Format(time.RFC3339))
+	return nil
+}
+
+// lockDuration вычисляет длительность блокировки: BaseDuration * 2^excess, но не больше MaxDuration
+func (g *loginGuard) lockDuration(excess int) time.Duration {
+	duration := g.lockoutConfig.BaseDuration
+	for i := 0; i < excess && duration < g.lockoutConfig.MaxDuration; i++ {
+		duration *= 2
+	}
+
+	if duration > g.lockoutConfig.MaxDuration {
+		return g.lockoutConfig.MaxDuration
+	}
+	return duration
+}
+
+// emailKey ключ счетчика для аккаунта
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// ipKey ключ счетчика для IP клиента
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
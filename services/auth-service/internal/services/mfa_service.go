@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/avangero/auth-service/internal/totp"
+	"github.com/google/uuid"
+)
+
+const (
+	// mfaMaxAttempts число неверных кодов, после которого придется заново ввести пароль
+	mfaMaxAttempts = 5
+	// recoveryCodeCount число кодов восстановления, выдаваемых при подключении второго фактора
+	recoveryCodeCount = 10
+	// recoveryCodeBytes длина случайной части кода восстановления (80 бит)
+	recoveryCodeBytes = 10
+)
+
+// MFAService интерфейс для сервиса двухфакторной аутентификации (TOTP)
+type MFAService interface {
+	Challenge(ctx context.Context, user *models.User) (*responses.TokenResponse, error)
+	VerifyChallenge(ctx context.Context, req *requests.MFALoginRequest) (*MFAVerification, error)
+	Enroll(ctx context.Context, userID uuid.UUID) (*responses.MFAEnrollmentResponse, error)
+	EnrollWithChallenge(ctx context.Context, req *requests.MFAEnrollRequest) (*responses.MFAEnrollmentResponse, error)
+	Confirm(ctx context.Context, userID uuid.UUID, req *requests.MFAConfirmRequest) (*responses.MFARecoveryCodesResponse, error)
+}
+
+// MFAVerification результат успешной проверки второго фактора
+type MFAVerification struct {
+	UserID        uuid.UUID
+	RecoveryCodes []string // новые коды, если второй фактор был подключен во время входа
+}
+
+// mfaService реализация MFAService
+type mfaService struct {
+	userRepo      repositories.UserRepository
+	mfaRepo       repositories.MFARepository
+	challengeRepo repositories.MFAChallengeRepository
+	mfaConfig     config.MFAConfig
+	messages      lang.Messages
+}
+
+// NewMFAService создает новый экземпляр MFAService
+func NewMFAService(
+	userRepo repositories.UserRepository,
+	mfaRepo repositories.MFARepository,
+	challengeRepo repositories.MFAChallengeRepository,
+	mfaConfig config.MFAConfig,
+	messages lang.Messages,
+) MFAService {
+	return &mfaService{
+		userRepo:      userRepo,
+		mfaRepo:       mfaRepo,
+		challengeRepo: challengeRepo,
+		mfaConfig:     mfaConfig,
+		messages:      messages,
+	}
+}
+
+// Challenge начинает второй шаг входа после успешной проверки пароля.
+// Возвращает nil, если второй фактор пользователю не нужен.
+func (s *mfaService) Challenge(ctx context.Context, user *models.User) (*responses.TokenResponse, error) {
+	mfa, err := s.mfaRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled := mfa != nil && mfa.IsConfirmed()
+	if !enrolled && !s.isRequiredFor(user) {
+		return nil, nil
+	}
+
+	challengeToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	record := &models.MFAChallenge{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashOpaqueToken(challengeToken),
+		ExpiresAt: now.Add(s.mfaConfig.ChallengeTTL),
+		Created:   now,
+	}
+
+	if err := s.challengeRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogMFAChallengeIssued), user.Email)
+	return &responses.TokenResponse{
+		User:                  *user,
+		MFARequired:           true,
+		MFAEnrollmentRequired: !enrolled,
+		MFAToken:              challengeToken,
+	}, nil
+}
+
+// VerifyChallenge проверяет код второго фактора и завершает вход.
+// Если второй фактор обязателен, но еще не подключен, первый верный код подтверждает подключение.
+func (s *mfaService) VerifyChallenge(ctx context.Context, req *requests.MFALoginRequest) (*MFAVerification, error) {
+	challenge, err := s.activeChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepo.GetByUserID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil {
+		return nil, errors.New(s.messages.Get(lang.MFANotEnrolled))
+	}
+
+	verification := &MFAVerification{UserID: challenge.UserID}
+	if mfa.IsConfirmed() {
+		valid, err := s.verifyCode(ctx, mfa, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, s.failAttempt(ctx, challenge)
+		}
+	} else {
+		recoveryCodes, err := s.confirm(ctx, mfa, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if recoveryCodes == nil {
+			return nil, s.failAttempt(ctx, challenge)
+		}
+		verification.RecoveryCodes = recoveryCodes
+	}
+
+	// Вход мог быть завершен параллельным запросом между чтением и обновлением
+	marked, err := s.challengeRepo.MarkUsed(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		log.Printf(s.messages.Get(lang.LogMFAChallengeInvalid))
+		return nil, errors.New(s.messages.Get(lang.MFAChallengeInvalid))
+	}
+
+	return verification, nil
+}
+
+// Enroll выпускает новый секрет TOTP. Второй фактор включается только после Confirm.
+func (s *mfaService) Enroll(ctx context.Context, userID uuid.UUID) (*responses.MFAEnrollmentResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), userID.String(), err)
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New(s.messages.Get(lang.UserNotFound))
+	}
+
+	mfa, err := s.mfaRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa != nil && mfa.IsConfirmed() {
+		return nil, errors.New(s.messages.Get(lang.MFAAlreadyEnabled))
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &models.UserMFA{
+		UserID:  userID,
+		Secret:  secret,
+		Created: time.Now(),
+	}
+
+	if err := s.mfaRepo.SavePending(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogMFAEnrollmentStarted), user.Email)
+	return &responses.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.mfaConfig.Issuer, user.Email, secret),
+	}, nil
+}
+
+// EnrollWithChallenge выпускает секрет TOTP во время входа, когда второй фактор обязателен,
+// а токенов доступа у пользователя еще нет
+func (s *mfaService) EnrollWithChallenge(ctx context.Context, req *requests.MFAEnrollRequest) (*responses.MFAEnrollmentResponse, error) {
+	challenge, err := s.activeChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Enroll(ctx, challenge.UserID)
+}
+
+// Confirm включает второй фактор по первому коду из приложения и выдает коды восстановления
+func (s *mfaService) Confirm(ctx context.Context, userID uuid.UUID, req *requests.MFAConfirmRequest) (*responses.MFARecoveryCodesResponse, error) {
+	mfa, err := s.mfaRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil {
+		return nil, errors.New(s.messages.Get(lang.MFANotEnrolled))
+	}
+
+	if mfa.IsConfirmed() {
+		return nil, errors.New(s.messages.Get(lang.MFAAlreadyEnabled))
+	}
+
+	recoveryCodes, err := s.confirm(ctx, mfa, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if recoveryCodes == nil {
+		return nil, errors.New(s.messages.Get(lang.MFACodeInvalid))
+	}
+
+	return &responses.MFARecoveryCodesResponse{
+		Message:       s.messages.Get(lang.MFAEnabled),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// isRequiredFor проверяет, обязателен ли второй фактор для роли пользователя.
+// Администратор имеет все права менеджера, поэтому требование распространяется и на него.
+func (s *mfaService) isRequiredFor(user *models.User) bool {
+	return s.mfaConfig.RequiredForManagers && (user.Role == models.RoleManager || user.Role == models.RoleAdmin)
+}
+
+// activeChallenge находит незавершенный вход по токену и проверяет, что он еще действует
+func (s *mfaService) activeChallenge(ctx context.Context, challengeToken string) (*models.MFAChallenge, error) {
+	challenge, err := s.challengeRepo.GetByHash(ctx, hashOpaqueToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if challenge == nil || !challenge.IsActive(time.Now(), mfaMaxAttempts) {
+		log.Printf(s.messages.Get(lang.LogMFAChallengeInvalid))
+		return nil, errors.New(s.messages.Get(lang.MFAChallengeInvalid))
+	}
+
+	return challenge, nil
+}
+
+// failAttempt учитывает неверный код во время входа
+func (s *mfaService) failAttempt(ctx context.Context, challenge *models.MFAChallenge) error {
+	if err := s.challengeRepo.IncrementAttempts(ctx, challenge.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogMFACodeInvalid), challenge.UserID.String())
+	return errors.New(s.messages.Get(lang.MFACodeInvalid))
+}
+
+// verifyCode проверяет код из приложения или код восстановления.
+// Каждый код принимается только один раз.
+func (s *mfaService) verifyCode(ctx context.Context, mfa *models.UserMFA, code string) (bool, error) {
+	if step, ok := totp.Validate(mfa.Secret, code, time.Now()); ok {
+		return s.mfaRepo.UseStep(ctx, mfa.UserID, step)
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, mfa.UserID, hashOpaqueToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+	if used {
+		log.Printf(s.messages.Get(lang.LogMFARecoveryCodeUsed), mfa.UserID.String())
+	}
+
+	return used, nil
+}
+
+// confirm проверяет первый код из приложения, включает второй фактор и выпускает коды восстановления.
+// Возвращает nil без ошибки, если код неверен.
+func (s *mfaService) confirm(ctx context.Context, mfa *models.UserMFA, code string) ([]string, error) {
+	step, ok := totp.Validate(mfa.Secret, code, time.Now())
+	if !ok {
+		log.Printf(s.messages.Get(lang.LogMFACodeInvalid), mfa.UserID.String())
+		return nil, nil
+	}
+
+	// Подключение могло быть подтверждено параллельным запросом
+	confirmed, err := s.mfaRepo.Confirm(ctx, mfa.UserID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, errors.New(s.messages.Get(lang.MFAAlreadyEnabled))
+	}
+
+	recoveryCodes, err := s.issueRecoveryCodes(ctx, mfa.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogMFAEnabled), mfa.UserID.String())
+	return recoveryCodes, nil
+}
+
+// issueRecoveryCodes заменяет коды восстановления пользователя новым набором
+func (s *mfaService) issueRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	now := time.Now()
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.MFARecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		records = append(records, models.MFARecoveryCode{
+			ID:       uuid.New(),
+			UserID:   userID,
+			CodeHash: hashOpaqueToken(normalizeRecoveryCode(code)),
+			Created:  now,
+		})
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, records); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// newRecoveryCode генерирует код восстановления вида xxxx-xxxx-xxxx-xxxx
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw))
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeRecoveryCode приводит введенный код восстановления к виду, от которого считается хеш
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Значения параметров OpenID Connect, которые поддерживает провайдер
+const (
+	ResponseTypeCode          = "code"
+	CodeChallengeMethodS256   = "S256"
+	ScopeOpenID               = "openid"
+	ScopeEmail                = "email"
+	maxAuthorizeParamLength   = 255 // nonce и state
+	pkceChallengeLength       = 43  // base64url от SHA-256 без дополнения
+	minCodeVerifierLength     = 43
+	maxCodeVerifierLength     = 128
+	oidcUserInfoPath          = "/oauth/userinfo"
+	oidcAuthorizationEndpoint = "/oauth/authorize"
+	oidcTokenEndpoint         = "/oauth/token"
+	oidcJWKSPath              = "/.well-known/jwks.json"
+)
+
+// IDTokenClaims представляет данные ID токена OpenID Connect.
+// Email и EmailVerified заполняются только при выданном scope email.
+type IDTokenClaims struct {
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Role          string `json:"role"`
+	Nonce         string `json:"nonce,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// OIDCService интерфейс встроенного OpenID Connect провайдера
+type OIDCService interface {
+	Discovery() *responses.OIDCDiscoveryResponse
+	CheckAuthorization(ctx context.Context, req *requests.AuthorizeRequest) error
+	Authorize(ctx context.Context, tokenString string, req *requests.AuthorizeRequest) (*responses.AuthorizeResponse, error)
+	ExchangeCode(ctx context.Context, req *requests.ClientTokenRequest) (*responses.ClientTokenResponse, error)
+	UserInfo(ctx context.Context, tokenString string) (*responses.UserInfoResponse, error)
+}
+
+// oidcService реализация OIDCService
+type oidcService struct {
+	codeRepo      repositories.AuthorizationCodeRepository
+	clientRepo    repositories.APIClientRepository
+	clientService ClientService
+	authService   AuthService
+	tokenService  TokenService
+	keySet        *keys.KeySet
+	oidcConfig    config.OIDCConfig
+	messages      lang.Messages
+}
+
+// NewOIDCService создает новый экземпляр OIDCService
+func NewOIDCService(codeRepo repositories.AuthorizationCodeRepository, clientRepo repositories.APIClientRepository, clientService ClientService, authService AuthService, tokenService TokenService, keySet *keys.KeySet, oidcConfig config.OIDCConfig, messages lang.Messages) OIDCService {
+	// Подпись общим секретом внешние сервисы проверить не могут: JWKS при HS256 пуст
+	if keySet.SigningMethod() == jwt.SigningMethodHS256 {
+		log.Print(messages.Get(lang.LogOIDCSymmetricKey))
+	}
+
+	return &oidcService{
+		codeRepo:      codeRepo,
+		clientRepo:    clientRepo,
+		clientService: clientService,
+		authService:   authService,
+		tokenService:  tokenService,
+		keySet:        keySet,
+		oidcConfig:    oidcConfig,
+		messages:      messages,
+	}
+}
+
+// Discovery возвращает документ /.well-known/openid-configuration
+func (s *oidcService) Discovery() *responses.OIDCDiscoveryResponse {
+	issuer := s.oidcConfig.Issuer
+	return &responses.OIDCDiscoveryResponse{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + oidcAuthorizationEndpoint,
+		TokenEndpoint:                     issuer + oidcTokenEndpoint,
+		UserInfoEndpoint:                  issuer + oidcUserInfoPath,
+		JWKSURI:                           issuer + oidcJWKSPath,
+		ResponseTypesSupported:            []string{ResponseTypeCode},
+		GrantTypesSupported:               []string{GrantTypeAuthorizationCode, GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{s.keySet.SigningMethod().Alg()},
+		ScopesSupported:                   []string{ScopeOpenID, ScopeEmail},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "email", "email_verified", "role"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{CodeChallengeMethodS256},
+	}
+}
+
+// CheckAuthorization проверяет запрос авторизации до входа пользователя.
+// Неизвестный клиент или незарегистрированный redirect_uri возвращаются обычной ошибкой:
+// перенаправлять на такой адрес нельзя. Остальные ошибки - *OAuthError для возврата клиенту.
+func (s *oidcService) CheckAuthorization(ctx context.Context, req *requests.AuthorizeRequest) error {
+	_, err := s.checkAuthorization(ctx, req)
+	return err
+}
+
+// Authorize выдает код авторизации пользователю, вошедшему в портал, и возвращает адрес
+// возврата во внешний сервис. Код привязывается к сессии портала из токена.
+func (s *oidcService) Authorize(ctx context.Context, tokenString string, req *requests.AuthorizeRequest) (*responses.AuthorizeResponse, error) {
+	client, err := s.checkAuthorization(ctx, req)
+	if err != nil {
+		var oauthErr *OAuthError
+		if errors.As(err, &oauthErr) {
+			return &responses.AuthorizeResponse{RedirectTo: AuthorizationErrorRedirect(req, oauthErr)}, nil
+		}
+		return nil, err
+	}
+
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	record := &models.AuthorizationCode{
+		CodeHash:      hashOpaqueToken(code),
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		SessionID:     claims.SessionID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         grantedOIDCScope(req.Scope),
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     now.Add(s.oidcConfig.CodeTTL),
+		Created:       now,
+	}
+	if err := s.codeRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogOIDCCodeIssued), client.ID.String(), user.Email)
+	return &responses.AuthorizeResponse{
+		RedirectTo: withQuery(req.RedirectURI, url.Values{"code": {code}, "state": {req.State}}),
+	}, nil
+}
+
+// ExchangeCode обменивает код авторизации на access и ID токены (grant_type=authorization_code).
+// Код одноразовый, выдается только тому же клиенту и redirect_uri и проверяется по PKCE.
+func (s *oidcService) ExchangeCode(ctx context.Context, req *requests.ClientTokenRequest) (*responses.ClientTokenResponse, error) {
+	if req.ClientID == "" || req.ClientSecret == "" {
+		return nil, s.oauthError(OAuthInvalidRequest, lang.OAuthClientCredentialsMissing)
+	}
+
+	client, err := s.clientService.Authenticate(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if client.RedirectURIs == "" {
+		return nil, s.oauthError(OAuthUnauthorizedClient, lang.OAuthClientNotAllowed)
+	}
+
+	if req.Code == "" || req.RedirectURI == "" || req.CodeVerifier == "" {
+		return nil, s.oauthError(OAuthInvalidRequest, lang.OAuthCodeRequestInvalid)
+	}
+
+	now := time.Now()
+	code, err := s.codeRepo.Consume(ctx, hashOpaqueToken(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if code == nil || code.ClientID != client.ID || code.RedirectURI != req.RedirectURI || !now.Before(code.ExpiresAt) {
+		log.Printf(s.messages.Get(lang.LogOIDCCodeInvalid), client.ID.String())
+		return nil, s.oauthError(OAuthInvalidGrant, lang.OAuthCodeInvalid)
+	}
+
+	if !verifyPKCE(req.CodeVerifier, code.CodeChallenge) {
+		log.Printf(s.messages.Get(lang.LogOIDCPKCEFailed), client.ID.String())
+		return nil, s.oauthError(OAuthInvalidGrant, lang.OAuthCodeInvalid)
+	}
+
+	user, err := s.activeUser(ctx, code.UserID)
+	if err != nil {
+		return nil, s.oauthError(OAuthInvalidGrant, lang.OAuthCodeInvalid)
+	}
+
+	accessToken, err := s.tokenService.GenerateDelegatedToken(user, client, code.SessionID, code.Scope)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogJWTGenerateError), user.Email, err)
+		return nil, err
+	}
+
+	idToken, err := s.generateIDToken(user, client, code.Scope, code.Nonce, now)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogJWTGenerateError), user.Email, err)
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogOIDCTokensIssued), client.ID.String(), user.Email)
+	return &responses.ClientTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.tokenService.AccessTokenTTL().Seconds()),
+		Scope:       code.Scope,
+		IDToken:     idToken,
+	}, nil
+}
+
+// UserInfo возвращает данные пользователя по access токену, выданному внешнему сервису.
+// Токен перестает приниматься после завершения сессии портала или отзыва клиента.
+func (s *oidcService) UserInfo(ctx context.Context, tokenString string) (*responses.UserInfoResponse, error) {
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.IsClient() || claims.UserID == uuid.Nil || !hasOIDCScope(claims.Scope, ScopeOpenID) {
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+
+	clientID, err := uuid.Parse(claims.ClientID)
+	if err != nil {
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || !client.IsActive() {
+		log.Printf(s.messages.Get(lang.LogAPIClientInactive), claims.ClientID)
+		return nil, errors.New(s.messages.Get(lang.TokenRevoked))
+	}
+
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	email, emailVerified := emailClaims(user, claims.Scope)
+	return &responses.UserInfoResponse{
+		Subject:       user.ID.String(),
+		Email:         email,
+		EmailVerified: emailVerified,
+		Role:          user.Role,
+	}, nil
+}
+
+// checkAuthorization проверяет клиента, redirect_uri и параметры запроса авторизации
+func (s *oidcService) checkAuthorization(ctx context.Context, req *requests.AuthorizeRequest) (*models.APIClient, error) {
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return nil, errors.New(s.messages.Get(lang.OIDCClientInvalid))
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || !client.IsActive() || !client.AllowsRedirect(req.RedirectURI) {
+		return nil, errors.New(s.messages.Get(lang.OIDCClientInvalid))
+	}
+
+	if req.ResponseType != ResponseTypeCode {
+		return nil, s.oauthError(OAuthUnsupportedResponseType, lang.OAuthResponseTypeUnsupported)
+	}
+	if !hasOIDCScope(req.Scope, ScopeOpenID) {
+		return nil, s.oauthError(OAuthInvalidScope, lang.OAuthOpenIDScopeMissing)
+	}
+	if req.CodeChallengeMethod != CodeChallengeMethodS256 || len(req.CodeChallenge) != pkceChallengeLength {
+		return nil, s.oauthError(OAuthInvalidRequest, lang.OAuthPKCERequired)
+	}
+	if len(req.Nonce) > maxAuthorizeParamLength || len(req.State) > maxAuthorizeParamLength {
+		return nil, s.oauthError(OAuthInvalidRequest, lang.InvalidRequestFormat)
+	}
+
+	return client, nil
+}
+
+// activeUser загружает пользователя через AuthService и проверяет, что учетная запись активна
+func (s *oidcService) activeUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := s.authService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(s.messages.Get(lang.UserNotFound))
+	}
+	if !user.IsActive() {
+		log.Printf(s.messages.Get(lang.LogLoginInactive), user.Email, user.Status)
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+	return user, nil
+}
+
+// generateIDToken подписывает ID токен для внешнего сервиса тем же ключом, что и access токены
+func (s *oidcService) generateIDToken(user *models.User, client *models.APIClient, scope, nonce string, now time.Time) (string, error) {
+	email, emailVerified := emailClaims(user, scope)
+	claims := IDTokenClaims{
+		Email:         email,
+		EmailVerified: emailVerified,
+		Role:          user.Role,
+		Nonce:         nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.oidcConfig.Issuer,
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{client.ID.String()},
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.tokenService.AccessTokenTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(s.keySet.SigningMethod(), claims)
+	return s.keySet.Sign(token)
+}
+
+// oauthError создает ошибку OpenID Connect с описанием из сообщений
+func (s *oidcService) oauthError(code string, description lang.MessageKey) error {
+	return &OAuthError{Code: code, Description: s.messages.Get(description)}
+}
+
+// AuthorizationErrorRedirect возвращает адрес возврата во внешний сервис с ошибкой авторизации
+func AuthorizationErrorRedirect(req *requests.AuthorizeRequest, err *OAuthError) string {
+	return withQuery(req.RedirectURI, url.Values{
+		"error":             {err.Code},
+		"error_description": {err.Description},
+		"state":             {req.State},
+	})
+}
+
+// withQuery добавляет параметры к адресу, сохраняя его собственные; пустые значения пропускаются
+func withQuery(rawURL string, params url.Values) string {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := target.Query()
+	for key, values := range params {
+		if len(values) > 0 && values[0] != "" {
+			query.Set(key, values[0])
+		}
+	}
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// verifyPKCE сравнивает code_verifier с сохраненным code_challenge по методу S256
+func verifyPKCE(verifier, challenge string) bool {
+	if len(verifier) < minCodeVerifierLength || len(verifier) > maxCodeVerifierLength {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(pkceChallenge(verifier)), []byte(challenge)) == 1
+}
+
+// pkceChallenge вычисляет code_challenge по методу S256
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// grantedOIDCScope оставляет из запрошенного scope только поддерживаемые значения;
+// неизвестные значения по спецификации игнорируются
+func grantedOIDCScope(scope string) string {
+	granted := []models.Permission{ScopeOpenID}
+	if hasOIDCScope(scope, ScopeEmail) {
+		granted = append(granted, ScopeEmail)
+	}
+	return models.FormatScope(granted)
+}
+
+// emailClaims возвращает email и признак его подтверждения, если выдан scope email.
+// Без этого scope внешний сервис не получает адрес (OpenID Connect Core, раздел 5.4).
+func emailClaims(user *models.User, scope string) (string, *bool) {
+	if !hasOIDCScope(scope, ScopeEmail) {
+		return "", nil
+	}
+	verified := user.IsEmailVerified()
+	return user.Email, &verified
+}
+
+// hasOIDCScope проверяет, есть ли значение в scope через пробел
+func hasOIDCScope(scope, value string) bool {
+	return containsScope(models.ParseScope(scope), models.Permission(value))
+}
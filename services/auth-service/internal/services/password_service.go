@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// PasswordService интерфейс для сервиса восстановления пароля
+type PasswordService interface {
+	ForgotPassword(ctx context.Context, req *requests.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *requests.ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *requests.ChangePasswordRequest) error
+	ForceReset(ctx context.Context, user *models.User) error
+}
+
+// passwordService реализация PasswordService
+type passwordService struct {
+	userRepo     repositories.UserRepository
+	resetRepo    repositories.PasswordResetRepository
+	tokenService TokenService
+	mailer       mail.Sender
+	resetConfig  config.PasswordResetConfig
+	hasher       passwords.Hasher
+	messages     lang.Messages
+}
+
+// NewPasswordService создает новый экземпляр PasswordService
+func NewPasswordService(
+	userRepo repositories.UserRepository,
+	resetRepo repositories.PasswordResetRepository,
+	tokenService TokenService,
+	mailer mail.Sender,
+	resetConfig config.PasswordResetConfig,
+	hasher passwords.Hasher,
+	messages lang.Messages,
+) PasswordService {
+	return &passwordService{
+		userRepo:     userRepo,
+		resetRepo:    resetRepo,
+		tokenService: tokenService,
+		mailer:       mailer,
+		resetConfig:  resetConfig,
+		hasher:       hasher,
+		messages:     messages,
+	}
+}
+
+// ForgotPassword отправляет письмо со ссылкой на сброс пароля.
+// Для несуществующего email ошибка не возвращается, чтобы не раскрывать наличие аккаунта.
+// Учетной записи без локального пароля ссылка не отправляется: сброс дал бы ей пароль
+// в обход каталога или провайдера, где сотрудника могут заблокировать.
+func (s *passwordService) ForgotPassword(ctx context.Context, req *requests.ForgotPasswordRequest) error {
+	log.Printf(s.messages.Get(lang.LogPasswordResetRequested), req.Email)
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		log.Printf(s.messages.Get(lang.LogUserNotFoundLogin), req.Email)
+		return nil
+	}
+
+	if !user.HasLocalPassword() {
+		log.Printf(s.messages.Get(lang.LogPasswordResetExternal), user.Email)
+		return nil
+	}
+
+	// Ошибку отправки не возвращаем клиенту: ответ не должен зависеть от наличия аккаунта.
+	// Причина уже записана в лог репозиторием или отправителем писем
+	_ = s.sendResetLink(ctx, user)
+	return nil
+}
+
+// ForceReset сбрасывает пароль пользователя по решению администратора: старый пароль
+// заменяется случайным, все сессии завершаются, на email отправляется ссылка для установки нового.
+// У учетной записи без локального пароля только завершаются сессии.
+func (s *passwordService) ForceReset(ctx context.Context, user *models.User) error {
+	if !user.HasLocalPassword() {
+		log.Printf(s.messages.Get(lang.LogPasswordResetExternal), user.Email)
+		return s.tokenService.RevokeAllForUser(ctx, user.ID)
+	}
+
+	randomPassword, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(randomPassword)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogPasswordHashError), user.Email, err)
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.sendResetLink(ctx, user)
+}
+
+// ResetPassword устанавливает новый пароль по одноразовому токену
+// и завершает все сессии пользователя
+func (s *passwordService) ResetPassword(ctx context.Context, req *requests.ResetPasswordRequest) error {
+	record, err := s.resetRepo.GetByHash(ctx, hashOpaqueToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	if record == nil || !record.IsActive(time.Now()) {
+		log.Printf(s.messages.Get(lang.LogPasswordResetTokenInvalid))
+		return errors.New(s.messages.Get(lang.PasswordResetTokenInvalid))
+	}
+
+	user, err := s.userRepo.GetByID(ctx, record.UserID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), record.UserID.String(), err)
+		return err
+	}
+	if user == nil {
+		log.Printf(s.messages.Get(lang.LogPasswordResetTokenInvalid))
+		return errors.New(s.messages.Get(lang.PasswordResetTokenInvalid))
+	}
+
+	// Ссылка могла быть выдана до того, как учетная запись перешла на вход через каталог или SSO
+	if !user.HasLocalPassword() {
+		log.Printf(s.messages.Get(lang.LogPasswordResetExternal), user.Email)
+		return errors.New(s.messages.Get(lang.PasswordResetExternal))
+	}
+
+	// В запросе нет email, поэтому правило политики проверяется здесь. Токен не погашается:
+	// пользователь может сразу выбрать другой пароль
+	if passwords.MatchesEmail(req.Password, user.Email) {
+		return errors.New(s.messages.Get(lang.ValidationPasswordEmail))
+	}
+
+	// Токен мог быть использован параллельным запросом между чтением и обновлением
+	marked, err := s.resetRepo.MarkUsed(ctx, record.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		log.Printf(s.messages.Get(lang.LogPasswordResetTokenInvalid))
+		return errors.New(s.messages.Get(lang.PasswordResetTokenInvalid))
+	}
+
+	// Хешируем новый пароль
+	hashedPassword, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogPasswordHashError), record.UserID.String(), err)
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, record.UserID, hashedPassword); err != nil {
+		return err
+	}
+
+	// Остальные выданные ссылки больше не нужны
+	if err := s.resetRepo.InvalidateForUser(ctx, record.UserID); err != nil {
+		return err
+	}
+
+	// Старый пароль мог быть скомпрометирован - завершаем все сессии
+	if err := s.tokenService.RevokeAllForUser(ctx, record.UserID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogPasswordResetComplete), record.UserID.String())
+	return nil
+}
+
+// ChangePassword меняет пароль аутентифицированного пользователя
+// и завершает все ранее выданные сессии
+func (s *passwordService) ChangePassword(ctx context.Context, userID uuid.UUID, req *requests.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), userID.String(), err)
+		return err
+	}
+
+	if user == nil {
+		return errors.New(s.messages.Get(lang.UserNotFound))
+	}
+
+	// Проверяем текущий пароль
+	if ok, err := s.hasher.Verify(user.Password, req.CurrentPassword); err != nil || !ok {
+		log.Printf(s.messages.Get(lang.LogInvalidPassword), user.Email)
+		return errors.New(s.messages.Get(lang.CurrentPasswordInvalid))
+	}
+
+	// Хешируем новый пароль
+	hashedPassword, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogPasswordHashError), user.Email, err)
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	// Все токены, выданные до смены пароля, перестают действовать
+	if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogPasswordChangeComplete), user.Email)
+	return nil
+}
+
+// sendResetLink выпускает одноразовый токен и отправляет письмо со ссылкой на сброс пароля
+func (s *passwordService) sendResetLink(ctx context.Context, user *models.User) error {
+	resetToken, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	record := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashOpaqueToken(resetToken),
+		ExpiresAt: now.Add(s.resetConfig.TokenTTL),
+		Created:   now,
+	}
+
+	if err := s.resetRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: s.messages.Get(lang.PasswordResetEmailSubject),
+		Body:    s.messages.Get(lang.PasswordResetEmailBody, s.resetLink(resetToken), int(s.resetConfig.TokenTTL.Minutes())),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf(s.messages.Get(lang.LogMailSendError), user.Email, err)
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogPasswordResetSent), user.Email)
+	return nil
+}
+
+// resetLink формирует ссылку на страницу сброса пароля
+func (s *passwordService) resetLink(resetToken string) string {
+	link, err := url.Parse(s.resetConfig.URL)
+	if err != nil {
+		return s.resetConfig.URL + "?token=" + url.QueryEscape(resetToken)
+	}
+
+	query := link.Query()
+	query.Set("token", resetToken)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// RevocationStore интерфейс списка отозванных access токенов
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, jti, userID uuid.UUID, expiresAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	IsRevoked(ctx context.Context, claims *JWTClaims) (bool, error)
+}
+
+// cachedRevocationStore хранит отзывы в Postgres и держит их копию в памяти процесса.
+// Копия перечитывается из БД не чаще syncInterval, поэтому отзыв, сделанный
+// другим экземпляром сервиса, начинает действовать с задержкой не более syncInterval.
+type cachedRevocationStore struct {
+	repo         repositories.TokenRevocationRepository
+	syncInterval time.Duration
+	maxTokenAge  time.Duration
+	messages     lang.Messages
+
+	mu       sync.RWMutex
+	tokens   map[uuid.UUID]time.Time
+	users    map[uuid.UUID]time.Time
+	lastSync time.Time
+}
+
+// NewRevocationStore создает новый экземпляр RevocationStore
+func NewRevocationStore(repo repositories.TokenRevocationRepository, jwtConfig config.JWTConfig, messages lang.Messages) RevocationStore {
+	return &cachedRevocationStore{
+		repo:         repo,
+		syncInterval: jwtConfig.RevocationSyncInterval,
+		maxTokenAge:  jwtConfig.AccessTokenTTL,
+		messages:     messages,
+		tokens:       make(map[uuid.UUID]time.Time),
+		users:        make(map[uuid.UUID]time.Time),
+	}
+}
+
+// RevokeToken отзывает один access токен до истечения его срока действия
+func (s *cachedRevocationStore) RevokeToken(ctx context.Context, jti, userID uuid.UUID, expiresAt time.Time) error {
+	token := &models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		RevokedAt: time.Now(),
+	}
+
+	if err := s.repo.RevokeToken(ctx, token); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = expiresAt
+	s.mu.Unlock()
+
+	log.Printf(s.messages.Get(lang.LogTokenRevoked), jti.String(), userID.String())
+	return nil
+}
+
+// RevokeAllForUser отзывает все access токены пользователя, выпущенные до текущего момента
+func (s *cachedRevocationStore) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	// iat выпускается с точностью до секунды (jwt.NewNumericDate отбрасывает доли),
+	// поэтому момент отзыва сравнивается по тем же часам. Токен, выпущенный сразу
+	// после отзыва в ту же секунду, принимается. Токены сессий, выпущенные в эту секунду
+	// до отзыва, отклоняются проверкой сессии: RevokeAllForUser завершает их раньше
+	revokedBefore := time.Now().Truncate(time.Second)
+
+	if err := s.repo.RevokeAllForUser(ctx, userID, revokedBefore); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if current, ok := s.users[userID]; !ok || revokedBefore.After(current) {
+		s.users[userID] = revokedBefore
+	}
+	s.mu.Unlock()
+
+	log.Printf(s.messages.Get(lang.LogUserTokensRevoked), userID.String())
+	return nil
+}
+
+// IsRevoked проверяет, отозван ли токен по jti или отзывом всех токенов пользователя
+func (s *cachedRevocationStore) IsRevoked(ctx context.Context, claims *JWTClaims) (bool, error) {
+	if err := s.syncIfStale(ctx); err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if jti, err := uuid.Parse(claims.ID); err == nil {
+		if _, revoked := s.tokens[jti]; revoked {
+			return true, nil
+		}
+	}
+
+	if revokedBefore, ok := s.users[claims.UserID]; ok {
+		if claims.IssuedAt == nil || claims.IssuedAt.Time.Before(revokedBefore) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// syncIfStale перечитывает список отзывов из БД, если кеш устарел
+func (s *cachedRevocationStore) syncIfStale(ctx context.Context) error {
+	s.mu.RLock()
+	fresh := time.Since(s.lastSync) < s.syncInterval
+	s.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	now := time.Now()
+	tokens, err := s.repo.ListRevokedTokens(ctx, now)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogRevocationSyncError), err)
+		return err
+	}
+
+	// Отзывы старше времени жизни access токена уже ни на что не влияют
+	users, err := s.repo.ListUserRevocations(ctx, now.Add(-s.maxTokenAge))
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogRevocationSyncError), err)
+		return err
+	}
+
+	tokenMap := make(map[uuid.UUID]time.Time, len(tokens))
+	for _, token := range tokens {
+		tokenMap[token.JTI] = token.ExpiresAt
+	}
+
+	userMap := make(map[uuid.UUID]time.Time, len(users))
+	for _, revocation := range users {
+		userMap[revocation.UserID] = revocation.RevokedBefore
+	}
+
+	s.mu.Lock()
+	// Отзывы, сделанные этим процессом во время чтения из БД, не должны потеряться
+	for jti, expiresAt := range s.tokens {
+		if expiresAt.After(now) {
+			tokenMap[jti] = expiresAt
+		}
+	}
+	for userID, revokedBefore := range s.users {
+		if revokedBefore.Before(now.Add(-s.maxTokenAge)) {
+			continue
+		}
+		if current, ok := userMap[userID]; !ok || revokedBefore.After(current) {
+			userMap[userID] = revokedBefore
+		}
+	}
+	s.tokens = tokenMap
+	s.users = userMap
+	s.lastSync = now
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,911 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/avangero/auth-service/internal/scim"
+	"github.com/google/uuid"
+)
+
+// SCIMService интерфейс для синхронизации учетных записей и групп с кадровой системой по SCIM 2.0.
+// Ошибки запроса возвращаются как *scim.Error, остальные ошибки - внутренние.
+type SCIMService interface {
+	CreateUser(ctx context.Context, req *scim.User) (*scim.User, error)
+	GetUser(ctx context.Context, id string) (*scim.User, error)
+	ListUsers(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error)
+	ReplaceUser(ctx context.Context, id string, req *scim.User) (*scim.User, error)
+	PatchUser(ctx context.Context, id string, req *scim.PatchRequest) (*scim.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	CreateGroup(ctx context.Context, req *scim.Group) (*scim.Group, error)
+	GetGroup(ctx context.Context, id string, excludeMembers bool) (*scim.Group, error)
+	ListGroups(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error)
+	ReplaceGroup(ctx context.Context, id string, req *scim.Group) (*scim.Group, error)
+	PatchGroup(ctx context.Context, id string, req *scim.PatchRequest) (*scim.Group, error)
+	DeleteGroup(ctx context.Context, id string) error
+}
+
+// scimService реализация SCIMService
+type scimService struct {
+	userRepo     repositories.UserRepository
+	identityRepo repositories.UserIdentityRepository
+	groupRepo    repositories.GroupRepository
+	resetRepo    repositories.PasswordResetRepository
+	verifyRepo   repositories.EmailVerificationRepository
+	tokenService TokenService
+	messages     lang.Messages
+}
+
+// NewSCIMService создает новый экземпляр SCIMService
+func NewSCIMService(
+	userRepo repositories.UserRepository,
+	identityRepo repositories.UserIdentityRepository,
+	groupRepo repositories.GroupRepository,
+	resetRepo repositories.PasswordResetRepository,
+	verifyRepo repositories.EmailVerificationRepository,
+	tokenService TokenService,
+	messages lang.Messages,
+) SCIMService {
+	return &scimService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		groupRepo:    groupRepo,
+		resetRepo:    resetRepo,
+		verifyRepo:   verifyRepo,
+		tokenService: tokenService,
+		messages:     messages,
+	}
+}
+
+// userChanges изменения пользователя из PUT или PATCH; nil - атрибут не меняется
+type userChanges struct {
+	email      *string
+	externalID *string // пустая строка - отвязать externalId
+	active     *bool
+}
+
+// CreateUser создает сотрудника без локального пароля: входить он будет через SSO или каталог.
+// Email подтвержден кадровой системой.
+func (s *scimService) CreateUser(ctx context.Context, req *scim.User) (*scim.User, error) {
+	existing, err := s.userRepo.GetByEmail(ctx, req.UserName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, scim.NewError(http.StatusConflict, scim.ErrorUniqueness, s.messages.Get(lang.UserAlreadyExists))
+	}
+	if err := s.checkExternalID(ctx, req.ExternalID, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &models.User{
+		ID:              uuid.New(),
+		Email:           req.UserName,
+		Role:            models.RoleEmployee,
+		Created:         now,
+		EmailVerifiedAt: &now,
+		Status:          models.StatusActive,
+	}
+	// Кадровая система может сразу создать уволенного сотрудника
+	if req.Active != nil && !*req.Active {
+		reason := s.messages.Get(lang.SCIMDeactivationReason)
+		s.setStatus(user, models.StatusDeactivated, &reason)
+	}
+
+	var identity *models.UserIdentity
+	if req.ExternalID != "" {
+		identity = &models.UserIdentity{
+			Provider: models.IdentityProviderSCIM,
+			Subject:  req.ExternalID,
+			UserID:   user.ID,
+			Email:    user.Email,
+			Created:  now,
+		}
+	}
+
+	// Пользователь и externalId сохраняются вместе: иначе после сбоя на втором шаге осталась бы
+	// учетная запись, и повтор запроса кадровой системой получил бы 409
+	if err := s.userRepo.CreateWithIdentity(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	log.Printf(s.messages.Get(lang.LogSCIMUserProvisioned), user.Email)
+
+	return s.userResource(ctx, user)
+}
+
+// GetUser возвращает пользователя по id
+func (s *scimService) GetUser(ctx context.Context, id string) (*scim.User, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.userResource(ctx, user)
+}
+
+// ListUsers ищет пользователей. Без фильтра возвращается страница всех неудаленных пользователей.
+func (s *scimService) ListUsers(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error) {
+	if query.Filter == "" {
+		users, total, err := s.userRepo.List(ctx, models.UserFilter{Limit: query.Count, Offset: query.StartIndex - 1})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, 0, len(users))
+		for i := range users {
+			resource, err := s.userResource(ctx, &users[i])
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, resource)
+		}
+		return scim.NewListResponse(query, total, resources), nil
+	}
+
+	filter, err := scim.ParseFilter(query.Filter)
+	if err != nil {
+		return nil, s.invalidFilter()
+	}
+
+	var user *models.User
+	switch {
+	case filter.Is("userName"), filter.Is("emails.value"), filter.Is("emails"):
+		user, err = s.userRepo.GetByEmail(ctx, filter.Value)
+	case filter.Is("externalId"):
+		user, err = s.userByExternalID(ctx, filter.Value)
+	case filter.Is("id"):
+		if id, parseErr := uuid.Parse(filter.Value); parseErr == nil {
+			user, err = s.userRepo.GetByID(ctx, id)
+		}
+	default:
+		return nil, s.invalidFilter()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return scim.NewListResponse(query, 0, nil), nil
+	}
+
+	// Фильтр eq находит не больше одного пользователя, страница учитывается только для единообразия
+	if query.StartIndex > 1 || query.Count == 0 {
+		return scim.NewListResponse(query, 1, nil), nil
+	}
+	resource, err := s.userResource(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return scim.NewListResponse(query, 1, []interface{}{resource}), nil
+}
+
+// ReplaceUser заменяет userName, externalId и active пользователя
+func (s *scimService) ReplaceUser(ctx context.Context, id string, req *scim.User) (*scim.User, error) {
+	user, err := s.writableUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := userChanges{email: &req.UserName, externalID: &req.ExternalID, active: req.Active}
+	if err := s.applyUserChanges(ctx, user, changes); err != nil {
+		return nil, err
+	}
+
+	return s.userResource(ctx, user)
+}
+
+// PatchUser применяет операции PATCH. Атрибуты, которые портал не хранит, пропускаются.
+func (s *scimService) PatchUser(ctx context.Context, id string, req *scim.PatchRequest) (*scim.User, error) {
+	user, err := s.writableUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes userChanges
+	for _, operation := range req.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != scim.OpAdd && op != scim.OpReplace && op != scim.OpRemove {
+			return nil, s.invalidPatch()
+		}
+
+		// Без path значение - объект с атрибутами: так Okta передает {"active": false}
+		if operation.Path == "" {
+			var attributes map[string]json.RawMessage
+			if op == scim.OpRemove || json.Unmarshal(operation.Value, &attributes) != nil {
+				return nil, s.invalidPatch()
+			}
+			for attribute, value := range attributes {
+				if err := s.patchUserAttribute(&changes, attribute, op, value); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		path, err := scim.ParsePath(operation.Path)
+		if err != nil {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrorInvalidPath, s.messages.Get(lang.SCIMPatchInvalid))
+		}
+		if path.Filter != nil || path.SubAttribute != "" {
+			continue // emails[type eq "work"].value, name.givenName и подобные не хранятся
+		}
+		if err := s.patchUserAttribute(&changes, path.Attribute, op, operation.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.applyUserChanges(ctx, user, changes); err != nil {
+		return nil, err
+	}
+
+	return s.userResource(ctx, user)
+}
+
+// patchUserAttribute переносит значение атрибута из операции PATCH в изменения пользователя
+func (s *scimService) patchUserAttribute(changes *userChanges, attribute, op string, value json.RawMessage) error {
+	switch strings.ToLower(attribute) {
+	case "username":
+		email, ok := scim.ParseString(value)
+		if op == scim.OpRemove || !ok {
+			return s.invalidValue()
+		}
+		changes.email = &email
+	case "externalid":
+		externalID := ""
+		if op != scim.OpRemove {
+			var ok bool
+			if externalID, ok = scim.ParseString(value); !ok {
+				return s.invalidValue()
+			}
+		}
+		changes.externalID = &externalID
+	case "active":
+		if op == scim.OpRemove {
+			return nil
+		}
+		active, ok := scim.ParseBool(value)
+		if !ok {
+			return s.invalidValue()
+		}
+		changes.active = &active
+	}
+	return nil
+}
+
+// DeleteUser удаляет учетную запись так же, как администратор: токены отзываются,
+// персональные данные позже стирает фоновая очистка
+func (s *scimService) DeleteUser(ctx context.Context, id string) error {
+	user, err := s.writableUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	// externalId освобождается, чтобы кадровая система могла создать сотрудника заново
+	if err := s.identityRepo.DeleteByUser(ctx, models.IdentityProviderSCIM, user.ID); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMUserDeleted), user.Email)
+	return nil
+}
+
+// applyUserChanges сохраняет изменения пользователя. Деактивация сразу завершает все сессии.
+// Приостановка учетной записи остается решением администратора портала и через SCIM не меняется.
+func (s *scimService) applyUserChanges(ctx context.Context, user *models.User, changes userChanges) error {
+	if changes.email != nil && *changes.email != user.Email {
+		if err := s.changeEmail(ctx, user, *changes.email); err != nil {
+			return err
+		}
+	}
+
+	if changes.externalID != nil {
+		if err := s.changeExternalID(ctx, user, *changes.externalID); err != nil {
+			return err
+		}
+	}
+
+	if changes.active != nil {
+		return s.changeActive(ctx, user, *changes.active)
+	}
+	return nil
+}
+
+// changeEmail меняет email после проверки формата и уникальности. Новый адрес становится
+// неподтвержденным, сессии завершаются, а ссылки сброса пароля и подтверждения email,
+// отправленные на прежний адрес, погашаются: ящик мог остаться у другого человека
+func (s *scimService) changeEmail(ctx context.Context, user *models.User, email string) error {
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return s.invalidValue()
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return scim.NewError(http.StatusConflict, scim.ErrorUniqueness, s.messages.Get(lang.UserAlreadyExists))
+	}
+
+	if err := s.userRepo.UpdateEmail(ctx, user.ID, email); err != nil {
+		return err
+	}
+	if err := s.resetRepo.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.verifyRepo.InvalidateForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMUserUpdated), email)
+	user.Email = email
+	user.EmailVerifiedAt = nil
+	return nil
+}
+
+// changeExternalID привязывает пользователя к новому externalId или отвязывает при пустом значении
+func (s *scimService) changeExternalID(ctx context.Context, user *models.User, externalID string) error {
+	current, err := s.identityRepo.GetByUser(ctx, models.IdentityProviderSCIM, user.ID)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.Subject == externalID {
+		return nil
+	}
+	if current == nil && externalID == "" {
+		return nil
+	}
+
+	if err := s.checkExternalID(ctx, externalID, user.ID); err != nil {
+		return err
+	}
+	if err := s.identityRepo.DeleteByUser(ctx, models.IdentityProviderSCIM, user.ID); err != nil {
+		return err
+	}
+	if externalID != "" {
+		identity := &models.UserIdentity{
+			Provider: models.IdentityProviderSCIM,
+			Subject:  externalID,
+			UserID:   user.ID,
+			Email:    user.Email,
+			Created:  time.Now(),
+		}
+		if err := s.identityRepo.Save(ctx, identity); err != nil {
+			return err
+		}
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMUserUpdated), user.Email)
+	return nil
+}
+
+// changeActive деактивирует уволенного сотрудника или возвращает доступ вновь принятому
+func (s *scimService) changeActive(ctx context.Context, user *models.User, active bool) error {
+	switch {
+	case !active && user.Status != models.StatusDeactivated:
+		reason := s.messages.Get(lang.SCIMDeactivationReason)
+		if err := s.userRepo.UpdateStatus(ctx, user.ID, models.StatusDeactivated, &reason); err != nil {
+			return err
+		}
+		if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		log.Printf(s.messages.Get(lang.LogSCIMUserDeactivated), user.Email)
+		s.setStatus(user, models.StatusDeactivated, &reason)
+	case active && user.Status == models.StatusDeactivated:
+		if err := s.userRepo.UpdateStatus(ctx, user.ID, models.StatusActive, nil); err != nil {
+			return err
+		}
+		log.Printf(s.messages.Get(lang.LogSCIMUserReactivated), user.Email)
+		s.setStatus(user, models.StatusActive, nil)
+	}
+	return nil
+}
+
+// setStatus отражает смену статуса в уже загруженном пользователе
+func (s *scimService) setStatus(user *models.User, status string, reason *string) {
+	now := time.Now()
+	user.Status = status
+	user.StatusReason = reason
+	user.StatusChangedAt = &now
+}
+
+// checkExternalID проверяет, что externalId не привязан к другому пользователю
+func (s *scimService) checkExternalID(ctx context.Context, externalID string, userID uuid.UUID) error {
+	if externalID == "" {
+		return nil
+	}
+
+	identity, err := s.identityRepo.Get(ctx, models.IdentityProviderSCIM, externalID)
+	if err != nil {
+		return err
+	}
+	if identity != nil && identity.UserID != userID {
+		return scim.NewError(http.StatusConflict, scim.ErrorUniqueness, s.messages.Get(lang.UserAlreadyExists))
+	}
+	return nil
+}
+
+// findUser находит пользователя по id из адреса ресурса
+func (s *scimService) findUser(ctx context.Context, id string) (*models.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, scim.NotFound(s.messages.Get(lang.UserNotFound))
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, scim.NotFound(s.messages.Get(lang.UserNotFound))
+	}
+	return user, nil
+}
+
+// writableUser находит пользователя, которого кадровая система может изменить или удалить.
+// Администраторы назначаются только вручную, и клиент с scim:provision не должен
+// менять их email, деактивировать или удалять: так портал можно было бы лишить администраторов
+// или перехватить их учетные записи.
+func (s *scimService) writableUser(ctx context.Context, id string) (*models.User, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == models.RoleAdmin {
+		log.Printf(s.messages.Get(lang.LogSCIMAdminForbidden), user.Email)
+		return nil, scim.NewError(http.StatusForbidden, "", s.messages.Get(lang.SCIMAdminForbidden))
+	}
+	return user, nil
+}
+
+// userByExternalID находит пользователя по externalId кадровой системы
+func (s *scimService) userByExternalID(ctx context.Context, externalID string) (*models.User, error) {
+	identity, err := s.identityRepo.Get(ctx, models.IdentityProviderSCIM, externalID)
+	if err != nil || identity == nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(ctx, identity.UserID)
+}
+
+// userResource формирует ресурс пользователя. active ложно только у деактивированных:
+// приостановка - внутреннее решение портала, и кадровая система не должна ее отменять.
+func (s *scimService) userResource(ctx context.Context, user *models.User) (*scim.User, error) {
+	identity, err := s.identityRepo.GetByUser(ctx, models.IdentityProviderSCIM, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := user.Status != models.StatusDeactivated
+	resource := &scim.User{
+		Schemas:  []string{scim.SchemaUser},
+		ID:       user.ID.String(),
+		UserName: user.Email,
+		Emails:   []scim.Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:   &active,
+		Meta: &scim.Meta{
+			ResourceType: scim.ResourceTypeUser,
+			Created:      user.Created,
+			LastModified: user.Created,
+		},
+	}
+	if identity != nil {
+		resource.ExternalID = identity.Subject
+	}
+	if user.StatusChangedAt != nil {
+		resource.Meta.LastModified = *user.StatusChangedAt
+	}
+	return resource, nil
+}
+
+// CreateGroup создает группу с участниками
+func (s *scimService) CreateGroup(ctx context.Context, req *scim.Group) (*scim.Group, error) {
+	if err := s.checkGroupUnique(ctx, req.DisplayName, req.ExternalID, uuid.Nil); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, req.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	group := &models.Group{
+		ID:          uuid.New(),
+		DisplayName: req.DisplayName,
+		ExternalID:  optionalString(req.ExternalID),
+		Created:     now,
+		Updated:     now,
+	}
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	if len(memberIDs) > 0 {
+		if err := s.groupRepo.AddMembers(ctx, group.ID, memberIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMGroupCreated), group.DisplayName)
+	return s.groupResource(ctx, group, false)
+}
+
+// GetGroup возвращает группу по id
+func (s *scimService) GetGroup(ctx context.Context, id string, excludeMembers bool) (*scim.Group, error) {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupResource(ctx, group, excludeMembers)
+}
+
+// ListGroups ищет группы по displayName, externalId или id
+func (s *scimService) ListGroups(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error) {
+	groupFilter := models.GroupFilter{Limit: query.Count, Offset: query.StartIndex - 1}
+
+	if query.Filter != "" {
+		filter, err := scim.ParseFilter(query.Filter)
+		if err != nil {
+			return nil, s.invalidFilter()
+		}
+
+		switch {
+		case filter.Is("displayName"):
+			groupFilter.DisplayName = filter.Value
+		case filter.Is("externalId"):
+			groupFilter.ExternalID = filter.Value
+		case filter.Is("id"):
+			return s.groupByID(ctx, query, filter.Value)
+		default:
+			return nil, s.invalidFilter()
+		}
+	}
+
+	groups, total, err := s.groupRepo.List(ctx, groupFilter)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupList(ctx, query, total, groups)
+}
+
+// groupByID ищет группу фильтром id eq
+func (s *scimService) groupByID(ctx context.Context, query scim.ListQuery, id string) (*scim.ListResponse, error) {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return scim.NewListResponse(query, 0, nil), nil
+	}
+
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return scim.NewListResponse(query, 0, nil), nil
+	}
+	if query.StartIndex > 1 || query.Count == 0 {
+		return scim.NewListResponse(query, 1, nil), nil
+	}
+	return s.groupList(ctx, query, 1, []models.Group{*group})
+}
+
+// groupList формирует страницу групп
+func (s *scimService) groupList(ctx context.Context, query scim.ListQuery, total int, groups []models.Group) (*scim.ListResponse, error) {
+	resources := make([]interface{}, 0, len(groups))
+	for i := range groups {
+		resource, err := s.groupResource(ctx, &groups[i], query.ExcludeMembers)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return scim.NewListResponse(query, total, resources), nil
+}
+
+// ReplaceGroup заменяет название, externalId и весь состав группы
+func (s *scimService) ReplaceGroup(ctx context.Context, id string, req *scim.Group) (*scim.Group, error) {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGroupUnique(ctx, req.DisplayName, req.ExternalID, group.ID); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, req.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	group.DisplayName = req.DisplayName
+	group.ExternalID = optionalString(req.ExternalID)
+	group.Updated = time.Now()
+	changes := []models.GroupMembersChange{{Op: models.GroupMembersReplace, UserIDs: memberIDs}}
+	if err := s.groupRepo.Update(ctx, group, changes); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMGroupUpdated), group.DisplayName)
+	return s.groupResource(ctx, group, false)
+}
+
+// PatchGroup применяет операции PATCH к названию и составу группы. Кадровые системы
+// меняют состав операциями add и remove, не передавая весь список участников.
+// Сначала проверяются все операции, затем изменения сохраняются одной транзакцией
+// (RFC 7644, раздел 3.5.2: PATCH применяется целиком или не применяется вовсе).
+func (s *scimService) PatchGroup(ctx context.Context, id string, req *scim.PatchRequest) (*scim.Group, error) {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := *group
+	var changes []models.GroupMembersChange
+	for _, operation := range req.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != scim.OpAdd && op != scim.OpReplace && op != scim.OpRemove {
+			return nil, s.invalidPatch()
+		}
+
+		// Без path значение - объект с атрибутами: так Azure AD переименовывает группу
+		if operation.Path == "" {
+			var attributes map[string]json.RawMessage
+			if op == scim.OpRemove || json.Unmarshal(operation.Value, &attributes) != nil {
+				return nil, s.invalidPatch()
+			}
+			for attribute, value := range attributes {
+				if err := s.patchGroupAttribute(ctx, &renamed, &changes, &scim.Path{Attribute: attribute}, op, value); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		path, err := scim.ParsePath(operation.Path)
+		if err != nil {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrorInvalidPath, s.messages.Get(lang.SCIMPatchInvalid))
+		}
+		if err := s.patchGroupAttribute(ctx, &renamed, &changes, path, op, operation.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	nameChanged := renamed.DisplayName != group.DisplayName || !equalOptional(renamed.ExternalID, group.ExternalID)
+	if nameChanged {
+		if err := s.checkGroupUnique(ctx, renamed.DisplayName, derefString(renamed.ExternalID), group.ID); err != nil {
+			return nil, err
+		}
+	}
+	if nameChanged || len(changes) > 0 {
+		renamed.Updated = time.Now()
+		if err := s.groupRepo.Update(ctx, &renamed, changes); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMGroupUpdated), renamed.DisplayName)
+	return s.groupResource(ctx, &renamed, false)
+}
+
+// patchGroupAttribute проверяет одну операцию PATCH. Название и externalId копятся в group,
+// изменения состава - в changes; все сохраняется после проверки всех операций.
+func (s *scimService) patchGroupAttribute(ctx context.Context, group *models.Group, changes *[]models.GroupMembersChange, path *scim.Path, op string, value json.RawMessage) error {
+	switch {
+	case path.Is("displayName"):
+		displayName, ok := scim.ParseString(value)
+		if op == scim.OpRemove || !ok || strings.TrimSpace(displayName) == "" {
+			return s.invalidValue()
+		}
+		group.DisplayName = displayName
+	case path.Is("externalId"):
+		if op == scim.OpRemove {
+			group.ExternalID = nil
+			return nil
+		}
+		externalID, ok := scim.ParseString(value)
+		if !ok {
+			return s.invalidValue()
+		}
+		group.ExternalID = optionalString(externalID)
+	case path.Is("members"):
+		change, err := s.patchMembers(ctx, path, op, value)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			*changes = append(*changes, *change)
+		}
+	}
+	return nil
+}
+
+// patchMembers разбирает изменение состава группы. remove с путем members[value eq "id"]
+// исключает одного участника, remove без значения очищает группу. nil - состав не меняется.
+func (s *scimService) patchMembers(ctx context.Context, path *scim.Path, op string, value json.RawMessage) (*models.GroupMembersChange, error) {
+	if op == scim.OpRemove {
+		var memberIDs []uuid.UUID
+		switch {
+		case path.Filter != nil && path.Filter.Is("value"):
+			memberID, err := uuid.Parse(path.Filter.Value)
+			if err != nil {
+				return nil, nil // такого участника в группе быть не может
+			}
+			memberIDs = []uuid.UUID{memberID}
+		case path.Filter != nil:
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrorInvalidPath, s.messages.Get(lang.SCIMPatchInvalid))
+		case len(value) == 0 || string(value) == "null":
+			return &models.GroupMembersChange{Op: models.GroupMembersReplace}, nil
+		default:
+			members, ok := scim.ParseMembers(value)
+			if !ok {
+				return nil, s.invalidValue()
+			}
+			for _, member := range members {
+				if memberID, err := uuid.Parse(member.Value); err == nil {
+					memberIDs = append(memberIDs, memberID)
+				}
+			}
+		}
+		return &models.GroupMembersChange{Op: models.GroupMembersRemove, UserIDs: memberIDs}, nil
+	}
+
+	members, ok := scim.ParseMembers(value)
+	if !ok || path.Filter != nil {
+		return nil, s.invalidValue()
+	}
+	memberIDs, err := s.memberIDs(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	if op == scim.OpReplace {
+		return &models.GroupMembersChange{Op: models.GroupMembersReplace, UserIDs: memberIDs}, nil
+	}
+	return &models.GroupMembersChange{Op: models.GroupMembersAdd, UserIDs: memberIDs}, nil
+}
+
+// DeleteGroup удаляет группу. Учетные записи участников не меняются.
+func (s *scimService) DeleteGroup(ctx context.Context, id string) error {
+	group, err := s.findGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.groupRepo.Delete(ctx, group.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSCIMGroupDeleted), group.DisplayName)
+	return nil
+}
+
+// checkGroupUnique проверяет, что название и externalId не заняты другой группой
+func (s *scimService) checkGroupUnique(ctx context.Context, displayName, externalID string, groupID uuid.UUID) error {
+	filters := []models.GroupFilter{{DisplayName: displayName, Limit: 1}}
+	if externalID != "" {
+		filters = append(filters, models.GroupFilter{ExternalID: externalID, Limit: 1})
+	}
+
+	for _, filter := range filters {
+		groups, _, err := s.groupRepo.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if len(groups) > 0 && groups[0].ID != groupID {
+			return scim.NewError(http.StatusConflict, scim.ErrorUniqueness, s.messages.Get(lang.SCIMGroupExists))
+		}
+	}
+	return nil
+}
+
+// memberIDs проверяет, что участники - существующие пользователи
+func (s *scimService) memberIDs(ctx context.Context, members []scim.Member) ([]uuid.UUID, error) {
+	memberIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		memberID, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, s.messages.Get(lang.SCIMMemberNotFound))
+		}
+
+		user, err := s.userRepo.GetByID(ctx, memberID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, s.messages.Get(lang.SCIMMemberNotFound))
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+	return memberIDs, nil
+}
+
+// findGroup находит группу по id из адреса ресурса
+func (s *scimService) findGroup(ctx context.Context, id string) (*models.Group, error) {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, scim.NotFound(s.messages.Get(lang.SCIMGroupNotFound))
+	}
+
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, scim.NotFound(s.messages.Get(lang.SCIMGroupNotFound))
+	}
+	return group, nil
+}
+
+// groupResource формирует ресурс группы с участниками
+func (s *scimService) groupResource(ctx context.Context, group *models.Group, excludeMembers bool) (*scim.Group, error) {
+	resource := &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          group.ID.String(),
+		ExternalID:  derefString(group.ExternalID),
+		DisplayName: group.DisplayName,
+		Meta: &scim.Meta{
+			ResourceType: scim.ResourceTypeGroup,
+			Created:      group.Created,
+			LastModified: group.Updated,
+		},
+	}
+	if excludeMembers {
+		return resource, nil
+	}
+
+	members, err := s.groupRepo.ListMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		resource.Members = append(resource.Members, scim.Member{Value: member.UserID.String(), Display: member.Email})
+	}
+	return resource, nil
+}
+
+// invalidFilter ошибка неподдерживаемого фильтра
+func (s *scimService) invalidFilter() error {
+	return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidFilter, s.messages.Get(lang.SCIMFilterInvalid))
+}
+
+// invalidPatch ошибка некорректной операции PATCH
+func (s *scimService) invalidPatch() error {
+	return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidSyntax, s.messages.Get(lang.SCIMPatchInvalid))
+}
+
+// invalidValue ошибка некорректного значения атрибута
+func (s *scimService) invalidValue() error {
+	return scim.NewError(http.StatusBadRequest, scim.ErrorInvalidValue, s.messages.Get(lang.SCIMValueInvalid))
+}
+
+// optionalString превращает пустую строку в nil
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// derefString возвращает значение или пустую строку для nil
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// equalOptional сравнивает необязательные строки
+func equalOptional(a, b *string) bool {
+	return derefString(a) == derefString(b)
+}
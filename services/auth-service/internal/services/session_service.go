@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// SessionService интерфейс для управления сессиями пользователя на его устройствах
+type SessionService interface {
+	List(ctx context.Context, tokenString string) (*responses.SessionListResponse, error)
+	Revoke(ctx context.Context, tokenString string, sessionID uuid.UUID) error
+}
+
+// sessionService реализация SessionService
+type sessionService struct {
+	sessionRepo  repositories.SessionRepository
+	tokenService TokenService
+	messages     lang.Messages
+}
+
+// NewSessionService создает новый экземпляр SessionService
+func NewSessionService(sessionRepo repositories.SessionRepository, tokenService TokenService, messages lang.Messages) SessionService {
+	return &sessionService{
+		sessionRepo:  sessionRepo,
+		tokenService: tokenService,
+		messages:     messages,
+	}
+}
+
+// List возвращает активные сессии владельца токена и отмечает текущую
+func (s *sessionService) List(ctx context.Context, tokenString string) (*responses.SessionListResponse, error) {
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, claims.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &responses.SessionListResponse{Sessions: []responses.SessionResponse{}}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, responses.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.Created,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == claims.SessionID,
+		})
+	}
+
+	return response, nil
+}
+
+// Revoke завершает сессию владельца токена: отзывает ее refresh токены,
+// а выданные в ней access токены перестают приниматься сразу.
+// Чужая или уже завершенная сессия считается ненайденной, чтобы не раскрывать ее существование.
+func (s *sessionService) Revoke(ctx context.Context, tokenString string, sessionID uuid.UUID) error {
+	claims, err := s.tokenService.ParseAccessToken(ctx, tokenString)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserID != claims.UserID || !session.IsActive(time.Now()) {
+		return errors.New(s.messages.Get(lang.SessionNotFound))
+	}
+
+	if err := s.tokenService.RevokeFamily(ctx, session.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSessionRevoked), session.ID.String(), claims.Email)
+	return nil
+}
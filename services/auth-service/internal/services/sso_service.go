@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/avangero/auth-service/internal/sso"
+	"github.com/google/uuid"
+)
+
+// SSOService интерфейс входа через внешние провайдеры удостоверений (корпоративный SSO)
+type SSOService interface {
+	Providers() *responses.SSOProvidersResponse
+	Begin(ctx context.Context, provider string) (string, string, error)
+	Complete(ctx context.Context, provider string, req *requests.SSOCallbackRequest, client models.ClientInfo) (*responses.TokenResponse, error)
+}
+
+// ssoService реализация SSOService
+type ssoService struct {
+	providers    map[string]sso.Provider
+	names        []string
+	stateRepo    repositories.SSOStateRepository
+	identityRepo repositories.UserIdentityRepository
+	userRepo     repositories.UserRepository
+	authService  AuthService
+	authConfig   config.AuthConfig
+	ssoConfig    config.SSOConfig
+	messages     lang.Messages
+}
+
+// NewSSOService создает новый экземпляр SSOService
+func NewSSOService(providers []sso.Provider, stateRepo repositories.SSOStateRepository, identityRepo repositories.UserIdentityRepository, userRepo repositories.UserRepository, authService AuthService, authConfig config.AuthConfig, ssoConfig config.SSOConfig, messages lang.Messages) SSOService {
+	service := &ssoService{
+		providers:    make(map[string]sso.Provider),
+		names:        []string{},
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		authConfig:   authConfig,
+		ssoConfig:    ssoConfig,
+		messages:     messages,
+	}
+	for _, provider := range providers {
+		service.providers[provider.Name()] = provider
+		service.names = append(service.names, provider.Name())
+	}
+	return service
+}
+
+// Providers возвращает имена подключенных провайдеров
+func (s *ssoService) Providers() *responses.SSOProvidersResponse {
+	return &responses.SSOProvidersResponse{Providers: s.names}
+}
+
+// Begin начинает вход через провайдер и возвращает адрес, на который перенаправляется пользователь,
+// и хеш state для cookie браузера. state, nonce и code_verifier остаются в БД и проверяются при возврате.
+func (s *ssoService) Begin(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", errors.New(s.messages.Get(lang.SSOProviderNotFound))
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := newOpaqueToken()
+		if err != nil {
+			return "", "", err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	now := time.Now()
+	record := &models.SSOState{
+		StateHash:    hashOpaqueToken(state),
+		Provider:     provider.Name(),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(s.ssoConfig.StateTTL),
+		Created:      now,
+	}
+	if err := s.stateRepo.Create(ctx, record); err != nil {
+		return "", "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, record.StateHash, nil
+}
+
+// Complete завершает вход: проверяет state, обменивает код у провайдера, находит, привязывает
+// или создает пользователя и выдает обычные токены сервиса
+func (s *ssoService) Complete(ctx context.Context, providerName string, req *requests.SSOCallbackRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, errors.New(s.messages.Get(lang.SSOProviderNotFound))
+	}
+
+	// Возврат принимается только в браузере, который начал вход. Иначе ссылку с кодом
+	// и state злоумышленника можно подсунуть жертве и войти ей под чужой учетной записью
+	stateHash := hashOpaqueToken(req.State)
+	if subtle.ConstantTimeCompare([]byte(stateHash), []byte(req.BrowserState)) != 1 {
+		log.Printf(s.messages.Get(lang.LogSSOBrowserMismatch), providerName)
+		return nil, errors.New(s.messages.Get(lang.SSOStateInvalid))
+	}
+
+	// state одноразовый: повторный возврат с тем же state отклоняется
+	state, err := s.stateRepo.Consume(ctx, stateHash)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || state.Provider != provider.Name() || !time.Now().Before(state.ExpiresAt) {
+		log.Printf(s.messages.Get(lang.LogSSOStateInvalid), providerName)
+		return nil, errors.New(s.messages.Get(lang.SSOStateInvalid))
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogSSOExchangeFailed), providerName, err)
+		return nil, errors.New(s.messages.Get(lang.SSOLoginFailed))
+	}
+
+	user, err := s.resolveUser(ctx, provider.Name(), identity)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.authService.LoginExternal(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSSOLoginComplete), providerName, user.Email)
+	return response, nil
+}
+
+// resolveUser находит пользователя по привязанной учетной записи провайдера. При первом входе
+// учетная запись привязывается к пользователю с тем же подтвержденным email, а если его нет -
+// создается сотрудник (SSO_AUTO_PROVISION), если email разрешен правилами регистрации.
+func (s *ssoService) resolveUser(ctx context.Context, providerName string, identity *sso.Identity) (*models.User, error) {
+	now := time.Now()
+	link := &models.UserIdentity{
+		Provider:    providerName,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		Created:     now,
+		LastLoginAt: &now,
+	}
+
+	linked, err := s.identityRepo.Get(ctx, providerName, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil {
+		user, err := s.userRepo.GetByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		// Привязка к удаленной учетной записи не действует, вход идет как первый
+		if user != nil {
+			link.UserID, link.Created = user.ID, linked.Created
+			return user, s.identityRepo.Save(ctx, link)
+		}
+	}
+
+	// По email привязывается только адрес, подтвержденный провайдером
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errors.New(s.messages.Get(lang.SSOEmailNotVerified))
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		if !s.ssoConfig.AutoProvision {
+			log.Printf(s.messages.Get(lang.LogSSOAccountNotFound), providerName, identity.Email)
+			return nil, errors.New(s.messages.Get(lang.SSOAccountNotFound))
+		}
+		// Иначе учетная запись у провайдера обходила бы режим invite_only и списки доменов
+		if err := checkRegistrationAllowed(s.authConfig, identity.Email, s.messages); err != nil {
+			return nil, err
+		}
+		if user, err = s.provisionUser(ctx, providerName, identity.Email, now); err != nil {
+			return nil, err
+		}
+	} else if !user.IsEmailVerified() {
+		if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	link.UserID = user.ID
+	if err := s.identityRepo.Save(ctx, link); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSSOIdentityLinked), providerName, user.Email)
+	return user, nil
+}
+
+// provisionUser создает сотрудника без локального пароля: входит он только через провайдер,
+// сброс пароля для такой учетной записи недоступен
+func (s *ssoService) provisionUser(ctx context.Context, providerName, email string, now time.Time) (*models.User, error) {
+	user := &models.User{
+		ID:              uuid.New(),
+		Email:           email,
+		Role:            models.RoleEmployee,
+		Created:         now,
+		EmailVerifiedAt: &now,
+		Status:          models.StatusActive,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		log.Printf(s.messages.Get(lang.LogUserCreateError), email, err)
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSSOUserProvisioned), email, providerName)
+	return user, nil
+}
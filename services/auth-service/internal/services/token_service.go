@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// opaqueTokenBytes длина случайной части непрозрачных токенов (refresh, сброс пароля)
+const opaqueTokenBytes = 32
+
+// maxUserAgentLength длина User-Agent, сохраняемая в сессии
+const maxUserAgentLength = 512
+
+// TokenService интерфейс для выпуска и проверки access и refresh токенов
+type TokenService interface {
+	GenerateAccessToken(user *models.User, familyID uuid.UUID) (string, error)
+	GenerateClientToken(client *models.APIClient, scopes []models.Permission) (string, error)
+	GenerateDelegatedToken(user *models.User, client *models.APIClient, sessionID uuid.UUID, scope string) (string, error)
+	ParseAccessToken(ctx context.Context, tokenString string) (*JWTClaims, error)
+	IssueRefreshToken(ctx context.Context, userID, familyID uuid.UUID) (string, error)
+	ConsumeRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshToken, error)
+	StartSession(ctx context.Context, userID uuid.UUID, client models.ClientInfo) (uuid.UUID, error)
+	TouchSession(ctx context.Context, sessionID uuid.UUID, client models.ClientInfo) error
+	RevokeSession(ctx context.Context, claims *JWTClaims) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	AccessTokenTTL() time.Duration
+}
+
+// JWTClaims представляет данные в JWT токене
+type JWTClaims struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	SessionID uuid.UUID `json:"sid"`                 // сессия и цепочка refresh токенов, к которой относится токен
+	ClientID  string    `json:"client_id,omitempty"` // API клиент; у токенов пользователей портала пусто
+	Scope     string    `json:"scope,omitempty"`     // права API клиента через пробел
+	jwt.RegisteredClaims
+}
+
+// IsClient проверяет, выпущен ли токен API клиенту (ему самому или от имени пользователя
+// через OpenID Connect), а не пользователю портала
+func (c *JWTClaims) IsClient() bool {
+	return c.ClientID != ""
+}
+
+// IsClientToken определяет по claims без проверки подписи, кому выпущен токен.
+// Нужен только для выбора способа проверки: подпись проверяется после.
+func IsClientToken(tokenString string) bool {
+	claims := &JWTClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return false
+	}
+	return claims.IsClient()
+}
+
+// tokenService реализация TokenService
+type tokenService struct {
+	refreshRepo repositories.RefreshTokenRepository
+	sessionRepo repositories.SessionRepository
+	revocations RevocationStore
+	keySet      *keys.KeySet
+	jwtConfig   config.JWTConfig
+	messages    lang.Messages
+}
+
+// NewTokenService создает новый экземпляр TokenService
+func NewTokenService(refreshRepo repositories.RefreshTokenRepository, sessionRepo repositories.SessionRepository, revocations RevocationStore, keySet *keys.KeySet, jwtConfig config.JWTConfig, messages lang.Messages) TokenService {
+	return &tokenService{
+		refreshRepo: refreshRepo,
+		sessionRepo: sessionRepo,
+		revocations: revocations,
+		keySet:      keySet,
+		jwtConfig:   jwtConfig,
+		messages:    messages,
+	}
+}
+
+// GenerateAccessToken генерирует короткоживущий JWT токен доступа с уникальным jti
+func (s *tokenService) GenerateAccessToken(user *models.User, familyID uuid.UUID) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: familyID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.jwtConfig.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   user.ID.String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(s.keySet.SigningMethod(), claims)
+	return s.keySet.Sign(token)
+}
+
+// GenerateClientToken генерирует JWT токен API клиента с выданными правами.
+// Refresh токен клиенту не нужен: по истечении он снова запрашивает токен.
+func (s *tokenService) GenerateClientToken(client *models.APIClient, scopes []models.Permission) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		ClientID: client.ID.String(),
+		Scope:    models.FormatScope(scopes),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.jwtConfig.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   client.ID.String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(s.keySet.SigningMethod(), claims)
+	return s.keySet.Sign(token)
+}
+
+// GenerateDelegatedToken генерирует access токен внешнего сервиса, в который пользователь вошел
+// через OpenID Connect. Токен привязан к сессии портала и принимается только userinfo.
+func (s *tokenService) GenerateDelegatedToken(user *models.User, client *models.APIClient, sessionID uuid.UUID, scope string) (string, error) {
+	now := time.Now()
+	claims := JWTClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
+		ClientID:  client.ID.String(),
+		Scope:     scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.jwtConfig.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   user.ID.String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(s.keySet.SigningMethod(), claims)
+	return s.keySet.Sign(token)
+}
+
+// ParseAccessToken проверяет подпись, срок действия и отзыв JWT токена и возвращает его claims
+func (s *tokenService) ParseAccessToken(ctx context.Context, tokenString string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, s.keySet.Keyfunc, jwt.WithValidMethods(s.keySet.ValidMethods()))
+
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogJWTParseError), err)
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		log.Printf(s.messages.Get(lang.LogJWTInvalid))
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+
+	// Без jti токен невозможно отозвать, поэтому такие токены не принимаем
+	if _, err := uuid.Parse(claims.ID); err != nil {
+		log.Printf(s.messages.Get(lang.LogJWTInvalid))
+		return nil, errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+
+	revoked, err := s.revocations.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		log.Printf(s.messages.Get(lang.LogJWTRevoked), claims.ID)
+		return nil, errors.New(s.messages.Get(lang.TokenRevoked))
+	}
+
+	// Завершенная сессия отзывает все свои access токены сразу, не дожидаясь их истечения
+	if claims.SessionID != uuid.Nil {
+		session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil || session.RevokedAt != nil {
+			log.Printf(s.messages.Get(lang.LogSessionRevokedToken), claims.SessionID.String(), claims.ID)
+			return nil, errors.New(s.messages.Get(lang.TokenRevoked))
+		}
+	}
+
+	return claims, nil
+}
+
+// IssueRefreshToken выпускает новый непрозрачный refresh токен в указанной цепочке
+func (s *tokenService) IssueRefreshToken(ctx context.Context, userID, familyID uuid.UUID) (string, error) {
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogRefreshTokenIssueError), userID.String(), err)
+		return "", err
+	}
+
+	now := time.Now()
+	record := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashOpaqueToken(refreshToken),
+		ExpiresAt: now.Add(s.jwtConfig.RefreshTokenTTL),
+		Created:   now,
+	}
+
+	if err := s.refreshRepo.Create(ctx, record); err != nil {
+		log.Printf(s.messages.Get(lang.LogRefreshTokenIssueError), userID.String(), err)
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// ConsumeRefreshToken проверяет refresh токен и помечает его использованным.
+// Повторное предъявление уже использованного токена считается кражей:
+// вся цепочка отзывается, и владельцу придется войти заново.
+func (s *tokenService) ConsumeRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshToken, error) {
+	record, err := s.refreshRepo.GetByHash(ctx, hashOpaqueToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		log.Printf(s.messages.Get(lang.LogRefreshTokenNotFound))
+		return nil, errors.New(s.messages.Get(lang.RefreshTokenInvalid))
+	}
+
+	if record.UsedAt != nil && record.RevokedAt == nil {
+		return nil, s.revokeReusedFamily(ctx, record)
+	}
+
+	if !record.IsActive(time.Now()) {
+		log.Printf(s.messages.Get(lang.LogRefreshTokenExpired), record.ID.String())
+		return nil, errors.New(s.messages.Get(lang.RefreshTokenInvalid))
+	}
+
+	// Токен мог быть использован параллельным запросом между чтением и обновлением
+	marked, err := s.refreshRepo.MarkUsed(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, s.revokeReusedFamily(ctx, record)
+	}
+
+	return record, nil
+}
+
+// StartSession создает сессию входа с клиента; ее ID становится цепочкой refresh токенов
+func (s *tokenService) StartSession(ctx context.Context, userID uuid.UUID, client models.ClientInfo) (uuid.UUID, error) {
+	now := time.Now()
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		UserAgent:  truncateUserAgent(client.UserAgent),
+		IP:         client.IP,
+		Created:    now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.jwtConfig.RefreshTokenTTL),
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return uuid.Nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogSessionStarted), session.ID.String(), userID.String(), client.IP)
+	return session.ID, nil
+}
+
+// TouchSession отмечает обновление токенов сессии и продлевает ее вместе с новым refresh токеном
+func (s *tokenService) TouchSession(ctx context.Context, sessionID uuid.UUID, client models.ClientInfo) error {
+	return s.sessionRepo.Touch(ctx, sessionID, models.ClientInfo{
+		IP:        client.IP,
+		UserAgent: truncateUserAgent(client.UserAgent),
+	}, time.Now().Add(s.jwtConfig.RefreshTokenTTL))
+}
+
+// RevokeSession отзывает access токен и сессию, к которой он относится
+func (s *tokenService) RevokeSession(ctx context.Context, claims *JWTClaims) error {
+	jti, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return errors.New(s.messages.Get(lang.TokenInvalid))
+	}
+
+	if err := s.revocations.RevokeToken(ctx, jti, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	if claims.SessionID != uuid.Nil {
+		return s.RevokeFamily(ctx, claims.SessionID)
+	}
+
+	return nil
+}
+
+// RevokeFamily отзывает цепочку refresh токенов и завершает ее сессию.
+// Access токены сессии перестают приниматься сразу.
+func (s *tokenService) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := s.refreshRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.Revoke(ctx, familyID)
+}
+
+// RevokeAllForUser отзывает все access и refresh токены пользователя и завершает его сессии
+func (s *tokenService) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	if err := s.refreshRepo.RevokeByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.revocations.RevokeAllForUser(ctx, userID)
+}
+
+// AccessTokenTTL возвращает время жизни access токена
+func (s *tokenService) AccessTokenTTL() time.Duration {
+	return s.jwtConfig.AccessTokenTTL
+}
+
+// revokeReusedFamily отзывает цепочку токенов после обнаружения повторного использования
+func (s *tokenService) revokeReusedFamily(ctx context.Context, record *models.RefreshToken) error {
+	log.Printf(s.messages.Get(lang.LogRefreshTokenReuse), record.ID.String(), record.FamilyID.String())
+
+	if err := s.RevokeFamily(ctx, record.FamilyID); err != nil {
+		return err
+	}
+
+	return errors.New(s.messages.Get(lang.RefreshTokenReused))
+}
+
+// truncateUserAgent обрезает User-Agent до длины, которая хранится в сессии
+func truncateUserAgent(userAgent string) string {
+	if runes := []rune(userAgent); len(runes) > maxUserAgentLength {
+		return string(runes[:maxUserAgentLength])
+	}
+	return userAgent
+}
+
+// newOpaqueToken генерирует случайный непрозрачный токен
+func newOpaqueToken() (string, error) {
+	raw := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashOpaqueToken возвращает SHA-256 хеш непрозрачного токена для хранения в БД
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// UserManagementService интерфейс для административного управления пользователями
+type UserManagementService interface {
+	ChangeRole(ctx context.Context, actor *models.User, userID uuid.UUID, req *requests.ChangeRoleRequest) (*models.User, error)
+	RoleHistory(ctx context.Context, userID uuid.UUID) ([]models.RoleChange, error)
+	ListUsers(ctx context.Context, req *requests.ListUsersRequest) (*responses.UserListResponse, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	ChangeStatus(ctx context.Context, actor *models.User, userID uuid.UUID, req *requests.ChangeStatusRequest) (*models.User, error)
+	DeleteUser(ctx context.Context, actor *models.User, userID uuid.UUID) error
+	ResetUser(ctx context.Context, actor *models.User, userID uuid.UUID, req *requests.ResetUserRequest) error
+}
+
+// Размер страницы списка пользователей по умолчанию
+const defaultUsersPerPage = 20
+
+// userManagementService реализация UserManagementService
+type userManagementService struct {
+	userRepo        repositories.UserRepository
+	roleChangeRepo  repositories.RoleChangeRepository
+	mfaRepo         repositories.MFARepository
+	tokenService    TokenService
+	passwordService PasswordService
+	loginGuard      LoginGuard
+	messages        lang.Messages
+}
+
+// NewUserManagementService создает новый экземпляр UserManagementService
+func NewUserManagementService(
+	userRepo repositories.UserRepository,
+	roleChangeRepo repositories.RoleChangeRepository,
+	mfaRepo repositories.MFARepository,
+	tokenService TokenService,
+	passwordService PasswordService,
+	loginGuard LoginGuard,
+	messages lang.Messages,
+) UserManagementService {
+	return &userManagementService{
+		userRepo:        userRepo,
+		roleChangeRepo:  roleChangeRepo,
+		mfaRepo:         mfaRepo,
+		tokenService:    tokenService,
+		passwordService: passwordService,
+		loginGuard:      loginGuard,
+		messages:        messages,
+	}
+}
+
+// ChangeRole меняет роль пользователя и записывает изменение в журнал.
+// Роль передается в access токене, поэтому выданные ранее токены отзываются.
+func (s *userManagementService) ChangeRole(ctx context.Context, actor *models.User, userID uuid.UUID, req *requests.ChangeRoleRequest) (*models.User, error) {
+	// Администратор не может случайно лишить себя прав
+	if actor.ID == userID {
+		return nil, errors.New(s.messages.Get(lang.RoleChangeOwnForbidden))
+	}
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == req.Role {
+		return user, nil
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, user.ID, req.Role); err != nil {
+		return nil, err
+	}
+
+	oldRole := user.Role
+	change := &models.RoleChange{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		OldRole:   &oldRole,
+		NewRole:   req.Role,
+		ChangedBy: &actor.ID,
+		Source:    models.RoleChangeSourceAdmin,
+		Created:   time.Now(),
+	}
+	if err := s.roleChangeRepo.Create(ctx, change); err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	log.Printf(s.messages.Get(lang.LogRoleChanged), user.Email, oldRole, req.Role, actor.Email)
+	user.Role = req.Role
+	return user, nil
+}
+
+// RoleHistory возвращает журнал смены ролей пользователя
+func (s *userManagementService) RoleHistory(ctx context.Context, userID uuid.UUID) ([]models.RoleChange, error) {
+	return s.roleChangeRepo.ListByUser(ctx, userID)
+}
+
+// ListUsers возвращает страницу пользователей с фильтрами по роли, дате создания и email
+func (s *userManagementService) ListUsers(ctx context.Context, req *requests.ListUsersRequest) (*responses.UserListResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage < 1 {
+		perPage = defaultUsersPerPage
+	}
+
+	filter := models.UserFilter{
+		Role:   req.Role,
+		Status: req.Status,
+		Email:  strings.TrimSpace(req.Email),
+		Limit:  perPage,
+		Offset: (page - 1) * perPage,
+	}
+
+	// Формат дат уже проверен валидатором запроса
+	if req.CreatedFrom != "" {
+		createdFrom, err := time.Parse(time.DateOnly, req.CreatedFrom)
+		if err != nil {
+			return nil, err
+		}
+		filter.CreatedFrom = &createdFrom
+	}
+	if req.CreatedTo != "" {
+		createdTo, err := time.Parse(time.DateOnly, req.CreatedTo)
+		if err != nil {
+			return nil, err
+		}
+		// Дата окончания включительно: берем начало следующего дня
+		createdTo = createdTo.AddDate(0, 0, 1)
+		filter.CreatedTo = &createdTo
+	}
+
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.UserListResponse{
+		Users:   users,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// GetUser находит пользователя по ID
+func (s *userManagementService) GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf(s.messages.Get(lang.LogUserFetchError), userID.String(), err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New(s.messages.Get(lang.UserNotFound))
+	}
+
+	return user, nil
+}
+
+// ChangeStatus приостанавливает, деактивирует или снова активирует учетную запись.
+// При блокировке все сессии пользователя завершаются сразу.
+func (s *userManagementService) ChangeStatus(ctx context.Context, actor *models.User, userID uuid.UUID, req *requests.ChangeStatusRequest) (*models.User, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if req.Status != models.StatusActive && reason == "" {
+		return nil, errors.New(s.messages.Get(lang.StatusReasonRequired))
+	}
+
+	user, err := s.otherUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var statusReason *string
+	if reason != "" {
+		statusReason = &reason
+	}
+
+	if err := s.userRepo.UpdateStatus(ctx, user.ID, req.Status, statusReason); err != nil {
+		return nil, err
+	}
+
+	if req.Status != models.StatusActive {
+		if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf(s.messages.Get(lang.LogUserStatusChanged), user.Email, user.Status, req.Status, actor.Email)
+
+	now := time.Now()
+	user.Status = req.Status
+	user.StatusReason = statusReason
+	user.StatusChangedAt = &now
+	return user, nil
+}
+
+// DeleteUser удаляет учетную запись вместе с токенами и настройками входа
+func (s *userManagementService) DeleteUser(ctx context.Context, actor *models.User, userID uuid.UUID) error {
+	user, err := s.otherUser(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+
+	// Отзыв сначала попадает в кеш отозванных токенов, поэтому выполняется до удаления строки
+	if err := s.tokenService.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogUserDeleted), user.Email, actor.Email)
+	return nil
+}
+
+// ResetUser сбрасывает пароль и сессии пользователя, снимает блокировку входа
+// и по запросу отключает второй фактор
+func (s *userManagementService) ResetUser(ctx context.Context, actor *models.User, userID uuid.UUID, req *requests.ResetUserRequest) error {
+	user, err := s.otherUser(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+
+	if req.ResetMFA {
+		if err := s.mfaRepo.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.loginGuard.Unlock(ctx, user.Email); err != nil {
+		return err
+	}
+
+	if err := s.passwordService.ForceReset(ctx, user); err != nil {
+		return err
+	}
+
+	log.Printf(s.messages.Get(lang.LogUserReset), user.Email, actor.Email, req.ResetMFA)
+	return nil
+}
+
+// otherUser находит пользователя, над которым администратор выполняет операцию.
+// Операции над собственной учетной записью запрещены, чтобы не лишить систему администратора.
+func (s *userManagementService) otherUser(ctx context.Context, actor *models.User, userID uuid.UUID) (*models.User, error) {
+	if actor.ID == userID {
+		return nil, errors.New(s.messages.Get(lang.OwnAccountForbidden))
+	}
+
+	return s.GetUser(ctx, userID)
+}
@@ -0,0 +1,331 @@
+package sso
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/avangero/auth-service/internal/lang"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	maxResponseBytes  = 1 << 20          // ответы провайдера больше 1 МиБ не читаются
+	jwksRefreshPeriod = time.Minute      // неизвестный kid перечитывает JWKS не чаще раза в минуту
+	idTokenLeeway     = 30 * time.Second // допустимое расхождение часов с провайдером
+)
+
+// idTokenAlgorithms алгоритмы подписи ID токена, которые принимаются от провайдера
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "EdDSA"}
+
+// discoveryDocument поля /.well-known/openid-configuration, которые нужны для входа
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse ответ эндпоинта токенов провайдера
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// idTokenClaims данные ID токена провайдера
+type idTokenClaims struct {
+	Email           string `json:"email"`
+	EmailVerified   any    `json:"email_verified"` // bool, у части провайдеров строка "true"
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// oidcProvider вход через внешний OpenID Connect провайдер.
+// Документ обнаружения загружается при первом входе, ключи подписи - по kid из ID токена.
+// Запросы к провайдеру идут без блокировки mu, под ней только читается и заменяется кеш.
+type oidcProvider struct {
+	cfg        config.SSOProviderConfig
+	httpClient *http.Client
+	messages   lang.Messages
+
+	mu          sync.Mutex
+	discovery   *discoveryDocument
+	keys        map[string]crypto.PublicKey
+	keysFetched time.Time
+	keysLoading chan struct{} // закрывается, когда загрузка JWKS завершится; nil - JWKS не загружается
+}
+
+// NewOIDCProvider создает провайдер входа по OpenID Connect
+func NewOIDCProvider(cfg config.SSOProviderConfig, httpClient *http.Client, messages lang.Messages) Provider {
+	return &oidcProvider{
+		cfg:        cfg,
+		httpClient: httpClient,
+		messages:   messages,
+	}
+}
+
+// Name возвращает имя провайдера
+func (p *oidcProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL возвращает адрес authorization_endpoint провайдера с параметрами запроса
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", p.providerError(err)
+	}
+
+	query := target.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	target.RawQuery = query.Encode()
+
+	return target.String(), nil
+}
+
+// Exchange обменивает код на ID токен (client_secret_basic) и проверяет его
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, p.providerError(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// RFC 6749: client_id и client_secret перед кодированием в base64 проходят form-urlencoding
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	var tokens tokenResponse
+	status, err := p.doJSON(req, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || tokens.IDToken == "" {
+		return nil, p.providerError(fmt.Errorf("token endpoint: %d %s %s", status, tokens.Error, tokens.ErrorDescription))
+	}
+
+	return p.verifyIDToken(ctx, discovery, tokens.IDToken, nonce)
+}
+
+// verifyIDToken проверяет подпись и данные ID токена и возвращает учетную запись
+func (p *oidcProvider) verifyIDToken(ctx context.Context, discovery *discoveryDocument, idToken, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.verificationKey(ctx, discovery, kid)
+	},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, p.idTokenError(err)
+	}
+
+	// При нескольких получателях токен должен быть выдан именно этому сервису
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, p.idTokenError(errors.New("azp"))
+	}
+	if claims.Subject == "" {
+		return nil, p.idTokenError(errors.New("sub"))
+	}
+	// nonce связывает ID токен с начатым в этом сервисе входом и защищает от повтора
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, p.idTokenError(errors.New("nonce"))
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+	}, nil
+}
+
+// loadDiscovery загружает документ обнаружения провайдера один раз. Параллельные первые входы
+// могут загрузить его одновременно - документы одинаковые, в кеше остается первый.
+func (p *oidcProvider) loadDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	discovery, err := p.fetchDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery == nil {
+		p.discovery = discovery
+		log.Printf(p.messages.Get(lang.LogSSOProviderLoaded), p.cfg.Name, discovery.Issuer)
+	}
+	return p.discovery, nil
+}
+
+// fetchDiscovery запрашивает и проверяет документ обнаружения
+func (p *oidcProvider) fetchDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, p.providerError(err)
+	}
+
+	var discovery discoveryDocument
+	status, err := p.doJSON(req, &discovery)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, p.providerError(fmt.Errorf("discovery: %d", status))
+	}
+
+	// OpenID Connect Discovery: issuer документа должен совпадать с адресом, по которому он загружен
+	if discovery.Issuer != p.cfg.Issuer || discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, p.providerError(fmt.Errorf("discovery: issuer %q", discovery.Issuer))
+	}
+
+	return &discovery, nil
+}
+
+// verificationKey возвращает ключ проверки по kid. Неизвестный kid означает ротацию ключей
+// у провайдера, поэтому JWKS перечитывается, но не чаще jwksRefreshPeriod. Пока JWKS
+// загружается, другие входы с неизвестным kid ждут результат, а не запрашивают его повторно.
+func (p *oidcProvider) verificationKey(ctx context.Context, discovery *discoveryDocument, kid string) (crypto.PublicKey, error) {
+	for {
+		p.mu.Lock()
+		if key, ok := p.keys[kid]; ok {
+			p.mu.Unlock()
+			return key, nil
+		}
+
+		if loading := p.keysLoading; loading != nil {
+			p.mu.Unlock()
+			select {
+			case <-loading:
+				continue
+			case <-ctx.Done():
+				return nil, p.providerError(ctx.Err())
+			}
+		}
+
+		if time.Since(p.keysFetched) < jwksRefreshPeriod {
+			p.mu.Unlock()
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		loading := make(chan struct{})
+		p.keysLoading = loading
+		p.mu.Unlock()
+
+		fetched, err := p.fetchKeys(ctx, discovery)
+
+		p.mu.Lock()
+		if err == nil {
+			p.keys, p.keysFetched = fetched, time.Now()
+		}
+		p.keysLoading = nil
+		close(loading)
+		key, ok := p.keys[kid]
+		p.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return key, nil
+		}
+		return nil, jwt.ErrTokenUnverifiable
+	}
+}
+
+// fetchKeys запрашивает JWKS провайдера. Ключи неподдерживаемых типов и ключи шифрования пропускаются.
+func (p *oidcProvider) fetchKeys(ctx context.Context, discovery *discoveryDocument) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, p.providerError(err)
+	}
+
+	var set keys.JWKS
+	status, err := p.doJSON(req, &set)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, p.providerError(fmt.Errorf("jwks: %d", status))
+	}
+
+	fetched := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.PublicKey(); err == nil {
+			fetched[jwk.Kid] = key
+		}
+	}
+	return fetched, nil
+}
+
+// doJSON выполняет запрос к провайдеру и разбирает JSON ответ любого статуса
+func (p *oidcProvider) doJSON(req *http.Request, target interface{}) (int, error) {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, p.providerError(err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(target); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, p.providerError(err)
+	}
+	return resp.StatusCode, nil
+}
+
+// providerError формирует ошибку обращения к провайдеру
+func (p *oidcProvider) providerError(err error) error {
+	return fmt.Errorf("%s %s: %v", p.messages.Get(lang.SSOProviderError), p.cfg.Name, err)
+}
+
+// idTokenError формирует ошибку проверки ID токена
+func (p *oidcProvider) idTokenError(err error) error {
+	return fmt.Errorf("%s %s: %v", p.messages.Get(lang.SSOIDTokenInvalid), p.cfg.Name, err)
+}
@@ -0,0 +1,24 @@
+package sso
+
+import "context"
+
+// Identity учетная запись пользователя, подтвержденная внешним провайдером
+type Identity struct {
+	Subject       string // постоянный идентификатор пользователя у провайдера (sub)
+	Email         string
+	EmailVerified bool
+}
+
+// Provider внешний провайдер входа (корпоративный SSO). Вход идет по authorization code
+// с PKCE: сервис перенаправляет пользователя на AuthCodeURL, а код из возврата обменивает
+// через Exchange. Реализация для OpenID Connect - NewOIDCProvider; другой протокол
+// подключается своей реализацией интерфейса.
+type Provider interface {
+	// Name возвращает имя провайдера в адресах /api/v1/sso/:provider
+	Name() string
+	// AuthCodeURL возвращает адрес входа у провайдера с state, nonce и code_challenge (S256)
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange обменивает код на учетную запись пользователя. ID токен проверяется
+	// полностью: подпись, iss, aud, срок действия и nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
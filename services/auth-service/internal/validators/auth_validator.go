@@ -1,25 +1,127 @@
 package validators
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/avangero/auth-service/internal/lang"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/go-playground/validator/v10"
 )
 
 // AuthValidator валидатор для аутентификации
 type AuthValidator struct {
-	validator *validator.Validate
-	messages  lang.Messages
+	validator    *validator.Validate
+	messages     lang.Messages
+	emailDomains models.EmailDomainPolicy
+	passwords    passwords.Policy
+}
+
+// Option настраивает AuthValidator
+type Option func(*AuthValidator)
+
+// WithEmailDomainPolicy задает правила доменов для тега corporate_email.
+// Без этой опции тег пропускает любой домен.
+func WithEmailDomainPolicy(policy models.EmailDomainPolicy) Option {
+	return func(v *AuthValidator) {
+		v.emailDomains = policy
+	}
+}
+
+// WithPasswordPolicy задает политику для новых паролей.
+// Без этой опции действует passwords.DefaultPolicy.
+func WithPasswordPolicy(policy passwords.Policy) Option {
+	return func(v *AuthValidator) {
+		v.passwords = policy
+	}
 }
 
 // NewAuthValidator создает новый валидатор
-func NewAuthValidator(messages lang.Messages) *AuthValidator {
-	return &AuthValidator{
+func NewAuthValidator(messages lang.Messages, opts ...Option) *AuthValidator {
+	v := &AuthValidator{
 		validator: validator.New(),
 		messages:  messages,
+		passwords: passwords.DefaultPolicy(messages),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	v.validator.RegisterValidation("corporate_email", v.validateCorporateEmail)
+	v.validator.RegisterValidation("client_scopes", validateClientScopes)
+	v.validator.RegisterValidation("redirect_uri", validateRedirectURI)
+	v.registerPasswordRules()
+	return v
+}
+
+// registerPasswordRules подключает политику паролей к запросам, задающим новый пароль.
+// Каждое нарушенное правило становится отдельной ошибкой со своим тегом. В запросах по токену
+// email неизвестен - совпадение пароля с email там проверяют PasswordService и InvitationService.
+func (v *AuthValidator) registerPasswordRules() {
+	v.validator.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(requests.RegisterRequest)
+		v.checkPassword(sl, "Password", req.Password, req.Email)
+	}, requests.RegisterRequest{})
+	v.validator.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(requests.ResetPasswordRequest)
+		v.checkPassword(sl, "Password", req.Password, "")
+	}, requests.ResetPasswordRequest{})
+	v.validator.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(requests.ChangePasswordRequest)
+		v.checkPassword(sl, "NewPassword", req.NewPassword, req.Email)
+	}, requests.ChangePasswordRequest{})
+	v.validator.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(requests.AcceptInvitationRequest)
+		v.checkPassword(sl, "Password", req.Password, "")
+	}, requests.AcceptInvitationRequest{})
+}
+
+// checkPassword сообщает о каждом нарушенном правиле политики паролей.
+// Пустой пароль уже отклонен тегом required.
+func (v *AuthValidator) checkPassword(sl validator.StructLevel, field, password, email string) {
+	if password == "" {
+		return
 	}
+
+	for _, violation := range v.passwords.Check(context.Background(), password, email) {
+		sl.ReportError(password, field, field, violation.Rule, violation.Param)
+	}
+}
+
+// validateCorporateEmail проверяет домен email по правилам регистрации
+func (v *AuthValidator) validateCorporateEmail(fl validator.FieldLevel) bool {
+	return v.emailDomains.Allows(fl.Field().String())
+}
+
+// validateClientScopes проверяет, что API клиенту выдается хотя бы одно право и все права известны
+func validateClientScopes(fl validator.FieldLevel) bool {
+	scopes, ok := fl.Field().Interface().([]string)
+	if !ok || len(scopes) == 0 {
+		return false
+	}
+
+	for _, scope := range scopes {
+		if !models.IsValidScope(models.Permission(scope)) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRedirectURI проверяет адрес возврата OpenID Connect: абсолютный, без фрагмента
+// и пробелов, так как адреса клиента хранятся через пробел
+func validateRedirectURI(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if strings.ContainsAny(value, " \t\r\n") {
+		return false
+	}
+
+	redirectURI, err := url.Parse(value)
+	return err == nil && redirectURI.IsAbs() && redirectURI.Host != "" && redirectURI.Fragment == ""
 }
 
 // Validate валидирует структуру и возвращает отформатированные ошибки
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/avangero/auth-service/internal/config"
 	"github.com/avangero/auth-service/internal/database"
+	"github.com/avangero/auth-service/internal/directory"
 	"github.com/avangero/auth-service/internal/handlers"
+	"github.com/avangero/auth-service/internal/keys"
 	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/avangero/auth-service/internal/repositories"
 	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/sso"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 func main() {
@@ -25,13 +31,84 @@ func main() {
 		log.Fatal("Ошибка загрузки конфигурации:", err)
 	}
 
+	// Загрузка ключей подписи JWT
+	keySet, err := keys.NewKeySet(cfg.JWT, messages)
+	if err != nil {
+		log.Fatal("Ошибка загрузки ключей JWT:", err)
+	}
+
+	// Загрузка политики паролей и списка утекших паролей
+	var breachedPasswords passwords.BreachedPasswords
+	if cfg.Password.BreachedListFile != "" {
+		breachedPasswords, err = passwords.NewFileBreachedPasswords(cfg.Password.BreachedListFile, messages)
+		if err != nil {
+			log.Fatal("Ошибка загрузки списка утекших паролей:", err)
+		}
+	}
+	passwordPolicy := passwords.NewPolicy(cfg.Password, breachedPasswords, messages)
+	passwordHasher := passwords.NewHasher(cfg.PasswordHash, cfg.BCryptCost)
+
 	// Подключение к базе данных
 	connectionManager := database.NewConnectionManager(messages)
 	db := connectionManager.Connect(cfg)
 
 	// Инициализация слоев приложения (Dependency Injection)
 	userRepo := repositories.NewUserRepository(db, messages)
-	authService := services.NewAuthService(userRepo, cfg.JWT.Secret, cfg.BCryptCost, messages)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db, messages)
+	sessionRepo := repositories.NewSessionRepository(db, messages)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db, messages)
+	revocationRepo := repositories.NewTokenRevocationRepository(db, messages)
+	revocationStore := services.NewRevocationStore(revocationRepo, cfg.JWT, messages)
+	tokenService := services.NewTokenService(refreshTokenRepo, sessionRepo, revocationStore, keySet, cfg.JWT, messages)
+	mailSender := mail.NewSender(cfg.Mail, messages)
+	verificationRepo := repositories.NewEmailVerificationRepository(db, messages)
+	verificationService := services.NewEmailVerificationService(userRepo, verificationRepo, mailSender, cfg.Verification, messages)
+	mfaRepo := repositories.NewMFARepository(db, messages)
+	mfaChallengeRepo := repositories.NewMFAChallengeRepository(db, messages)
+	mfaService := services.NewMFAService(userRepo, mfaRepo, mfaChallengeRepo, cfg.MFA, messages)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(cfg.Lockout, db, messages)
+	loginGuard := services.NewLoginGuard(loginAttemptRepo, cfg.Lockout, messages)
+	roleChangeRepo := repositories.NewRoleChangeRepository(db, messages)
+	userIdentityRepo := repositories.NewUserIdentityRepository(db, messages)
+	groupRepo := repositories.NewGroupRepository(db, messages)
+
+	// Пароль проверяется по хешу в БД, затем в корпоративном каталоге, если он настроен
+	authenticators := []services.Authenticator{services.NewPasswordAuthenticator(userRepo, passwordHasher, messages)}
+	if cfg.LDAP.URL != "" {
+		ldapDirectory := directory.NewLDAPDirectory(cfg.LDAP, messages)
+		authenticators = append(authenticators, services.NewDirectoryAuthenticator(ldapDirectory, userRepo, roleChangeRepo, cfg.LDAP, messages))
+	}
+	authenticator := services.NewChainAuthenticator(authenticators, messages)
+	authService := services.NewAuthService(userRepo, tokenService, verificationService, mfaService, loginGuard, cfg.Auth, passwordHasher, authenticator, messages)
+
+	passwordResetRepo := repositories.NewPasswordResetRepository(db, messages)
+	passwordService := services.NewPasswordService(userRepo, passwordResetRepo, tokenService, mailSender, cfg.PasswordReset, passwordHasher, messages)
+
+	userManagementService := services.NewUserManagementService(userRepo, roleChangeRepo, mfaRepo, tokenService, passwordService, loginGuard, messages)
+	invitationRepo := repositories.NewInvitationRepository(db, messages)
+	invitationService := services.NewInvitationService(userRepo, invitationRepo, roleChangeRepo, authService, mailSender, cfg.Invitation, messages)
+	accountService := services.NewAccountService(userRepo, roleChangeRepo, invitationRepo, mfaRepo, refreshTokenRepo, sessionRepo, apiKeyRepo, userIdentityRepo, groupRepo, loginAttemptRepo, cfg.Erasure, messages)
+	sessionService := services.NewSessionService(sessionRepo, tokenService, messages)
+	clientRepo := repositories.NewAPIClientRepository(db, messages)
+	clientService := services.NewClientService(clientRepo, tokenService, messages)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo, messages)
+	authorizationCodeRepo := repositories.NewAuthorizationCodeRepository(db, messages)
+	oidcService := services.NewOIDCService(authorizationCodeRepo, clientRepo, clientService, authService, tokenService, keySet, cfg.OIDC, messages)
+
+	// Внешние провайдеры входа (корпоративный SSO)
+	ssoHTTPClient := &http.Client{Timeout: 10 * time.Second}
+	var ssoProviders []sso.Provider
+	for _, providerConfig := range cfg.SSO.Providers {
+		ssoProviders = append(ssoProviders, sso.NewOIDCProvider(providerConfig, ssoHTTPClient, messages))
+	}
+	ssoStateRepo := repositories.NewSSOStateRepository(db, messages)
+	ssoService := services.NewSSOService(ssoProviders, ssoStateRepo, userIdentityRepo, userRepo, authService, cfg.Auth, cfg.SSO, messages)
+
+	// Синхронизация сотрудников и групп с кадровой системой (SCIM)
+	scimService := services.NewSCIMService(userRepo, userIdentityRepo, groupRepo, passwordResetRepo, verificationRepo, tokenService, messages)
+
+	// Стирание персональных данных удаленных пользователей по истечении срока хранения
+	go services.RunErasureJob(context.Background(), accountService, cfg.Erasure.Interval, messages)
 
 	// Создание Fiber приложения
 	app := fiber.New(fiber.Config{
@@ -46,12 +123,30 @@ func main() {
 		},
 	})
 
-	// Middleware
-	app.Use(logger.New())
-	app.Use(cors.New())
-
 	// Настройка маршрутов
-	handlers.SetupRoutes(app, authService, messages)
+	handlers.SetupRoutes(app, handlers.RouteDeps{
+		AuthService:         authService,
+		PasswordService:     passwordService,
+		VerificationService: verificationService,
+		MFAService:          mfaService,
+		LoginGuard:          loginGuard,
+		UserManagement:      userManagementService,
+		InvitationService:   invitationService,
+		AccountService:      accountService,
+		SessionService:      sessionService,
+		ClientService:       clientService,
+		APIKeyService:       apiKeyService,
+		OIDCService:         oidcService,
+		SSOService:          ssoService,
+		SCIMService:         scimService,
+		RegistrationDomains: services.RegistrationDomainPolicy(cfg.Auth),
+		PasswordPolicy:      passwordPolicy,
+		RateLimits:          cfg.RateLimit,
+		CORS:                cfg.CORS,
+		OIDCConfig:          cfg.OIDC,
+		KeySet:              keySet,
+		Messages:            messages,
+	})
 
 	// Запуск сервера
 	log.Printf("🚀 Сервер запущен на порту %s", cfg.Port)
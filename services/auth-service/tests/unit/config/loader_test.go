@@ -2,7 +2,9 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/avangero/auth-service/internal/config"
 	"github.com/avangero/auth-service/internal/lang/ru"
@@ -28,6 +30,7 @@ func TestLoader_Load_Success(t *testing.T) {
 	// Проверка
 	require.NoError(t, err)
 	assert.Equal(t, "8081", cfg.Port)
+	assert.Equal(t, []string{"http://localhost:3000"}, cfg.CORS.AllowedOrigins)
 	assert.Equal(t, "test-secret-key", cfg.JWT.Secret)
 	assert.Equal(t, 10, cfg.BCryptCost)
 	assert.Equal(t, "localhost", cfg.Database.Host)
@@ -153,3 +156,631 @@ func TestLoader_Load_DatabaseURL_Priority(t *testing.T) {
 	assert.Equal(t, "urluser", cfg.Database.User)
 	assert.Equal(t, "urldb", cfg.Database.Name)
 }
+
+func TestLoader_Load_TokenTTL(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret-key")
+	os.Setenv("JWT_ACCESS_TTL", "5m")
+	os.Setenv("JWT_REFRESH_TTL", "48h")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("JWT_ACCESS_TTL")
+		os.Unsetenv("JWT_REFRESH_TTL")
+	}()
+
+	messages := ru.NewRussianMessages()
+	loader := config.NewLoader(messages)
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, cfg.JWT.AccessTokenTTL)
+	assert.Equal(t, 48*time.Hour, cfg.JWT.RefreshTokenTTL)
+}
+
+func TestLoader_Load_InvalidTokenTTL(t *testing.T) {
+	tests := []struct {
+		name       string
+		accessTTL  string
+		refreshTTL string
+	}{
+		{"Not a duration", "invalid", "720h"},
+		{"Negative", "-5m", "720h"},
+		{"Access longer than refresh", "48h", "24h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv("JWT_ACCESS_TTL", tt.accessTTL)
+			os.Setenv("JWT_REFRESH_TTL", tt.refreshTTL)
+			defer func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("JWT_ACCESS_TTL")
+				os.Unsetenv("JWT_REFRESH_TTL")
+			}()
+
+			messages := ru.NewRussianMessages()
+			loader := config.NewLoader(messages)
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), "время жизни JWT")
+		})
+	}
+}
+
+func TestLoader_Load_EmailVerification(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("REQUIRE_EMAIL_VERIFICATION", "true")
+	os.Setenv("EMAIL_VERIFICATION_TTL", "48h")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("REQUIRE_EMAIL_VERIFICATION")
+		os.Unsetenv("EMAIL_VERIFICATION_TTL")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка
+	require.NoError(t, err)
+	assert.True(t, cfg.Auth.RequireEmailVerification)
+	assert.Equal(t, 48*time.Hour, cfg.Verification.TokenTTL)
+	assert.Equal(t, "http://localhost:8081/api/v1/verify-email", cfg.Verification.URL)
+}
+
+func TestLoader_Load_MFA(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("REQUIRE_MFA_FOR_MANAGERS", "true")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("REQUIRE_MFA_FOR_MANAGERS")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка
+	require.NoError(t, err)
+	assert.True(t, cfg.MFA.RequiredForManagers)
+	assert.Equal(t, "Learning Portal", cfg.MFA.Issuer)
+	assert.Equal(t, 5*time.Minute, cfg.MFA.ChallengeTTL)
+}
+
+func TestLoader_Load_InvalidLockout(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("LOCKOUT_BASE_DURATION", "2h")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("LOCKOUT_BASE_DURATION")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - первая блокировка не может быть дольше максимальной
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "LOCKOUT_MAX_DURATION")
+}
+
+func TestLoader_Load_RateLimit(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("RATE_LIMIT_LOGIN_IP", "50/30s")
+	os.Setenv("RATE_LIMIT_LOGIN_EMAIL", "0")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("RATE_LIMIT_LOGIN_IP")
+		os.Unsetenv("RATE_LIMIT_LOGIN_EMAIL")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - заданные значения и значения по умолчанию
+	require.NoError(t, err)
+	assert.Equal(t, config.RateLimit{Burst: 50, Period: 30 * time.Second}, cfg.RateLimit.Login.IP)
+	assert.Equal(t, config.RateLimit{}, cfg.RateLimit.Login.Email)
+	assert.Equal(t, config.RateLimit{Burst: 5, Period: time.Minute}, cfg.RateLimit.Register.IP)
+	assert.Equal(t, config.RateLimit{Burst: 3, Period: time.Hour}, cfg.RateLimit.Register.Email)
+	assert.Equal(t, config.RateLimit{Burst: 10, Period: time.Minute}, cfg.RateLimit.LoginMFA.IP)
+	assert.Equal(t, config.RateLimit{Burst: 10, Period: time.Minute}, cfg.RateLimit.PasswordReset.IP)
+	assert.Equal(t, config.RateLimit{Burst: 10, Period: time.Minute}, cfg.RateLimit.InvitationAccept.IP)
+}
+
+func TestLoader_Load_InvalidRateLimit(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("RATE_LIMIT_REGISTER_IP", "5")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("RATE_LIMIT_REGISTER_IP")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "RATE_LIMIT_REGISTER_IP")
+}
+
+func TestLoader_Load_Erasure(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("ERASURE_GRACE_PERIOD", "0s")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("ERASURE_GRACE_PERIOD")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - нулевой срок стирает данные при следующем запуске задачи
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), cfg.Erasure.GracePeriod)
+	assert.Equal(t, time.Hour, cfg.Erasure.Interval)
+}
+
+func TestLoader_Load_InvalidErasure(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("ERASURE_INTERVAL", "0s")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("ERASURE_INTERVAL")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "ERASURE_INTERVAL")
+}
+
+func TestLoader_Load_RegistrationMode(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("REGISTRATION_MODE", "domain")
+	os.Setenv("REGISTRATION_ALLOWED_DOMAINS", "Corp.example, @partner.example")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("REGISTRATION_MODE")
+		os.Unsetenv("REGISTRATION_ALLOWED_DOMAINS")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - домены приводятся к нижнему регистру без @
+	require.NoError(t, err)
+	assert.Equal(t, config.RegistrationDomain, cfg.Auth.RegistrationMode)
+	assert.Equal(t, []string{"corp.example", "partner.example"}, cfg.Auth.RegistrationAllowedDomains)
+}
+
+func TestLoader_Load_RegistrationDomainFiles(t *testing.T) {
+	// Подготовка
+	dir := t.TempDir()
+	deniedFile := filepath.Join(dir, "denied.txt")
+	require.NoError(t, os.WriteFile(deniedFile, []byte("# одноразовые почтовые сервисы\nMailinator.com\n\n@yopmail.com\n"), 0o600))
+
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("REGISTRATION_DENIED_DOMAINS", "tempmail.example")
+	os.Setenv("REGISTRATION_DENIED_DOMAINS_FILE", deniedFile)
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("REGISTRATION_DENIED_DOMAINS")
+		os.Unsetenv("REGISTRATION_DENIED_DOMAINS_FILE")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - список из переменной дополняется строками файла, комментарии пропускаются
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tempmail.example", "mailinator.com", "yopmail.com"}, cfg.Auth.RegistrationDeniedDomains)
+}
+
+func TestLoader_Load_InvalidRegistrationDomains(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"Missing file", "REGISTRATION_DENIED_DOMAINS_FILE", "/nonexistent/denied.txt"},
+		{"Email instead of domain", "REGISTRATION_ALLOWED_DOMAINS", "admin@corp.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv(tt.key, tt.value)
+			defer func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv(tt.key)
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), "REGISTRATION_")
+		})
+	}
+}
+
+func TestLoader_Load_PasswordPolicy(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Setenv("PASSWORD_MIN_LENGTH", "12")
+	os.Setenv("PASSWORD_REQUIRED_CLASSES", "Upper, digit")
+	os.Setenv("PASSWORD_BREACHED_LIST_FILE", "/etc/auth-service/breached.txt")
+	defer func() {
+		os.Unsetenv("JWT_SECRET")
+		os.Unsetenv("PASSWORD_MIN_LENGTH")
+		os.Unsetenv("PASSWORD_REQUIRED_CLASSES")
+		os.Unsetenv("PASSWORD_BREACHED_LIST_FILE")
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - PASSWORD_MAX_BYTES по умолчанию равен ограничению bcrypt
+	require.NoError(t, err)
+	assert.Equal(t, config.PasswordPolicyConfig{
+		MinLength:        12,
+		MaxBytes:         72,
+		RequiredClasses:  []string{config.PasswordClassUpper, config.PasswordClassDigit},
+		BreachedListFile: "/etc/auth-service/breached.txt",
+	}, cfg.Password)
+}
+
+func TestLoader_Load_PasswordHash(t *testing.T) {
+	// Подготовка
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - по умолчанию argon2id с параметрами из рекомендаций OWASP
+	require.NoError(t, err)
+	assert.Equal(t, config.PasswordHashConfig{
+		Algorithm:         config.PasswordHashArgon2id,
+		Argon2Memory:      19456,
+		Argon2Iterations:  2,
+		Argon2Parallelism: 1,
+	}, cfg.PasswordHash)
+}
+
+func TestLoader_Load_InvalidPasswordHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"Unknown algorithm", "PASSWORD_HASH_ALGORITHM", "md5", "PASSWORD_HASH_ALGORITHM=md5"},
+		{"Zero iterations", "ARGON2_ITERATIONS", "0", "ARGON2_ITERATIONS"},
+		{"Too many threads", "ARGON2_PARALLELISM", "300", "ARGON2_PARALLELISM"},
+		{"Memory below minimum", "ARGON2_MEMORY", "4", "ARGON2_MEMORY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv(tt.key, tt.value)
+			defer func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv(tt.key)
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoader_Load_InvalidPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"Above bcrypt limit", "PASSWORD_MAX_BYTES", "100", "PASSWORD_MAX_BYTES"},
+		{"Zero min length", "PASSWORD_MIN_LENGTH", "0", "PASSWORD_MIN_LENGTH"},
+		{"Min length above max", "PASSWORD_MIN_LENGTH", "80", "PASSWORD_MIN_LENGTH"},
+		{"Unknown class", "PASSWORD_REQUIRED_CLASSES", "emoji", "PASSWORD_REQUIRED_CLASSES=emoji"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv(tt.key, tt.value)
+			defer func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv(tt.key)
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoader_Load_InvalidRegistrationMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr string
+	}{
+		{"Unknown mode", "closed", "REGISTRATION_MODE=closed"},
+		{"Domain mode without domains", "domain", "REGISTRATION_ALLOWED_DOMAINS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv("REGISTRATION_MODE", tt.mode)
+			defer func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("REGISTRATION_MODE")
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoader_Load_InvalidCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins string
+	}{
+		{"Wildcard", "*"},
+		{"With path", "http://localhost:3000/app"},
+		{"Not absolute", "localhost:3000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv("CORS_ALLOWED_ORIGINS", tt.origins)
+			defer func() {
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("CORS_ALLOWED_ORIGINS")
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка - запросы с cookie разрешаются только перечисленным источникам
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), "CORS_ALLOWED_ORIGINS")
+		})
+	}
+}
+
+func TestLoader_Load_SSO(t *testing.T) {
+	// Подготовка
+	env := map[string]string{
+		"JWT_SECRET":        "test-secret",
+		"SSO_ISSUER":        "https://id.example.com/realms/corp",
+		"SSO_PROVIDER_NAME": "Keycloak",
+		"SSO_CLIENT_ID":     "learning-portal",
+		"SSO_CLIENT_SECRET": "secret",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка
+	require.NoError(t, err)
+	require.Len(t, cfg.SSO.Providers, 1)
+	assert.Equal(t, "keycloak", cfg.SSO.Providers[0].Name)
+	assert.Equal(t, []string{"openid", "email", "profile"}, cfg.SSO.Providers[0].Scopes)
+	assert.True(t, cfg.SSO.AutoProvision)
+	assert.Equal(t, 10*time.Minute, cfg.SSO.StateTTL)
+}
+
+func TestLoader_Load_InvalidSSO(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"Relative issuer", "SSO_ISSUER", "id.example.com", "SSO_ISSUER"},
+		{"No openid scope", "SSO_SCOPES", "email,profile", "openid"},
+		{"Missing secret", "SSO_CLIENT_SECRET", "", "SSO_CLIENT_SECRET"},
+		{"Invalid provider name", "SSO_PROVIDER_NAME", "corp/sso", "SSO_PROVIDER_NAME"},
+		{"Reserved provider name", "SSO_PROVIDER_NAME", "scim", "SSO_PROVIDER_NAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv("SSO_ISSUER", "https://id.example.com")
+			os.Setenv("SSO_CLIENT_ID", "learning-portal")
+			os.Setenv("SSO_CLIENT_SECRET", "secret")
+			os.Setenv(tt.key, tt.value)
+			defer func() {
+				for _, key := range []string{"JWT_SECRET", "SSO_ISSUER", "SSO_CLIENT_ID", "SSO_CLIENT_SECRET", tt.key} {
+					os.Unsetenv(key)
+				}
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoader_Load_LDAP(t *testing.T) {
+	// Подготовка
+	env := map[string]string{
+		"JWT_SECRET":           "test-secret",
+		"LDAP_URL":             "ldap://dc.corp.example.com",
+		"LDAP_START_TLS":       "true",
+		"LDAP_BIND_DN":         "CN=portal,OU=Services,DC=corp,DC=example,DC=com",
+		"LDAP_BIND_PASSWORD":   "secret",
+		"LDAP_BASE_DN":         "DC=corp,DC=example,DC=com",
+		"LDAP_MANAGER_GROUPS":  "CN=Managers,OU=Groups,DC=corp,DC=example,DC=com; CN=Leads,OU=Groups,DC=corp,DC=example,DC=com",
+		"LDAP_EMPLOYEE_GROUPS": "CN=Staff,OU=Groups,DC=corp,DC=example,DC=com",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	loader := config.NewLoader(ru.NewRussianMessages())
+
+	// Выполнение
+	cfg, err := loader.Load()
+
+	// Проверка - группы разделяются ";", запятые остаются частью DN
+	require.NoError(t, err)
+	assert.True(t, cfg.LDAP.StartTLS)
+	assert.Equal(t, "(&(objectClass=person)(mail=%s))", cfg.LDAP.UserFilter)
+	assert.Equal(t, "memberOf", cfg.LDAP.GroupAttribute)
+	assert.Equal(t, 10*time.Second, cfg.LDAP.Timeout)
+	assert.Equal(t, []string{"CN=Managers,OU=Groups,DC=corp,DC=example,DC=com", "CN=Leads,OU=Groups,DC=corp,DC=example,DC=com"}, cfg.LDAP.ManagerGroups)
+	assert.Equal(t, []string{"CN=Staff,OU=Groups,DC=corp,DC=example,DC=com"}, cfg.LDAP.EmployeeGroups)
+}
+
+func TestLoader_Load_InvalidLDAP(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"Unsupported scheme", "LDAP_URL", "http://dc.corp.example.com", "LDAP_URL"},
+		{"StartTLS over ldaps", "LDAP_START_TLS", "true", "LDAP_START_TLS"},
+		{"Missing base DN", "LDAP_BASE_DN", "", "LDAP_BASE_DN"},
+		{"Filter without placeholder", "LDAP_USER_FILTER", "(mail=*)", "LDAP_USER_FILTER"},
+		{"Bind DN without password", "LDAP_BIND_PASSWORD", "", "LDAP_BIND_PASSWORD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv("LDAP_URL", "ldaps://dc.corp.example.com")
+			os.Setenv("LDAP_BIND_DN", "CN=portal,OU=Services,DC=corp,DC=example,DC=com")
+			os.Setenv("LDAP_BIND_PASSWORD", "secret")
+			os.Setenv("LDAP_BASE_DN", "DC=corp,DC=example,DC=com")
+			os.Setenv(tt.key, tt.value)
+			defer func() {
+				for _, key := range []string{"JWT_SECRET", "LDAP_URL", "LDAP_BIND_DN", "LDAP_BIND_PASSWORD", "LDAP_BASE_DN", tt.key} {
+					os.Unsetenv(key)
+				}
+			}()
+
+			loader := config.NewLoader(ru.NewRussianMessages())
+
+			// Выполнение
+			cfg, err := loader.Load()
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package directory_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/directory"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBaseDN       = "dc=corp,dc=example,dc=com"
+	testServiceDN    = "cn=portal,ou=services,dc=corp,dc=example,dc=com"
+	testServicePass  = "service-secret"
+	testEmployeesDN  = "cn=employees,ou=groups,dc=corp,dc=example,dc=com"
+	testManagersDN   = "cn=managers,ou=groups,dc=corp,dc=example,dc=com"
+	testUserPassword = "directory-password"
+)
+
+// ldapEntry запись каталога-заглушки
+type ldapEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// ldapStandIn LDAP сервер в процессе теста: simple bind, поиск по поддереву с фильтрами
+// &, |, !, = и present. Поиск разрешен только служебной учетной записи, как в AD.
+type ldapStandIn struct {
+	listener net.Listener
+	entries  []ldapEntry
+
+	mu    sync.Mutex
+	binds []string // DN всех попыток bind
+}
+
+func newLDAPStandIn(t *testing.T, entries ...ldapEntry) *ldapStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &ldapStandIn{listener: listener, entries: entries}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *ldapStandIn) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *ldapStandIn) bindAttempts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *ldapStandIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle обрабатывает запросы одного соединения до unbind или закрытия
+func (s *ldapStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	boundDN := ""
+
+	for {
+		request, err := ber.ReadPacket(conn)
+		if err != nil || len(request.Children) < 2 {
+			return
+		}
+		messageID := request.Children[0].Value.(int64)
+		op := request.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+			s.mu.Lock()
+			s.binds = append(s.binds, dn)
+			s.mu.Unlock()
+
+			code := ldap.LDAPResultInvalidCredentials
+			if s.checkPassword(dn, password) {
+				code, boundDN = ldap.LDAPResultSuccess, dn
+			}
+			s.reply(conn, messageID, ldapResult(ldap.ApplicationBindResponse, code))
+
+		case ldap.ApplicationSearchRequest:
+			if boundDN != testServiceDN {
+				s.reply(conn, messageID, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights))
+				continue
+			}
+			s.search(conn, messageID, op)
+
+		default:
+			return
+		}
+	}
+}
+
+// checkPassword проверяет пароль служебной учетной записи или пользователя
+func (s *ldapStandIn) checkPassword(dn, password string) bool {
+	if strings.EqualFold(dn, testServiceDN) {
+		return password == testServicePass
+	}
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.dn, dn) {
+			return password != "" && password == entry.password
+		}
+	}
+	return false
+}
+
+// search отдает подходящие записи с запрошенными атрибутами с учетом sizeLimit
+func (s *ldapStandIn) search(conn net.Conn, messageID int64, op *ber.Packet) {
+	baseDN := strings.ToLower(op.Children[0].Data.String())
+	sizeLimit := int(op.Children[3].Value.(int64))
+	filter := op.Children[6]
+	var requested []string
+	for _, attribute := range op.Children[7].Children {
+		requested = append(requested, attribute.Data.String())
+	}
+
+	sent := 0
+	for _, entry := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(entry.dn), baseDN) || !matchFilter(filter, entry) {
+			continue
+		}
+		if sizeLimit > 0 && sent == sizeLimit {
+			s.reply(conn, messageID, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded))
+			return
+		}
+		s.reply(conn, messageID, searchResultEntry(entry, requested))
+		sent++
+	}
+	s.reply(conn, messageID, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+}
+
+func (s *ldapStandIn) reply(conn net.Conn, messageID int64, op *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	envelope.AppendChild(op)
+	_, _ = conn.Write(envelope.Bytes())
+}
+
+// matchFilter вычисляет фильтр поиска; сравнение значений без учета регистра
+func matchFilter(filter *ber.Packet, entry ldapEntry) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchFilter(child, entry) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchFilter(child, entry) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !matchFilter(filter.Children[0], entry)
+	case ldap.FilterEqualityMatch:
+		for _, value := range attributeValues(entry, filter.Children[0].Data.String()) {
+			if strings.EqualFold(value, filter.Children[1].Data.String()) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		return len(attributeValues(entry, filter.Data.String())) > 0
+	default:
+		return false
+	}
+}
+
+func attributeValues(entry ldapEntry, name string) []string {
+	for attribute, values := range entry.attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func ldapResult(tag ber.Tag, code int) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return op
+}
+
+func searchResultEntry(entry ldapEntry, requested []string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.dn, "DN"))
+
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, name := range requested {
+		values := attributeValues(entry, name)
+		if len(values) == 0 {
+			continue
+		}
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		attributes.AppendChild(attribute)
+	}
+	op.AppendChild(attributes)
+
+	return op
+}
+
+func testPerson(uid, email string, groups ...string) ldapEntry {
+	return ldapEntry{
+		dn:       "uid=" + uid + ",ou=people," + testBaseDN,
+		password: testUserPassword,
+		attributes: map[string][]string{
+			"objectClass": {"top", "person", "inetOrgPerson"},
+			"uid":         {uid},
+			"mail":        {email},
+			"memberOf":    groups,
+		},
+	}
+}
+
+func newTestDirectory(server *ldapStandIn) directory.Directory {
+	return directory.NewLDAPDirectory(config.LDAPConfig{
+		URL:            server.url(),
+		BindDN:         testServiceDN,
+		BindPassword:   testServicePass,
+		BaseDN:         testBaseDN,
+		UserFilter:     "(&(objectClass=person)(mail=%s))",
+		EmailAttribute: "mail",
+		GroupAttribute: "memberOf",
+		Timeout:        5 * time.Second,
+	}, ru.NewRussianMessages())
+}
+
+func TestLDAPDirectory_Authenticate_Success(t *testing.T) {
+	// Подготовка
+	server := newLDAPStandIn(t,
+		testPerson("ivanov", "Ivanov@corp.example.com", testEmployeesDN, testManagersDN),
+		testPerson("petrov", "petrov@corp.example.com", testEmployeesDN),
+	)
+	dir := newTestDirectory(server)
+
+	// Выполнение
+	entry, err := dir.Authenticate(context.Background(), "ivanov@corp.example.com", testUserPassword)
+
+	// Проверка - поиск от служебной учетной записи, пароль проверен bind от имени пользователя
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "uid=ivanov,ou=people,"+testBaseDN, entry.DN)
+	assert.Equal(t, "Ivanov@corp.example.com", entry.Email)
+	assert.Equal(t, []string{testEmployeesDN, testManagersDN}, entry.Groups)
+	assert.Equal(t, []string{testServiceDN, entry.DN}, server.bindAttempts())
+}
+
+func TestLDAPDirectory_Authenticate_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{"Неверный пароль", "petrov@corp.example.com", "wrong-password"},
+		{"Пользователя нет в каталоге", "sidorov@corp.example.com", testUserPassword},
+		{"Email совпадает у двух записей", "shared@corp.example.com", testUserPassword},
+		{"Подстановка в фильтр поиска", "*", testUserPassword},
+		{"Внедрение условия в фильтр", "petrov@corp.example.com)(objectClass=*", testUserPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			server := newLDAPStandIn(t,
+				testPerson("petrov", "petrov@corp.example.com"),
+				testPerson("shared1", "shared@corp.example.com"),
+				testPerson("shared2", "shared@corp.example.com"),
+			)
+			dir := newTestDirectory(server)
+
+			// Выполнение
+			entry, err := dir.Authenticate(context.Background(), tt.email, tt.password)
+
+			// Проверка - отказ без ошибки, чтобы попытка засчиталась как неудачная
+			require.NoError(t, err)
+			assert.Nil(t, entry)
+		})
+	}
+}
+
+func TestLDAPDirectory_Authenticate_EmptyPassword(t *testing.T) {
+	// Подготовка
+	server := newLDAPStandIn(t, testPerson("petrov", "petrov@corp.example.com"))
+	dir := newTestDirectory(server)
+
+	// Выполнение
+	entry, err := dir.Authenticate(context.Background(), "petrov@corp.example.com", "")
+
+	// Проверка - unauthenticated bind не выполняется вовсе
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+	assert.Empty(t, server.bindAttempts())
+}
+
+func TestLDAPDirectory_Authenticate_DirectoryErrors(t *testing.T) {
+	// Подготовка
+	server := newLDAPStandIn(t, testPerson("petrov", "petrov@corp.example.com"))
+	wrongServicePassword := config.LDAPConfig{
+		URL:            server.url(),
+		BindDN:         testServiceDN,
+		BindPassword:   "outdated-secret",
+		BaseDN:         testBaseDN,
+		UserFilter:     "(mail=%s)",
+		EmailAttribute: "mail",
+		GroupAttribute: "memberOf",
+		Timeout:        5 * time.Second,
+	}
+	unavailable := wrongServicePassword
+	unavailable.URL = "ldap://127.0.0.1:1"
+
+	for name, cfg := range map[string]config.LDAPConfig{"Неверный пароль служебной учетной записи": wrongServicePassword, "Сервер недоступен": unavailable} {
+		t.Run(name, func(t *testing.T) {
+			dir := directory.NewLDAPDirectory(cfg, ru.NewRussianMessages())
+
+			// Выполнение
+			entry, err := dir.Authenticate(context.Background(), "petrov@corp.example.com", testUserPassword)
+
+			// Проверка - ошибка каталога не считается неверным паролем пользователя
+			require.Error(t, err)
+			assert.Nil(t, entry)
+			assert.Contains(t, err.Error(), "Ошибка обращения к каталогу LDAP")
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/handlers"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOIDCService для тестирования
+type MockOIDCService struct {
+	mock.Mock
+}
+
+func (m *MockOIDCService) Discovery() *responses.OIDCDiscoveryResponse {
+	args := m.Called()
+	return args.Get(0).(*responses.OIDCDiscoveryResponse)
+}
+
+func (m *MockOIDCService) CheckAuthorization(ctx context.Context, req *requests.AuthorizeRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockOIDCService) Authorize(ctx context.Context, tokenString string, req *requests.AuthorizeRequest) (*responses.AuthorizeResponse, error) {
+	args := m.Called(ctx, tokenString, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.AuthorizeResponse), args.Error(1)
+}
+
+func (m *MockOIDCService) ExchangeCode(ctx context.Context, req *requests.ClientTokenRequest) (*responses.ClientTokenResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.ClientTokenResponse), args.Error(1)
+}
+
+func (m *MockOIDCService) UserInfo(ctx context.Context, tokenString string) (*responses.UserInfoResponse, error) {
+	args := m.Called(ctx, tokenString)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.UserInfoResponse), args.Error(1)
+}
+
+func TestOIDCHandler_Authorize_LoginURLWithQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		loginURL  string
+		wantPath  string
+		wantQuery url.Values
+	}{
+		{
+			name:     "страница входа без параметров",
+			loginURL: "http://localhost:3000/oauth/authorize",
+			wantPath: "/oauth/authorize",
+			wantQuery: url.Values{
+				"client_id": {"crm"},
+				"state":     {"abc"},
+			},
+		},
+		{
+			name:     "параметры страницы входа сохраняются",
+			loginURL: "http://localhost:3000/login?flow=oidc&lang=ru",
+			wantPath: "/login",
+			wantQuery: url.Values{
+				"flow":      {"oidc"},
+				"lang":      {"ru"},
+				"client_id": {"crm"},
+				"state":     {"abc"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			oidcService := new(MockOIDCService)
+			app := fiber.New()
+			handlers.SetupRoutes(app, handlers.RouteDeps{
+				OIDCService: oidcService,
+				OIDCConfig:  config.OIDCConfig{LoginURL: tt.loginURL},
+				CORS:        config.CORSConfig{AllowedOrigins: []string{frontendOrigin}},
+				Messages:    ru.NewRussianMessages(),
+			})
+
+			// Настройка моков
+			oidcService.On("CheckAuthorization", mock.Anything, mock.AnythingOfType("*requests.AuthorizeRequest")).Return(nil)
+
+			// Выполнение
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/oauth/authorize?client_id=crm&state=abc", nil))
+			require.NoError(t, err)
+
+			// Проверка
+			assert.Equal(t, fiber.StatusFound, resp.StatusCode)
+			location, err := url.Parse(resp.Header.Get(fiber.HeaderLocation))
+			require.NoError(t, err)
+			assert.Equal(t, "localhost:3000", location.Host)
+			assert.Equal(t, tt.wantPath, location.Path)
+			assert.Equal(t, tt.wantQuery, location.Query())
+			oidcService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/handlers"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Страница фронтенда, на которую провайдер возвращает пользователя
+const frontendOrigin = "http://localhost:3000"
+
+// MockSSOService для тестирования
+type MockSSOService struct {
+	mock.Mock
+}
+
+func (m *MockSSOService) Providers() *responses.SSOProvidersResponse {
+	args := m.Called()
+	return args.Get(0).(*responses.SSOProvidersResponse)
+}
+
+func (m *MockSSOService) Begin(ctx context.Context, provider string) (string, string, error) {
+	args := m.Called(ctx, provider)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockSSOService) Complete(ctx context.Context, provider string, req *requests.SSOCallbackRequest, client models.ClientInfo) (*responses.TokenResponse, error) {
+	args := m.Called(ctx, provider, req, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.TokenResponse), args.Error(1)
+}
+
+// newSSOApp собирает приложение с маршрутами сервиса и CORS как в рабочей конфигурации
+func newSSOApp(ssoService *MockSSOService) *fiber.App {
+	app := fiber.New()
+	handlers.SetupRoutes(app, handlers.RouteDeps{
+		SSOService: ssoService,
+		CORS:       config.CORSConfig{AllowedOrigins: []string{frontendOrigin}},
+		Messages:   ru.NewRussianMessages(),
+	})
+	return app
+}
+
+// stateCookie возвращает cookie sso_state из ответа
+func stateCookie(resp *http.Response) *http.Cookie {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "sso_state" {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// callbackRequest собирает POST возврата от провайдера так, как его отправляет фронтенд
+func callbackRequest(cookie *http.Cookie) *http.Request {
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1/sso/corporate/callback", strings.NewReader(`{"code":"auth-code","state":"raw-state"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderOrigin, frontendOrigin)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestSSOHandler_CallbackWithBrowserCookie(t *testing.T) {
+	// Подготовка
+	ssoService := new(MockSSOService)
+	app := newSSOApp(ssoService)
+
+	// Настройка моков - сервис получает хеш state из cookie браузера, начавшего вход
+	ssoService.On("Begin", mock.Anything, "corporate").Return("https://idp.example.com/authorize?state=raw-state", "state-hash", nil)
+	ssoService.On("Complete", mock.Anything, "corporate", mock.MatchedBy(func(req *requests.SSOCallbackRequest) bool {
+		return req.Code == "auth-code" && req.State == "raw-state" && req.BrowserState == "state-hash"
+	}), mock.Anything).Return(&responses.TokenResponse{Token: "access-token"}, nil)
+
+	// Выполнение - начало входа
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/sso/corporate/login", nil))
+	require.NoError(t, err)
+
+	// Проверка - перенаправление к провайдеру и cookie, недоступная скриптам
+	assert.Equal(t, fiber.StatusFound, resp.StatusCode)
+	cookie := stateCookie(resp)
+	require.NotNil(t, cookie)
+	assert.Equal(t, "state-hash", cookie.Value)
+	assert.True(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	assert.Equal(t, "/api/v1/sso", cookie.Path)
+
+	// Выполнение - preflight запроса фронтенда с credentials
+	preflight := httptest.NewRequest(fiber.MethodOptions, "/api/v1/sso/corporate/callback", nil)
+	preflight.Header.Set(fiber.HeaderOrigin, frontendOrigin)
+	preflight.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+	resp, err = app.Test(preflight)
+	require.NoError(t, err)
+
+	// Проверка - браузер отправит cookie только при явном источнике и разрешенных credentials
+	assert.Equal(t, frontendOrigin, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", resp.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+
+	// Выполнение - возврат от провайдера с cookie
+	resp, err = app.Test(callbackRequest(cookie))
+	require.NoError(t, err)
+
+	// Проверка - вход завершен, cookie удалена
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, frontendOrigin, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", resp.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+	cleared := stateCookie(resp)
+	require.NotNil(t, cleared)
+	assert.Empty(t, cleared.Value)
+	ssoService.AssertExpectations(t)
+}
+
+func TestSSOHandler_CallbackWithoutCookie(t *testing.T) {
+	// Подготовка
+	ssoService := new(MockSSOService)
+	app := newSSOApp(ssoService)
+
+	// Настройка моков - без cookie сервис получает пустой хеш и отклоняет возврат
+	ssoService.On("Complete", mock.Anything, "corporate", mock.MatchedBy(func(req *requests.SSOCallbackRequest) bool {
+		return req.BrowserState == ""
+	}), mock.Anything).Return(nil, assert.AnError)
+
+	// Выполнение
+	resp, err := app.Test(callbackRequest(nil))
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	ssoService.AssertExpectations(t)
+}
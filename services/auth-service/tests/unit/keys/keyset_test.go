@@ -0,0 +1,167 @@
+package keys_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair генерирует ключ и сохраняет приватную и публичную части в PEM файлы
+func writeKeyPair(t *testing.T, kind string) (privatePath, publicPath string) {
+	t.Helper()
+
+	var private, public interface{}
+	switch kind {
+	case "rsa":
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		private, public = key, &key.PublicKey
+	case "ed25519":
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		private, public = key, pub
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privatePath = filepath.Join(dir, kind+".pem")
+	publicPath = filepath.Join(dir, kind+".pub.pem")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+	return privatePath, publicPath
+}
+
+// signAndParse подписывает токен одним набором ключей и проверяет другим
+func signAndParse(signer, verifier *keys.KeySet) (*jwt.Token, error) {
+	token := jwt.NewWithClaims(signer.SigningMethod(), jwt.RegisteredClaims{Subject: "user"})
+	signed, err := signer.Sign(token)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.Parse(signed, verifier.Keyfunc, jwt.WithValidMethods(verifier.ValidMethods()))
+}
+
+func TestKeySet_AsymmetricSigning(t *testing.T) {
+	tests := []struct {
+		kind string
+		alg  string
+		kty  string
+	}{
+		{"rsa", "RS256", "RSA"},
+		{"ed25519", "EdDSA", "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			// Подготовка
+			privatePath, _ := writeKeyPair(t, tt.kind)
+			keySet, err := keys.NewKeySet(config.JWTConfig{SigningKeyFile: privatePath}, ru.NewRussianMessages())
+			require.NoError(t, err)
+
+			// Выполнение
+			token, err := signAndParse(keySet, keySet)
+
+			// Проверка
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, tt.alg, token.Method.Alg())
+
+			jwks := keySet.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tt.kty, jwks.Keys[0].Kty)
+			assert.Equal(t, tt.alg, jwks.Keys[0].Alg)
+			assert.Equal(t, token.Header["kid"], jwks.Keys[0].Kid)
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	// Подготовка - старый ключ подписи и новый, при котором старый оставлен для проверки
+	oldPrivate, oldPublic := writeKeyPair(t, "ed25519")
+	newPrivate, _ := writeKeyPair(t, "rsa")
+	messages := ru.NewRussianMessages()
+
+	oldKeySet, err := keys.NewKeySet(config.JWTConfig{SigningKeyFile: oldPrivate}, messages)
+	require.NoError(t, err)
+	rotatedKeySet, err := keys.NewKeySet(config.JWTConfig{
+		SigningKeyFile:       newPrivate,
+		SigningKeyID:         "2024-new",
+		VerificationKeyFiles: []string{oldPublic},
+	}, messages)
+	require.NoError(t, err)
+
+	// Выполнение
+	oldToken, err := signAndParse(oldKeySet, rotatedKeySet)
+	require.NoError(t, err)
+	newToken, err := signAndParse(rotatedKeySet, rotatedKeySet)
+	require.NoError(t, err)
+
+	// Проверка - принимаются токены обоих ключей, оба ключа опубликованы
+	assert.True(t, oldToken.Valid)
+	assert.True(t, newToken.Valid)
+	assert.Equal(t, "2024-new", newToken.Header["kid"])
+	assert.Len(t, rotatedKeySet.JWKS().Keys, 2)
+}
+
+func TestKeySet_UnknownKeyRejected(t *testing.T) {
+	// Подготовка
+	firstPrivate, _ := writeKeyPair(t, "rsa")
+	secondPrivate, _ := writeKeyPair(t, "rsa")
+	messages := ru.NewRussianMessages()
+
+	first, err := keys.NewKeySet(config.JWTConfig{SigningKeyFile: firstPrivate}, messages)
+	require.NoError(t, err)
+	second, err := keys.NewKeySet(config.JWTConfig{SigningKeyFile: secondPrivate}, messages)
+	require.NoError(t, err)
+
+	// Выполнение
+	_, err = signAndParse(first, second)
+
+	// Проверка
+	assert.Error(t, err)
+}
+
+func TestKeySet_HMACFallback(t *testing.T) {
+	// Подготовка
+	keySet, err := keys.NewKeySet(config.JWTConfig{Secret: "test-secret"}, ru.NewRussianMessages())
+	require.NoError(t, err)
+
+	// Выполнение
+	token, err := signAndParse(keySet, keySet)
+
+	// Проверка - общий секрет не публикуется в JWKS
+	require.NoError(t, err)
+	assert.Equal(t, "HS256", token.Method.Alg())
+	assert.Empty(t, keySet.JWKS().Keys)
+}
+
+func TestKeySet_InvalidKeyFile(t *testing.T) {
+	// Подготовка
+	path := filepath.Join(t.TempDir(), "broken.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+
+	// Выполнение
+	keySet, err := keys.NewKeySet(config.JWTConfig{SigningKeyFile: path}, ru.NewRussianMessages())
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, keySet)
+	assert.Contains(t, err.Error(), "Не удалось загрузить ключ JWT")
+}
@@ -0,0 +1,218 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuthorizedApp создает приложение, в котором пользователь уже прошел JWTMiddleware
+func newAuthorizedApp(user *models.User, guard fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		if user != nil {
+			c.Locals("user", user)
+		}
+		return c.Next()
+	}, guard, func(c *fiber.Ctx) error {
+		return c.SendString(middleware.GetUserRole(c))
+	})
+	return app
+}
+
+// newClientApp создает приложение, в котором API клиент уже прошел JWTMiddleware
+func newClientApp(client *models.AuthenticatedClient, guard fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		c.Locals("client", client)
+		return c.Next()
+	}, guard, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+// newAPIKeyApp создает приложение, в котором пользователь вошел по API ключу
+func newAPIKeyApp(user *models.User, apiKey *models.APIKey, guard fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/protected", func(c *fiber.Ctx) error {
+		c.Locals("user", user)
+		c.Locals("api_key", apiKey)
+		return c.Next()
+	}, guard, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+// getStatus выполняет запрос к защищенному маршруту и возвращает код ответа
+func getStatus(t *testing.T, app *fiber.App) int {
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/protected", nil))
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestRequireRole(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequireRole(messages, models.RoleManager, models.RoleAdmin)
+
+	tests := []struct {
+		name string
+		user *models.User
+		want int
+	}{
+		{"Manager allowed", &models.User{Email: "manager@example.com", Role: models.RoleManager}, fiber.StatusOK},
+		{"Admin allowed", &models.User{Email: "admin@example.com", Role: models.RoleAdmin}, fiber.StatusOK},
+		{"Employee forbidden", &models.User{Email: "employee@example.com", Role: models.RoleEmployee}, fiber.StatusForbidden},
+		{"Unauthenticated", nil, fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getStatus(t, newAuthorizedApp(tt.user, guard)))
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequirePermission(messages, models.PermissionUsersUnlock)
+
+	tests := []struct {
+		name string
+		user *models.User
+		want int
+	}{
+		{"Admin allowed", &models.User{Email: "admin@example.com", Role: models.RoleAdmin}, fiber.StatusOK},
+		{"Manager forbidden", &models.User{Email: "manager@example.com", Role: models.RoleManager}, fiber.StatusForbidden},
+		{"Unauthenticated", nil, fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getStatus(t, newAuthorizedApp(tt.user, guard)))
+		})
+	}
+}
+
+func TestRequirePermission_Client(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequirePermission(messages, models.PermissionTeamProgressRead)
+
+	tests := []struct {
+		name   string
+		scopes []models.Permission
+		want   int
+	}{
+		{"Scope granted", []models.Permission{models.PermissionTokensValidate, models.PermissionTeamProgressRead}, fiber.StatusOK},
+		{"Scope missing", []models.Permission{models.PermissionTokensValidate}, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &models.AuthenticatedClient{ID: uuid.New(), Name: "user-service", Scopes: tt.scopes}
+			assert.Equal(t, tt.want, getStatus(t, newClientApp(client, guard)))
+		})
+	}
+}
+
+func TestRequirePermission_SCIMOnlyForClients(t *testing.T) {
+	// Права scim:provision нет ни у одной роли: даже администратор не управляет учетными записями через SCIM
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequirePermission(messages, models.PermissionSCIMProvision)
+	hris := &models.AuthenticatedClient{ID: uuid.New(), Name: "hris", Scopes: []models.Permission{models.PermissionSCIMProvision}}
+
+	assert.Equal(t, fiber.StatusForbidden, getStatus(t, newAuthorizedApp(&models.User{Email: "admin@example.com", Role: models.RoleAdmin}, guard)))
+	assert.Equal(t, fiber.StatusOK, getStatus(t, newClientApp(hris, guard)))
+}
+
+func TestRequireScope(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequireScope(messages, models.PermissionTokensValidate)
+
+	validator := &models.AuthenticatedClient{ID: uuid.New(), Scopes: []models.Permission{models.PermissionTokensValidate}}
+	other := &models.AuthenticatedClient{ID: uuid.New(), Scopes: []models.Permission{models.PermissionCoursesRead}}
+
+	// Пользователь проходит без прав клиента, клиенту нужно право в токене
+	assert.Equal(t, fiber.StatusOK, getStatus(t, newAuthorizedApp(&models.User{Email: "employee@example.com", Role: models.RoleEmployee}, guard)))
+	assert.Equal(t, fiber.StatusUnauthorized, getStatus(t, newAuthorizedApp(nil, guard)))
+	assert.Equal(t, fiber.StatusOK, getStatus(t, newClientApp(validator, guard)))
+	assert.Equal(t, fiber.StatusForbidden, getStatus(t, newClientApp(other, guard)))
+}
+
+func TestRequireRole_ClientForbidden(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequireRole(messages, models.RoleAdmin)
+	client := &models.AuthenticatedClient{ID: uuid.New(), Scopes: []models.Permission{models.PermissionCoursesRead}}
+
+	assert.Equal(t, fiber.StatusForbidden, getStatus(t, newClientApp(client, guard)))
+}
+
+func TestRequirePermission_APIKey(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequirePermission(messages, models.PermissionTeamProgressRead)
+	manager := &models.User{Email: "manager@example.com", Role: models.RoleManager}
+	employee := &models.User{Email: "employee@example.com", Role: models.RoleEmployee}
+
+	tests := []struct {
+		name  string
+		user  *models.User
+		scope string
+		want  int
+	}{
+		{"Ключ без scope получает права роли", manager, "", fiber.StatusOK},
+		{"Право есть в scope ключа", manager, "progress:read team:progress:read", fiber.StatusOK},
+		{"Права нет в scope ключа", manager, "progress:read", fiber.StatusForbidden},
+		{"Scope не расширяет права роли", employee, "team:progress:read", fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKey := &models.APIKey{ID: uuid.New(), Scope: tt.scope}
+			assert.Equal(t, tt.want, getStatus(t, newAPIKeyApp(tt.user, apiKey, guard)))
+		})
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	guard := middleware.RequireSession(messages)
+	user := &models.User{Email: "employee@example.com", Role: models.RoleEmployee}
+	client := &models.AuthenticatedClient{ID: uuid.New(), Scopes: []models.Permission{models.PermissionCoursesRead}}
+
+	// Управлять учетной записью можно только после входа, но не по API ключу или токену клиента
+	assert.Equal(t, fiber.StatusOK, getStatus(t, newAuthorizedApp(user, guard)))
+	assert.Equal(t, fiber.StatusUnauthorized, getStatus(t, newAuthorizedApp(nil, guard)))
+	assert.Equal(t, fiber.StatusForbidden, getStatus(t, newAPIKeyApp(user, &models.APIKey{ID: uuid.New()}, guard)))
+	assert.Equal(t, fiber.StatusForbidden, getStatus(t, newClientApp(client, guard)))
+}
+
+func TestGetUserHelpers(t *testing.T) {
+	// Подготовка
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", Role: models.RoleEmployee}
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		// Без пользователя в контексте помощники возвращают нулевые значения
+		assert.Equal(t, uuid.Nil, middleware.GetUserID(c))
+		assert.Empty(t, middleware.GetUserEmail(c))
+
+		c.Locals("user", user)
+		assert.Equal(t, user.ID, middleware.GetUserID(c))
+		assert.Equal(t, user.Email, middleware.GetUserEmail(c))
+		assert.Equal(t, user.Role, middleware.GetUserRole(c))
+		return nil
+	})
+
+	// Выполнение
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+
+	// Проверка
+	require.NoError(t, err)
+}
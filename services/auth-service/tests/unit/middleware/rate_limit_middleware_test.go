@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitedApp создает приложение с одним ограниченным маршрутом
+func newRateLimitedApp(limits config.RouteRateLimit) *fiber.App {
+	app := fiber.New()
+	app.Post("/login", middleware.RateLimitMiddleware(limits, ru.NewRussianMessages()), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+// postLogin отправляет запрос входа с указанным email
+func postLogin(t *testing.T, app *fiber.App, email string) *http.Response {
+	req := httptest.NewRequest(fiber.MethodPost, "/login", strings.NewReader(`{"email":"`+email+`","password":"secret"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestRateLimitMiddleware_IPLimit(t *testing.T) {
+	// Подготовка
+	app := newRateLimitedApp(config.RouteRateLimit{
+		IP: config.RateLimit{Burst: 2, Period: time.Hour},
+	})
+
+	// Выполнение - лимит по IP не зависит от email
+	first := postLogin(t, app, "first@example.com")
+	second := postLogin(t, app, "second@example.com")
+	third := postLogin(t, app, "third@example.com")
+
+	// Проверка - третий запрос отклонен, следующий токен через полчаса
+	assert.Equal(t, fiber.StatusOK, first.StatusCode)
+	assert.Equal(t, fiber.StatusOK, second.StatusCode)
+	assert.Equal(t, fiber.StatusTooManyRequests, third.StatusCode)
+	assert.Equal(t, "1800", third.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func TestRateLimitMiddleware_EmailLimit(t *testing.T) {
+	// Подготовка
+	app := newRateLimitedApp(config.RouteRateLimit{
+		IP:    config.RateLimit{Burst: 100, Period: time.Minute},
+		Email: config.RateLimit{Burst: 1, Period: time.Minute},
+	})
+
+	// Выполнение - регистр и пробелы в email не дают обойти лимит
+	first := postLogin(t, app, "user@example.com")
+	second := postLogin(t, app, " USER@example.com")
+	other := postLogin(t, app, "other@example.com")
+
+	// Проверка
+	assert.Equal(t, fiber.StatusOK, first.StatusCode)
+	assert.Equal(t, fiber.StatusTooManyRequests, second.StatusCode)
+	assert.Equal(t, "60", second.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, fiber.StatusOK, other.StatusCode)
+}
+
+func TestRateLimitMiddleware_Disabled(t *testing.T) {
+	// Подготовка - нулевой лимит отключает ограничение
+	app := newRateLimitedApp(config.RouteRateLimit{})
+
+	// Выполнение и проверка
+	for i := 0; i < 10; i++ {
+		resp := postLogin(t, app, "user@example.com")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	}
+}
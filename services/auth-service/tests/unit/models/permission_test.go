@@ -0,0 +1,58 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		permission models.Permission
+		want       bool
+	}{
+		{"Employee reads courses", models.RoleEmployee, models.PermissionCoursesRead, true},
+		{"Employee cannot assign training", models.RoleEmployee, models.PermissionTrainingAssign, false},
+		{"Manager assigns training", models.RoleManager, models.PermissionTrainingAssign, true},
+		{"Manager keeps employee permissions", models.RoleManager, models.PermissionCoursesEnroll, true},
+		{"Manager invites users", models.RoleManager, models.PermissionUsersInvite, true},
+		{"Employee cannot invite users", models.RoleEmployee, models.PermissionUsersInvite, false},
+		{"Manager cannot manage users", models.RoleManager, models.PermissionUsersManage, false},
+		{"Admin manages users", models.RoleAdmin, models.PermissionUsersManage, true},
+		{"Admin validates skills", models.RoleAdmin, models.PermissionSkillsValidate, true},
+		{"Unknown role has no permissions", "guest", models.PermissionCoursesRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.HasPermission(tt.role, tt.permission))
+		})
+	}
+}
+
+func TestPermissionsForRole_ReturnsCopy(t *testing.T) {
+	// Изменение возвращенного списка не должно влиять на таблицу прав
+	permissions := models.PermissionsForRole(models.RoleEmployee)
+	permissions[0] = models.PermissionUsersManage
+
+	assert.False(t, models.HasPermission(models.RoleEmployee, models.PermissionUsersManage))
+	assert.True(t, models.IsValidRole(models.RoleAdmin))
+	assert.False(t, models.IsValidRole("guest"))
+}
+
+func TestIsValidScope(t *testing.T) {
+	// Клиенту можно выдать чтение данных портала и служебные права, которых нет ни у одной роли
+	assert.True(t, models.IsValidScope(models.PermissionTeamProgressRead))
+	assert.True(t, models.IsValidScope(models.PermissionTokensValidate))
+	assert.False(t, models.IsValidScope(models.PermissionUsersManage))
+	assert.False(t, models.IsValidScope(models.PermissionClientsManage))
+	assert.False(t, models.IsValidScope(models.PermissionCoursesManage))
+	assert.True(t, models.IsValidScope(models.PermissionSCIMProvision))
+	assert.False(t, models.HasPermission(models.RoleAdmin, models.PermissionSCIMProvision))
+	assert.False(t, models.IsValidScope("root"))
+	assert.False(t, models.HasPermission(models.RoleAdmin, models.PermissionTokensValidate))
+	assert.True(t, models.HasPermission(models.RoleAdmin, models.PermissionClientsManage))
+}
@@ -0,0 +1,70 @@
+package passwords_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeList записывает список хешей во временный файл
+func writeList(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileBreachedPasswords(t *testing.T) {
+	// Подготовка - хеши "password123" и "qwerty" в формате HASH:COUNT и в нижнем регистре
+	path := writeList(t, "# выгрузка утечек\nCBFDAC6008F9CAB4083784CBD1874F76618D2A97:2410\n\nb1b3773a05c0ed0176787a4f1574ff0075f7521e\n")
+
+	source, err := passwords.NewFileBreachedPasswords(path, ru.NewRussianMessages())
+	require.NoError(t, err)
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"password123", true},
+		{"qwerty", true},
+		{"Correct-Horse-7", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			// Выполнение
+			breached, err := passwords.IsBreached(context.Background(), source, tt.password)
+
+			// Проверка
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, breached)
+		})
+	}
+}
+
+func TestFileBreachedPasswords_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"Missing file", "/nonexistent/breached.txt"},
+		{"Not a SHA-1 hash", writeList(t, "password123\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Выполнение
+			source, err := passwords.NewFileBreachedPasswords(tt.path, ru.NewRussianMessages())
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, source)
+			assert.Contains(t, err.Error(), "PASSWORD_BREACHED_LIST_FILE")
+		})
+	}
+}
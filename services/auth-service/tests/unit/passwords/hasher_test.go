@@ -0,0 +1,130 @@
+package passwords_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2idParams параметры argon2id, достаточные для быстрых тестов
+var testArgon2idParams = passwords.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher passwords.Hasher
+		prefix string
+	}{
+		{"bcrypt", passwords.NewBcryptHasher(4), "$2a$04$"},
+		{"argon2id", passwords.NewArgon2idHasher(testArgon2idParams), "$argon2id$v=19$m=64,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Выполнение
+			hash, err := tt.hasher.Hash("password123")
+			require.NoError(t, err)
+
+			ok, err := tt.hasher.Verify(hash, "password123")
+			require.NoError(t, err)
+			wrong, err := tt.hasher.Verify(hash, "wrong-password")
+			require.NoError(t, err)
+
+			// Проверка - алгоритм и параметры записаны в строку хеша
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+			assert.True(t, ok)
+			assert.False(t, wrong)
+			assert.False(t, tt.hasher.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestHasher_Argon2idUsesRandomSalt(t *testing.T) {
+	// Подготовка
+	hasher := passwords.NewArgon2idHasher(testArgon2idParams)
+
+	// Выполнение
+	first, err := hasher.Hash("password123")
+	require.NoError(t, err)
+	second, err := hasher.Hash("password123")
+	require.NoError(t, err)
+
+	// Проверка
+	assert.NotEqual(t, first, second)
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	weakArgon2id, err := passwords.NewArgon2idHasher(testArgon2idParams).Hash("password123")
+	require.NoError(t, err)
+	strongArgon2id, err := passwords.NewArgon2idHasher(passwords.Argon2idParams{Memory: 128, Iterations: 2, Parallelism: 1}).Hash("password123")
+	require.NoError(t, err)
+
+	current := passwords.NewHasher(config.PasswordHashConfig{
+		Algorithm:         config.PasswordHashArgon2id,
+		Argon2Memory:      128,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}, 4)
+
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{"Other algorithm", string(bcryptHash), true},
+		{"Less memory than current", weakArgon2id, true},
+		{"Stronger than current", strongArgon2id, false},
+		{"Unknown format", "plain-text", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, current.NeedsRehash(tt.hash))
+		})
+	}
+}
+
+func TestHasher_BcryptCostIncrease(t *testing.T) {
+	// Подготовка
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	hasher := passwords.NewHasher(config.PasswordHashConfig{Algorithm: config.PasswordHashBcrypt}, 5)
+
+	// Выполнение и проверка - хеш с меньшей стоимостью помечается для перехеширования
+	assert.True(t, hasher.NeedsRehash(string(bcryptHash)))
+}
+
+func TestHasher_VerifiesLegacyAlgorithm(t *testing.T) {
+	// Подготовка - пароль сохранен bcrypt, текущий алгоритм argon2id
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	hasher := passwords.NewHasher(config.PasswordHashConfig{
+		Algorithm:         config.PasswordHashArgon2id,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}, 4)
+
+	// Выполнение
+	ok, err := hasher.Verify(string(bcryptHash), "password123")
+
+	// Проверка
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestHasher_VerifyUnknownHash(t *testing.T) {
+	// Подготовка - у стертой учетной записи пароль пустой
+	hasher := passwords.NewHasher(config.PasswordHashConfig{Algorithm: config.PasswordHashArgon2id, Argon2Memory: 64, Argon2Iterations: 1, Argon2Parallelism: 1}, 4)
+
+	// Выполнение
+	ok, err := hasher.Verify("", "password123")
+
+	// Проверка
+	assert.ErrorIs(t, err, passwords.ErrUnknownHash)
+	assert.False(t, ok)
+}
@@ -0,0 +1,111 @@
+package passwords_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/passwords"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBreachedPasswords мок источника утекших паролей
+type MockBreachedPasswords struct {
+	mock.Mock
+}
+
+func (m *MockBreachedPasswords) Range(ctx context.Context, prefix string) ([]string, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// rules возвращает имена нарушенных правил
+func rules(violations []passwords.Violation) []string {
+	var result []string
+	for _, violation := range violations {
+		result = append(result, violation.Rule)
+	}
+	return result
+}
+
+func TestPolicy_Check(t *testing.T) {
+	cfg := config.PasswordPolicyConfig{
+		MinLength:       8,
+		MaxBytes:        72,
+		RequiredClasses: []string{config.PasswordClassLower, config.PasswordClassUpper, config.PasswordClassDigit, config.PasswordClassSymbol},
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		email    string
+		want     []string
+	}{
+		{"Strong password", "Correct-Horse-7", "user@corp.example", nil},
+		{"Too short", "Ab1!", "", []string{passwords.RuleMinLength}},
+		{"Length counted in characters", "Пароль1!", "", nil},
+		{"Longer than bcrypt limit", "Aa1!" + string(make([]byte, 69)), "", []string{passwords.RuleMaxBytes}},
+		{"Only lower case", "onlylowercase", "", []string{passwords.RuleUpper, passwords.RuleDigit, passwords.RuleSymbol}},
+		{"Email as password", "User.Name1@Corp.example", "user.name1@corp.example", []string{passwords.RuleEmail}},
+		{"Local part as password", "User.Name-1", "user.name-1@corp.example", []string{passwords.RuleEmail}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			policy := passwords.NewPolicy(cfg, nil, ru.NewRussianMessages())
+
+			// Выполнение
+			violations := policy.Check(context.Background(), tt.password, tt.email)
+
+			// Проверка
+			assert.Equal(t, tt.want, rules(violations))
+		})
+	}
+}
+
+func TestPolicy_Check_MinLengthParam(t *testing.T) {
+	// Подготовка
+	policy := passwords.DefaultPolicy(ru.NewRussianMessages())
+
+	// Выполнение
+	violations := policy.Check(context.Background(), "short", "")
+
+	// Проверка - параметр попадает в текст ошибки
+	assert.Equal(t, []passwords.Violation{{Rule: passwords.RuleMinLength, Param: "8"}}, violations)
+}
+
+func TestPolicy_Check_Breached(t *testing.T) {
+	// Подготовка - SHA-1("password123") = CBFDAC6008F9CAB4083784CBD1874F76618D2A97
+	breached := new(MockBreachedPasswords)
+	breached.On("Range", mock.Anything, "CBFDA").Return([]string{"0000000000000000000000000000000000A", "C6008F9CAB4083784CBD1874F76618D2A97"}, nil)
+
+	policy := passwords.NewPolicy(config.PasswordPolicyConfig{MinLength: 8, MaxBytes: 72}, breached, ru.NewRussianMessages())
+
+	// Выполнение
+	violations := policy.Check(context.Background(), "password123", "")
+
+	// Проверка - в источник уходит только префикс хеша
+	assert.Equal(t, []string{passwords.RuleBreached}, rules(violations))
+	breached.AssertExpectations(t)
+}
+
+func TestPolicy_Check_BreachedSourceUnavailable(t *testing.T) {
+	// Подготовка
+	breached := new(MockBreachedPasswords)
+	breached.On("Range", mock.Anything, mock.Anything).Return(nil, errors.New("timeout"))
+
+	policy := passwords.NewPolicy(config.PasswordPolicyConfig{MinLength: 8, MaxBytes: 72}, breached, ru.NewRussianMessages())
+
+	// Выполнение
+	violations := policy.Check(context.Background(), "password123", "")
+
+	// Проверка - недоступный источник не блокирует пароль
+	assert.Empty(t, violations)
+}
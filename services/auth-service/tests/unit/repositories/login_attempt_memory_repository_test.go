@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLoginAttemptRepository_RegisterFailure(t *testing.T) {
+	repo := repositories.NewMemoryLoginAttemptRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	// Ошибки внутри окна накапливаются
+	for i := 1; i <= 3; i++ {
+		attempt, err := repo.RegisterFailure(ctx, "email:user@example.com", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, i, attempt.Failures)
+	}
+
+	// Ошибка после окна начинает счетчик заново
+	later := now.Add(2 * time.Hour)
+	attempt, err := repo.RegisterFailure(ctx, "email:user@example.com", later, later.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+	assert.Equal(t, later, attempt.LastFailureAt)
+}
+
+func TestMemoryLoginAttemptRepository_LockAndReset(t *testing.T) {
+	repo := repositories.NewMemoryLoginAttemptRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := repo.RegisterFailure(ctx, "ip:192.0.2.10", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	// Блокировка сохраняется
+	require.NoError(t, repo.Lock(ctx, "ip:192.0.2.10", now.Add(time.Minute)))
+	attempt, err := repo.Get(ctx, "ip:192.0.2.10")
+	require.NoError(t, err)
+	assert.True(t, attempt.IsLocked(now))
+	assert.False(t, attempt.IsLocked(now.Add(2*time.Minute)))
+
+	// Сброс удаляет счетчик
+	require.NoError(t, repo.Reset(ctx, "ip:192.0.2.10"))
+	attempt, err = repo.Get(ctx, "ip:192.0.2.10")
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+}
@@ -0,0 +1,113 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/scim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		attribute  string
+		value      string
+	}{
+		{"userName", `userName eq "ivanov@example.com"`, "userName", "ivanov@example.com"},
+		{"Оператор в другом регистре", `externalId Eq "E-1024"`, "externalId", "E-1024"},
+		{"URN схемы перед атрибутом", `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "a@example.com"`, "userName", "a@example.com"},
+		{"Экранированная кавычка", `displayName eq "Отдел \"Продажи\""`, "displayName", `Отдел "Продажи"`},
+		{"Вложенный атрибут", `emails.value eq "a@example.com"`, "emails.value", "a@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := scim.ParseFilter(tt.expression)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.attribute, filter.Attribute)
+			assert.Equal(t, tt.value, filter.Value)
+		})
+	}
+}
+
+func TestParseFilter_Unsupported(t *testing.T) {
+	expressions := []string{
+		``,
+		`userName`,
+		`userName co "ivanov"`,
+		`userName eq ivanov`,
+		`userName eq "a@example.com" and active eq true`,
+	}
+
+	for _, expression := range expressions {
+		t.Run(expression, func(t *testing.T) {
+			_, err := scim.ParseFilter(expression)
+			assert.ErrorIs(t, err, scim.ErrInvalidFilter)
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		attribute    string
+		filter       *scim.Filter
+		subAttribute string
+	}{
+		{"Простой атрибут", "active", "active", nil, ""},
+		{"Участник группы", `members[value eq "2819c223"]`, "members", &scim.Filter{Attribute: "value", Value: "2819c223"}, ""},
+		{"Рабочий email", `emails[type eq "work"].value`, "emails", &scim.Filter{Attribute: "type", Value: "work"}, "value"},
+		{"Вложенный атрибут", "name.givenName", "name", nil, "givenName"},
+		{"Атрибут расширения", "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "department", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := scim.ParsePath(tt.path)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.attribute, path.Attribute)
+			assert.Equal(t, tt.filter, path.Filter)
+			assert.Equal(t, tt.subAttribute, path.SubAttribute)
+		})
+	}
+}
+
+func TestNewListQuery(t *testing.T) {
+	// Некорректные значения заменяются ближайшими допустимыми, а не отклоняются
+	assert.Equal(t, scim.ListQuery{StartIndex: 1, Count: scim.DefaultCount}, scim.NewListQuery("", "", "", ""))
+	assert.Equal(t, scim.ListQuery{StartIndex: 1, Count: 0}, scim.NewListQuery("", "0", "-5", ""))
+	assert.Equal(t, scim.ListQuery{StartIndex: 11, Count: scim.MaxResults}, scim.NewListQuery("", "11", "1000", ""))
+
+	query := scim.NewListQuery(` displayName eq "HR" `, "1", "10", "externalId, Members")
+	assert.Equal(t, `displayName eq "HR"`, query.Filter)
+	assert.True(t, query.ExcludeMembers)
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		raw   string
+		value bool
+		ok    bool
+	}{
+		{`false`, false, true},
+		{`true`, true, true},
+		{`"False"`, false, true}, // Azure AD
+		{`"TRUE"`, true, true},
+		{`"no"`, false, false},
+		{`0`, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			value, ok := scim.ParseBool(json.RawMessage(tt.raw))
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// accountServiceMocks зависимости AccountService для тестов
+type accountServiceMocks struct {
+	userRepo       *MockUserRepository
+	roleChangeRepo *MockRoleChangeRepository
+	invitationRepo *MockInvitationRepository
+	mfaRepo        *MockMFARepository
+	refreshRepo    *MockRefreshTokenRepository
+	sessionRepo    *MockSessionRepository
+	apiKeyRepo     *MockAPIKeyRepository
+	identityRepo   *MockUserIdentityRepository
+	groupRepo      *MockGroupRepository
+	loginAttempts  repositories.LoginAttemptRepository
+}
+
+// testErasureConfig настройки стирания для тестов
+var testErasureConfig = config.ErasureConfig{
+	GracePeriod: 30 * 24 * time.Hour,
+	Interval:    time.Hour,
+}
+
+// newTestAccountService создает AccountService с тестовыми зависимостями
+func newTestAccountService() (services.AccountService, *accountServiceMocks) {
+	m := &accountServiceMocks{
+		userRepo:       new(MockUserRepository),
+		roleChangeRepo: new(MockRoleChangeRepository),
+		invitationRepo: new(MockInvitationRepository),
+		mfaRepo:        new(MockMFARepository),
+		refreshRepo:    new(MockRefreshTokenRepository),
+		sessionRepo:    new(MockSessionRepository),
+		apiKeyRepo:     new(MockAPIKeyRepository),
+		identityRepo:   new(MockUserIdentityRepository),
+		groupRepo:      new(MockGroupRepository),
+		loginAttempts:  repositories.NewMemoryLoginAttemptRepository(),
+	}
+
+	service := services.NewAccountService(
+		m.userRepo,
+		m.roleChangeRepo,
+		m.invitationRepo,
+		m.mfaRepo,
+		m.refreshRepo,
+		m.sessionRepo,
+		m.apiKeyRepo,
+		m.identityRepo,
+		m.groupRepo,
+		m.loginAttempts,
+		testErasureConfig,
+		ru.NewRussianMessages(),
+	)
+	return service, m
+}
+
+func (m *accountServiceMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.roleChangeRepo.AssertExpectations(t)
+	m.invitationRepo.AssertExpectations(t)
+	m.mfaRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.sessionRepo.AssertExpectations(t)
+	m.apiKeyRepo.AssertExpectations(t)
+	m.identityRepo.AssertExpectations(t)
+	m.groupRepo.AssertExpectations(t)
+}
+
+func TestAccountService_Export_CollectsDataWithoutSecrets(t *testing.T) {
+	// Подготовка
+	service, m := newTestAccountService()
+	ctx := context.Background()
+	confirmedAt := time.Now().Add(-time.Hour)
+
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: "bcrypt-hash", Role: models.RoleEmployee}
+	mfa := &models.UserMFA{UserID: user.ID, Secret: "TOTPSECRET", ConfirmedAt: &confirmedAt}
+	token := models.RefreshToken{ID: uuid.New(), UserID: user.ID, FamilyID: uuid.New(), TokenHash: "refresh-hash", ExpiresAt: time.Now().Add(time.Hour)}
+	invitation := models.Invitation{ID: uuid.New(), Email: user.Email, Role: models.RoleEmployee, TokenHash: "invitation-hash"}
+	device := models.Session{ID: token.FamilyID, UserID: user.ID, UserAgent: "test-agent/1.0", IP: "192.0.2.10", ExpiresAt: token.ExpiresAt}
+	apiKey := models.APIKey{ID: uuid.New(), UserID: user.ID, Name: "reports", Prefix: "ak_abcdefgh", KeyHash: "api-key-hash"}
+	identity := models.UserIdentity{Provider: "corporate", Subject: "idp-subject-1", UserID: user.ID, Email: user.Email, Created: time.Now()}
+	group := models.Group{ID: uuid.New(), DisplayName: "Отдел продаж"}
+
+	_, err := m.loginAttempts.RegisterFailure(ctx, "email:"+user.Email, time.Now(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.roleChangeRepo.On("ListByUser", mock.Anything, user.ID).Return([]models.RoleChange{}, nil)
+	m.invitationRepo.On("ListByEmail", mock.Anything, user.Email).Return([]models.Invitation{invitation}, nil)
+	m.mfaRepo.On("GetByUserID", mock.Anything, user.ID).Return(mfa, nil)
+	m.refreshRepo.On("ListByUser", mock.Anything, user.ID).Return([]models.RefreshToken{token}, nil)
+	m.sessionRepo.On("ListByUser", mock.Anything, user.ID).Return([]models.Session{device}, nil)
+	m.apiKeyRepo.On("ListByUser", mock.Anything, user.ID).Return([]models.APIKey{apiKey}, nil)
+	m.identityRepo.On("ListByUser", mock.Anything, user.ID).Return([]models.UserIdentity{identity}, nil)
+	m.groupRepo.On("ListByUser", mock.Anything, user.ID).Return([]models.Group{group}, nil)
+
+	// Выполнение
+	export, err := service.Export(ctx, user.ID)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, export.User.ID)
+	require.NotNil(t, export.MFA)
+	assert.True(t, export.MFA.Enabled)
+	require.Len(t, export.Sessions, 1)
+	assert.Equal(t, token.FamilyID, export.Sessions[0].FamilyID)
+	require.Len(t, export.Devices, 1)
+	assert.Equal(t, device.UserAgent, export.Devices[0].UserAgent)
+	require.Len(t, export.APIKeys, 1)
+	assert.Equal(t, apiKey.Prefix, export.APIKeys[0].Prefix)
+	require.Len(t, export.Identities, 1)
+	assert.Equal(t, identity.Subject, export.Identities[0].Subject)
+	assert.Equal(t, identity.Created, export.Identities[0].LinkedAt)
+	require.Len(t, export.Groups, 1)
+	assert.Equal(t, group.ID, export.Groups[0].ID)
+	assert.Equal(t, group.DisplayName, export.Groups[0].DisplayName)
+	require.NotNil(t, export.LoginAttempt)
+	assert.Equal(t, 1, export.LoginAttempt.Failures)
+
+	body, err := json.Marshal(export)
+	require.NoError(t, err)
+	for _, secret := range []string{"bcrypt-hash", "TOTPSECRET", "refresh-hash", "invitation-hash", "api-key-hash"} {
+		assert.False(t, strings.Contains(string(body), secret), secret)
+	}
+
+	m.assertExpectations(t)
+}
+
+func TestAccountService_EraseDeleted_ScrubsExpiredAccounts(t *testing.T) {
+	// Подготовка
+	service, m := newTestAccountService()
+	ctx := context.Background()
+
+	first := models.User{ID: uuid.New(), Email: "first@example.com"}
+	second := models.User{ID: uuid.New(), Email: "second@example.com"}
+
+	_, err := m.loginAttempts.RegisterFailure(ctx, "email:"+first.Email, time.Now(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	// Настройка моков - срок хранения отсчитывается от текущего момента
+	m.userRepo.On("ListDeleted", mock.Anything, mock.MatchedBy(func(deletedBefore time.Time) bool {
+		return time.Since(deletedBefore) >= testErasureConfig.GracePeriod
+	}), 100).Return([]models.User{first, second}, nil)
+	m.userRepo.On("Erase", mock.Anything, mock.MatchedBy(func(user *models.User) bool { return user.ID == first.ID }),
+		"erased-"+first.ID.String()+"@erased.invalid").Return(nil)
+	m.userRepo.On("Erase", mock.Anything, mock.MatchedBy(func(user *models.User) bool { return user.ID == second.ID }),
+		"erased-"+second.ID.String()+"@erased.invalid").Return(errors.New("db error"))
+
+	// Выполнение
+	erased, err := service.EraseDeleted(ctx)
+
+	// Проверка - ошибка одной записи не останавливает остальные
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+
+	attempt, err := m.loginAttempts.Get(ctx, "email:"+first.Email)
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+
+	m.assertExpectations(t)
+}
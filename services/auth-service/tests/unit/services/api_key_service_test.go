@@ -0,0 +1,258 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository для тестирования
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.APIKey, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// newTestAPIKeyService создает APIKeyService с тестовыми зависимостями
+func newTestAPIKeyService() (services.APIKeyService, *MockAPIKeyRepository, *MockUserRepository) {
+	apiKeyRepo := new(MockAPIKeyRepository)
+	userRepo := new(MockUserRepository)
+	return services.NewAPIKeyService(apiKeyRepo, userRepo, ru.NewRussianMessages()), apiKeyRepo, userRepo
+}
+
+// hashAPIKey возвращает хеш ключа в том виде, в котором он хранится в БД
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAPIKeyService_Create_StoresHashAndScopes(t *testing.T) {
+	// Подготовка
+	service, apiKeyRepo, _ := newTestAPIKeyService()
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+	expiresAt := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	req := &requests.CreateAPIKeyRequest{
+		Name:      "progress-report",
+		Scopes:    []string{"team:progress:read", "progress:read", "team:progress:read"},
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}
+
+	// Настройка моков
+	var stored *models.APIKey
+	apiKeyRepo.On("ListActiveByUser", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return([]models.APIKey{}, nil)
+	apiKeyRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.APIKey")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.APIKey)
+	}).Return(nil)
+
+	// Выполнение
+	response, err := service.Create(context.Background(), user, req)
+
+	// Проверка - ключ возвращается один раз, в БД только хеш и начало ключа
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(response.Key, services.APIKeyPrefix))
+	assert.Equal(t, hashAPIKey(response.Key), stored.KeyHash)
+	assert.True(t, strings.HasPrefix(response.Key, stored.Prefix))
+	assert.Less(t, len(stored.Prefix), len(response.Key))
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Equal(t, "team:progress:read progress:read", stored.Scope)
+	require.NotNil(t, stored.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*stored.ExpiresAt))
+
+	apiKeyRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Create_Rejected(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee}
+
+	tests := []struct {
+		name    string
+		req     *requests.CreateAPIKeyRequest
+		active  int
+		wantErr string
+	}{
+		{"Право не из роли владельца", &requests.CreateAPIKeyRequest{Name: "report", Scopes: []string{"users:manage"}}, 0, "только права вашей роли"},
+		{"Срок в прошлом", &requests.CreateAPIKeyRequest{Name: "report", ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)}, 0, "в будущем"},
+		{"Превышен лимит ключей", &requests.CreateAPIKeyRequest{Name: "report"}, 20, "максимальное число"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, apiKeyRepo, _ := newTestAPIKeyService()
+
+			// Настройка моков
+			apiKeyRepo.On("ListActiveByUser", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).
+				Return(make([]models.APIKey, tt.active), nil)
+
+			// Выполнение
+			response, err := service.Create(context.Background(), user, tt.req)
+
+			// Проверка
+			require.Error(t, err)
+			assert.Nil(t, response)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			apiKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate_ResolvesUserAndTouches(t *testing.T) {
+	// Подготовка
+	service, apiKeyRepo, userRepo := newTestAPIKeyService()
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager, Status: models.StatusActive}
+	key := services.APIKeyPrefix + "secret-value"
+	apiKey := &models.APIKey{ID: uuid.New(), UserID: user.ID, Prefix: key[:11], KeyHash: hashAPIKey(key)}
+
+	// Настройка моков
+	apiKeyRepo.On("GetByHash", mock.Anything, hashAPIKey(key)).Return(apiKey, nil)
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	apiKeyRepo.On("Touch", mock.Anything, apiKey.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Выполнение
+	authenticated, resolvedKey, err := service.Authenticate(context.Background(), key)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, authenticated.ID)
+	assert.Equal(t, apiKey.ID, resolvedKey.ID)
+	assert.NotNil(t, resolvedKey.LastUsedAt)
+
+	apiKeyRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Authenticate_RecentlyUsedKeyNotTouched(t *testing.T) {
+	// Подготовка
+	service, apiKeyRepo, userRepo := newTestAPIKeyService()
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager, Status: models.StatusActive}
+	key := services.APIKeyPrefix + "secret-value"
+	lastUsed := time.Now().Add(-10 * time.Second)
+	apiKey := &models.APIKey{ID: uuid.New(), UserID: user.ID, KeyHash: hashAPIKey(key), LastUsedAt: &lastUsed}
+
+	// Настройка моков
+	apiKeyRepo.On("GetByHash", mock.Anything, hashAPIKey(key)).Return(apiKey, nil)
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+	// Выполнение
+	_, _, err := service.Authenticate(context.Background(), key)
+
+	// Проверка - время использования не пишется в БД на каждый запрос
+	require.NoError(t, err)
+	apiKeyRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_Authenticate_Rejected(t *testing.T) {
+	key := services.APIKeyPrefix + "secret-value"
+	past := time.Now().Add(-time.Minute)
+	userID := uuid.New()
+
+	tests := []struct {
+		name   string
+		apiKey *models.APIKey
+		user   *models.User
+	}{
+		{"Неизвестный ключ", nil, nil},
+		{"Отозванный ключ", &models.APIKey{ID: uuid.New(), UserID: userID, RevokedAt: &past}, nil},
+		{"Истекший ключ", &models.APIKey{ID: uuid.New(), UserID: userID, ExpiresAt: &past}, nil},
+		{"Приостановленный владелец", &models.APIKey{ID: uuid.New(), UserID: userID},
+			&models.User{ID: userID, Email: "employee@example.com", Role: models.RoleEmployee, Status: models.StatusSuspended}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, apiKeyRepo, userRepo := newTestAPIKeyService()
+
+			// Настройка моков
+			apiKeyRepo.On("GetByHash", mock.Anything, hashAPIKey(key)).Return(tt.apiKey, nil)
+			if tt.user != nil {
+				userRepo.On("GetByID", mock.Anything, userID).Return(tt.user, nil)
+			}
+
+			// Выполнение
+			user, apiKey, err := service.Authenticate(context.Background(), key)
+
+			// Проверка
+			require.Error(t, err)
+			assert.Nil(t, user)
+			assert.Nil(t, apiKey)
+			apiKeyRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke_ForeignKeyNotFound(t *testing.T) {
+	// Подготовка
+	service, apiKeyRepo, _ := newTestAPIKeyService()
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee}
+	foreign := &models.APIKey{ID: uuid.New(), UserID: uuid.New()}
+
+	// Настройка моков
+	apiKeyRepo.On("GetByID", mock.Anything, foreign.ID).Return(foreign, nil)
+
+	// Выполнение
+	err := service.Revoke(context.Background(), user, foreign.ID)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API ключ не найден")
+	apiKeyRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
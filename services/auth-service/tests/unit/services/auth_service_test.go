@@ -2,13 +2,19 @@ package services_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
 	"github.com/avangero/auth-service/internal/lang/ru"
 	"github.com/avangero/auth-service/internal/models"
 	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/avangero/auth-service/internal/services"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +33,11 @@ func (m *MockUserRepository) Create(ctx context.Context, user *models.User) erro
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) CreateWithIdentity(ctx context.Context, user *models.User, identity *models.UserIdentity) error {
+	args := m.Called(ctx, user, identity)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
@@ -48,53 +59,244 @@ func (m *MockUserRepository) EmailExists(ctx context.Context, email string) (boo
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	args := m.Called(ctx, id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter models.UserFilter) ([]models.User, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, reason *string) error {
+	args := m.Called(ctx, id, status, reason)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]models.User, error) {
+	args := m.Called(ctx, deletedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Erase(ctx context.Context, user *models.User, tombstoneEmail string) error {
+	args := m.Called(ctx, user, tombstoneEmail)
+	return args.Error(0)
+}
+
+// MockRefreshTokenRepository для тестирования
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RefreshToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RefreshToken), args.Error(1)
+}
+
+// MockRevocationStore для тестирования
+type MockRevocationStore struct {
+	mock.Mock
+}
+
+func (m *MockRevocationStore) RevokeToken(ctx context.Context, jti, userID uuid.UUID, expiresAt time.Time) error {
+	args := m.Called(ctx, jti, userID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) IsRevoked(ctx context.Context, claims *services.JWTClaims) (bool, error) {
+	args := m.Called(ctx, claims)
+	return args.Bool(0), args.Error(1)
+}
+
+// testJWTConfig конфигурация JWT для тестов
+var testJWTConfig = config.JWTConfig{
+	Secret:                 "test-secret",
+	AccessTokenTTL:         15 * time.Minute,
+	RefreshTokenTTL:        24 * time.Hour,
+	RevocationSyncInterval: time.Minute,
+}
+
+// newTestTokenService создает TokenService с тестовыми зависимостями; сессии всегда активны
+func newTestTokenService(refreshRepo *MockRefreshTokenRepository, revocations *MockRevocationStore) services.TokenService {
+	return newTestTokenServiceWith(refreshRepo, newTestSessionRepository(), revocations)
+}
+
+// newTestTokenServiceWith создает TokenService с заданным хранилищем сессий
+func newTestTokenServiceWith(refreshRepo *MockRefreshTokenRepository, sessionRepo *MockSessionRepository, revocations *MockRevocationStore) services.TokenService {
+	messages := ru.NewRussianMessages()
+	keySet, _ := keys.NewKeySet(testJWTConfig, messages)
+	return services.NewTokenService(refreshRepo, sessionRepo, revocations, keySet, testJWTConfig, messages)
+}
+
+// authServiceDeps зависимости AuthService, которые отдельные тесты заменяют своими моками.
+// Незаданные моки разрешают вызовы без проверки: письма подтверждения не проверяются,
+// второй фактор не требуется, попытки входа считаются в памяти, сессии всегда активны.
+type authServiceDeps struct {
+	sessions      *MockSessionRepository
+	verification  *MockEmailVerificationService
+	mfa           *MockMFAService
+	loginGuard    services.LoginGuard
+	authConfig    config.AuthConfig
+	hasher        passwords.Hasher
+	authenticator services.Authenticator
+}
+
+// newTestHasher создает хешер bcrypt с минимальной стоимостью, как у хешей в тестах
+func newTestHasher() passwords.Hasher {
+	return passwords.NewHasher(config.PasswordHashConfig{
+		Algorithm:         config.PasswordHashBcrypt,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}, 4)
+}
+
+// newTestAuthService создает AuthService с тестовыми зависимостями
+func newTestAuthService(userRepo *MockUserRepository, refreshRepo *MockRefreshTokenRepository, revocations *MockRevocationStore) services.AuthService {
+	return newTestAuthServiceWith(userRepo, refreshRepo, revocations, authServiceDeps{})
+}
+
+// newTestAuthServiceWith создает AuthService с заданными зависимостями и правилами аутентификации
+func newTestAuthServiceWith(userRepo *MockUserRepository, refreshRepo *MockRefreshTokenRepository, revocations *MockRevocationStore, deps authServiceDeps) services.AuthService {
+	if deps.verification == nil {
+		deps.verification = new(MockEmailVerificationService)
+		deps.verification.On("SendVerification", mock.Anything, mock.Anything).Return(nil).Maybe()
+	}
+	if deps.mfa == nil {
+		deps.mfa = new(MockMFAService)
+		deps.mfa.On("Challenge", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	}
+	if deps.loginGuard == nil {
+		deps.loginGuard = newTestLoginGuard()
+	}
+	if deps.hasher == nil {
+		deps.hasher = newTestHasher()
+	}
+	if deps.sessions == nil {
+		deps.sessions = newTestSessionRepository()
+	}
+	if deps.authenticator == nil {
+		deps.authenticator = services.NewPasswordAuthenticator(userRepo, deps.hasher, ru.NewRussianMessages())
+	}
+
+	tokenService := newTestTokenServiceWith(refreshRepo, deps.sessions, revocations)
+	return services.NewAuthService(userRepo, tokenService, deps.verification, deps.mfa, deps.loginGuard, deps.authConfig, deps.hasher, deps.authenticator, ru.NewRussianMessages())
+}
+
 func TestAuthService_Register_Success(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	req := &requests.RegisterRequest{
 		Email:    "test@example.com",
 		Password: "password123",
-		Role:     "employee",
 	}
 
 	// Настройка моков
 	mockRepo.On("EmailExists", mock.Anything, req.Email).Return(false, nil)
 	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
 	// Выполнение
-	tokenResponse, err := authService.Register(context.Background(), req)
+	tokenResponse, err := authService.Register(context.Background(), req, testClient)
 
 	// Проверка
 	require.NoError(t, err)
 	assert.NotEmpty(t, tokenResponse.Token)
+	assert.NotEmpty(t, tokenResponse.RefreshToken)
 	assert.Equal(t, req.Email, tokenResponse.User.Email)
-	assert.Equal(t, req.Role, tokenResponse.User.Role)
+	assert.Equal(t, models.RoleEmployee, tokenResponse.User.Role)
 	assert.NotEmpty(t, tokenResponse.User.ID)
 	assert.NotEmpty(t, tokenResponse.User.Created)
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_Register_EmailAlreadyExists(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	req := &requests.RegisterRequest{
 		Email:    "existing@example.com",
 		Password: "password123",
-		Role:     "employee",
 	}
 
 	// Настройка моков
 	mockRepo.On("EmailExists", mock.Anything, req.Email).Return(true, nil)
 
 	// Выполнение
-	tokenResponse, err := authService.Register(context.Background(), req)
+	tokenResponse, err := authService.Register(context.Background(), req, testClient)
 
 	// Проверка
 	assert.Error(t, err)
@@ -102,18 +304,71 @@ func TestAuthService_Register_EmailAlreadyExists(t *testing.T) {
 	assert.Contains(t, err.Error(), "уже существует")
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_Register_RegistrationMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		authConfig config.AuthConfig
+		email      string
+		wantErr    string
+	}{
+		{"Invite only", config.AuthConfig{RegistrationMode: config.RegistrationInviteOnly}, "user@corp.example", "только по приглашению"},
+		{"Foreign domain", config.AuthConfig{RegistrationMode: config.RegistrationDomain, RegistrationAllowedDomains: []string{"corp.example"}}, "user@gmail.com", "корпоративного email"},
+		{"Subdomain is not the domain", config.AuthConfig{RegistrationMode: config.RegistrationDomain, RegistrationAllowedDomains: []string{"corp.example"}}, "user@evil.corp.example", "корпоративного email"},
+		{"Corporate domain", config.AuthConfig{RegistrationMode: config.RegistrationDomain, RegistrationAllowedDomains: []string{"corp.example"}}, "user@CORP.example", ""},
+		{"Denied domain in open mode", config.AuthConfig{RegistrationMode: config.RegistrationOpen, RegistrationDeniedDomains: []string{"mailinator.com"}}, "user@mailinator.com", "домена запрещена"},
+		{"Denied domain wins over allowed", config.AuthConfig{RegistrationMode: config.RegistrationDomain, RegistrationAllowedDomains: []string{"corp.example"}, RegistrationDeniedDomains: []string{"corp.example"}}, "user@corp.example", "домена запрещена"},
+		{"Allowed list ignored in open mode", config.AuthConfig{RegistrationMode: config.RegistrationOpen, RegistrationAllowedDomains: []string{"corp.example"}}, "user@gmail.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			mockRepo := new(MockUserRepository)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, new(MockRevocationStore), authServiceDeps{authConfig: tt.authConfig})
+
+			req := &requests.RegisterRequest{Email: tt.email, Password: "password123"}
+
+			// Настройка моков - отклоненная регистрация не обращается к БД
+			if tt.wantErr == "" {
+				mockRepo.On("EmailExists", mock.Anything, req.Email).Return(false, nil)
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+				mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+			}
+
+			// Выполнение
+			tokenResponse, err := authService.Register(context.Background(), req, testClient)
+
+			// Проверка
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.NotEmpty(t, tokenResponse.Token)
+			} else {
+				require.Error(t, err)
+				assert.Nil(t, tokenResponse)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockRefreshRepo.AssertExpectations(t)
+		})
+	}
 }
 
 func TestAuthService_Register_RepositoryError(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	req := &requests.RegisterRequest{
 		Email:    "test@example.com",
 		Password: "password123",
-		Role:     "employee",
 	}
 
 	// Настройка моков
@@ -121,7 +376,7 @@ func TestAuthService_Register_RepositoryError(t *testing.T) {
 	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(errors.New("database error"))
 
 	// Выполнение
-	tokenResponse, err := authService.Register(context.Background(), req)
+	tokenResponse, err := authService.Register(context.Background(), req, testClient)
 
 	// Проверка
 	assert.Error(t, err)
@@ -129,13 +384,16 @@ func TestAuthService_Register_RepositoryError(t *testing.T) {
 	assert.Contains(t, err.Error(), "database error")
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_Login_Success(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	password := "password123"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), 4)
@@ -155,25 +413,31 @@ func TestAuthService_Login_Success(t *testing.T) {
 
 	// Настройка моков
 	mockRepo.On("GetByEmail", mock.Anything, req.Email).Return(existingUser, nil)
+	mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
 	// Выполнение
-	tokenResponse, err := authService.Login(context.Background(), req)
+	tokenResponse, err := authService.Login(context.Background(), req, testClient)
 
 	// Проверка
 	require.NoError(t, err)
 	assert.NotEmpty(t, tokenResponse.Token)
+	assert.NotEmpty(t, tokenResponse.RefreshToken)
+	assert.Equal(t, int64(testJWTConfig.AccessTokenTTL.Seconds()), tokenResponse.ExpiresIn)
 	assert.Equal(t, existingUser.ID, tokenResponse.User.ID)
 	assert.Equal(t, existingUser.Email, tokenResponse.User.Email)
 	assert.Equal(t, existingUser.Role, tokenResponse.User.Role)
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correct-password"), 4)
 
@@ -194,7 +458,7 @@ func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	mockRepo.On("GetByEmail", mock.Anything, req.Email).Return(existingUser, nil)
 
 	// Выполнение
-	tokenResponse, err := authService.Login(context.Background(), req)
+	tokenResponse, err := authService.Login(context.Background(), req, testClient)
 
 	// Проверка
 	assert.Error(t, err)
@@ -202,13 +466,16 @@ func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	assert.Contains(t, err.Error(), "Неверный email или пароль")
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	req := &requests.LoginRequest{
 		Email:    "nonexistent@example.com",
@@ -219,7 +486,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 	mockRepo.On("GetByEmail", mock.Anything, req.Email).Return(nil, nil)
 
 	// Выполнение
-	tokenResponse, err := authService.Login(context.Background(), req)
+	tokenResponse, err := authService.Login(context.Background(), req, testClient)
 
 	// Проверка
 	assert.Error(t, err)
@@ -227,13 +494,155 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "Неверный email или пароль")
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_Login_SuspendedAccount(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	user := &models.User{
+		ID:       uuid.New(),
+		Email:    "test@example.com",
+		Password: string(hashedPassword),
+		Role:     "employee",
+		Status:   models.StatusSuspended,
+	}
+
+	// Настройка моков
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: user.Email, Password: "password123"}, testClient)
+
+	// Проверка - токены не выпускаются
+	require.Error(t, err)
+	assert.Nil(t, tokenResponse)
+	assert.Contains(t, err.Error(), "Учетная запись приостановлена")
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_RehashesWeakPassword(t *testing.T) {
+	argon2idHasher := passwords.NewHasher(config.PasswordHashConfig{
+		Algorithm:         config.PasswordHashArgon2id,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}, 4)
+	currentHash, err := argon2idHasher.Hash("password123")
+	require.NoError(t, err)
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+
+	tests := []struct {
+		name       string
+		storedHash string
+		wantRehash bool
+	}{
+		{"bcrypt hash upgraded to argon2id", string(bcryptHash), true},
+		{"Current argon2id hash kept", currentHash, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			mockRepo := new(MockUserRepository)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, new(MockRevocationStore), authServiceDeps{hasher: argon2idHasher})
+
+			user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: tt.storedHash, Role: "employee"}
+
+			// Настройка моков
+			mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+			mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+			if tt.wantRehash {
+				mockRepo.On("UpdatePassword", mock.Anything, user.ID, mock.MatchedBy(func(hash string) bool {
+					ok, err := argon2idHasher.Verify(hash, "password123")
+					return strings.HasPrefix(hash, "$argon2id$") && ok && err == nil
+				})).Return(nil)
+			}
+
+			// Выполнение
+			tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: user.Email, Password: "password123"}, testClient)
+
+			// Проверка
+			require.NoError(t, err)
+			assert.NotEmpty(t, tokenResponse.Token)
+			if !tt.wantRehash {
+				mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Login_RehashFailureDoesNotBlockLogin(t *testing.T) {
+	// Подготовка - текущая стоимость bcrypt выше, чем у сохраненного хеша
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	hasher := passwords.NewHasher(config.PasswordHashConfig{Algorithm: config.PasswordHashBcrypt, Argon2Memory: 64, Argon2Iterations: 1, Argon2Parallelism: 1}, 5)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, new(MockRevocationStore), authServiceDeps{hasher: hasher})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword), Role: "employee"}
+
+	// Настройка моков
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockRepo.On("UpdatePassword", mock.Anything, user.ID, mock.AnythingOfType("string")).Return(errors.New("database unavailable"))
+	mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: user.Email, Password: "password123"}, testClient)
+
+	// Проверка - вход успешен со старым хешем
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokenResponse.Token)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_ValidateToken_DeactivatedAccount(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: "employee"}
+	token, err := authService.GenerateToken(user)
+	require.NoError(t, err)
+
+	// Сотрудник уволен после выпуска токена
+	deactivated := *user
+	deactivated.Status = models.StatusDeactivated
+
+	// Настройка моков
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil)
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(&deactivated, nil)
+
+	// Выполнение
+	validatedUser, err := authService.ValidateToken(context.Background(), token)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Nil(t, validatedUser)
+	assert.Contains(t, err.Error(), "Учетная запись деактивирована")
+
+	mockRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_ValidateToken_Success(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	user := &models.User{
 		ID:      uuid.New(),
@@ -247,6 +656,7 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	// Настройка моков
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil)
 	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
 
 	// Выполнение
@@ -259,13 +669,16 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 	assert.Equal(t, user.Role, validatedUser.Role)
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	invalidToken := "invalid.jwt.token"
 
@@ -277,13 +690,16 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 	assert.Nil(t, user)
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
 
 func TestAuthService_ValidateToken_UserNotFound(t *testing.T) {
 	// Подготовка
 	mockRepo := new(MockUserRepository)
-	messages := ru.NewRussianMessages()
-	authService := services.NewAuthService(mockRepo, "test-secret", 4, messages)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
 
 	user := &models.User{
 		ID:      uuid.New(),
@@ -297,6 +713,7 @@ func TestAuthService_ValidateToken_UserNotFound(t *testing.T) {
 	require.NoError(t, err)
 
 	// Настройка моков - пользователь не найден в БД
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil)
 	mockRepo.On("GetByID", mock.Anything, user.ID).Return(nil, nil)
 
 	// Выполнение
@@ -308,4 +725,266 @@ func TestAuthService_ValidateToken_UserNotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "Пользователь не найден")
 
 	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+// hashToken повторяет хеширование refresh токена в сервисе
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAuthService_Refresh_Success(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	user := &models.User{
+		ID:      uuid.New(),
+		Email:   "test@example.com",
+		Role:    "employee",
+		Created: time.Now(),
+	}
+	record := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: hashToken("old-refresh-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Created:   time.Now(),
+	}
+
+	// Настройка моков
+	mockRefreshRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	mockRefreshRepo.On("MarkUsed", mock.Anything, record.ID).Return(true, nil)
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	mockRefreshRepo.On("Create", mock.Anything, mock.MatchedBy(func(token *models.RefreshToken) bool {
+		// Новый токен должен остаться в той же цепочке
+		return token.FamilyID == record.FamilyID && token.UserID == user.ID
+	})).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Refresh(context.Background(), &requests.RefreshRequest{RefreshToken: "old-refresh-token"}, testClient)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokenResponse.Token)
+	assert.NotEmpty(t, tokenResponse.RefreshToken)
+	assert.NotEqual(t, "old-refresh-token", tokenResponse.RefreshToken)
+	assert.Equal(t, user.ID, tokenResponse.User.ID)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	usedAt := time.Now().Add(-time.Minute)
+	record := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: hashToken("stolen-refresh-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+		Created:   time.Now().Add(-time.Hour),
+	}
+
+	// Настройка моков - токен уже был использован
+	mockRefreshRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	mockRefreshRepo.On("RevokeFamily", mock.Anything, record.FamilyID).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Refresh(context.Background(), &requests.RefreshRequest{RefreshToken: "stolen-refresh-token"}, testClient)
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, tokenResponse)
+	assert.Contains(t, err.Error(), "уже был использован")
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_ConcurrentUseRevokesFamily(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	record := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: hashToken("raced-refresh-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Created:   time.Now(),
+	}
+
+	// Настройка моков - параллельный запрос успел погасить токен раньше
+	mockRefreshRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	mockRefreshRepo.On("MarkUsed", mock.Anything, record.ID).Return(false, nil)
+	mockRefreshRepo.On("RevokeFamily", mock.Anything, record.FamilyID).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Refresh(context.Background(), &requests.RefreshRequest{RefreshToken: "raced-refresh-token"}, testClient)
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, tokenResponse)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_InvalidToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *models.RefreshToken
+	}{
+		{"Unknown token", nil},
+		{"Expired token", &models.RefreshToken{
+			ID:        uuid.New(),
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			mockRepo := new(MockUserRepository)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			mockRevocations := new(MockRevocationStore)
+			authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+			// Настройка моков
+			if tt.record == nil {
+				mockRefreshRepo.On("GetByHash", mock.Anything, hashToken("some-token")).Return(nil, nil)
+			} else {
+				mockRefreshRepo.On("GetByHash", mock.Anything, hashToken("some-token")).Return(tt.record, nil)
+			}
+
+			// Выполнение
+			tokenResponse, err := authService.Refresh(context.Background(), &requests.RefreshRequest{RefreshToken: "some-token"}, testClient)
+
+			// Проверка
+			assert.Error(t, err)
+			assert.Nil(t, tokenResponse)
+			assert.Contains(t, err.Error(), "Недействительный refresh токен")
+
+			mockRefreshRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_ValidateToken_Revoked(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	user := &models.User{
+		ID:      uuid.New(),
+		Email:   "test@example.com",
+		Role:    "employee",
+		Created: time.Now(),
+	}
+
+	token, err := authService.GenerateToken(user)
+	require.NoError(t, err)
+
+	// Настройка моков - токен в списке отозванных
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(true, nil)
+
+	// Выполнение
+	validatedUser, err := authService.ValidateToken(context.Background(), token)
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, validatedUser)
+	assert.Contains(t, err.Error(), "Токен отозван")
+
+	mockRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_Logout_RevokesTokenAndFamily(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	user := &models.User{
+		ID:      uuid.New(),
+		Email:   "test@example.com",
+		Role:    "employee",
+		Created: time.Now(),
+	}
+
+	// Логин создает новую цепочку, которую должен отозвать выход
+	var familyID uuid.UUID
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	user.Password = string(hashedPassword)
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).
+		Run(func(args mock.Arguments) {
+			familyID = args.Get(1).(*models.RefreshToken).FamilyID
+		}).Return(nil)
+
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: user.Email, Password: "password123"}, testClient)
+	require.NoError(t, err)
+
+	// Настройка моков
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil)
+	mockRevocations.On("RevokeToken", mock.Anything, mock.AnythingOfType("uuid.UUID"), user.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRefreshRepo.On("RevokeFamily", mock.Anything, mock.MatchedBy(func(id uuid.UUID) bool {
+		return id == familyID
+	})).Return(nil)
+
+	// Выполнение
+	err = authService.Logout(context.Background(), tokenResponse.Token)
+
+	// Проверка
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	authService := newTestAuthService(mockRepo, mockRefreshRepo, mockRevocations)
+
+	userID := uuid.New()
+
+	// Настройка моков
+	mockRefreshRepo.On("RevokeByUser", mock.Anything, userID).Return(nil)
+	mockRevocations.On("RevokeAllForUser", mock.Anything, userID).Return(nil)
+
+	// Выполнение
+	err := authService.LogoutAll(context.Background(), userID)
+
+	// Проверка
+	require.NoError(t, err)
+
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
 }
@@ -0,0 +1,239 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/directory"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testEmployeeGroup = "CN=Portal Employees,OU=Groups,DC=corp,DC=example,DC=com"
+	testManagerGroup  = "CN=Portal Managers,OU=Groups,DC=corp,DC=example,DC=com"
+)
+
+// MockDirectory для тестирования
+type MockDirectory struct {
+	mock.Mock
+}
+
+func (m *MockDirectory) Authenticate(ctx context.Context, email, password string) (*directory.Entry, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*directory.Entry), args.Error(1)
+}
+
+// directoryAuthenticatorMocks зависимости проверки через каталог для тестов
+type directoryAuthenticatorMocks struct {
+	directory      *MockDirectory
+	userRepo       *MockUserRepository
+	roleChangeRepo *MockRoleChangeRepository
+}
+
+// newTestDirectoryAuthenticator создает проверку через каталог с группами сотрудников и менеджеров
+func newTestDirectoryAuthenticator() (services.Authenticator, *directoryAuthenticatorMocks) {
+	m := &directoryAuthenticatorMocks{
+		directory:      new(MockDirectory),
+		userRepo:       new(MockUserRepository),
+		roleChangeRepo: new(MockRoleChangeRepository),
+	}
+	ldapConfig := config.LDAPConfig{
+		EmployeeGroups: []string{testEmployeeGroup},
+		ManagerGroups:  []string{testManagerGroup},
+	}
+	return services.NewDirectoryAuthenticator(m.directory, m.userRepo, m.roleChangeRepo, ldapConfig, ru.NewRussianMessages()), m
+}
+
+func TestDirectoryAuthenticator_ProvisionsUserWithGroupRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		groups   []string
+		wantRole string
+	}{
+		{"Группа сотрудников", []string{testEmployeeGroup}, models.RoleEmployee},
+		{"Группа менеджеров без учета регистра DN", []string{"cn=portal managers,ou=groups,dc=corp,dc=example,dc=com"}, models.RoleManager},
+		{"Обе группы", []string{testEmployeeGroup, testManagerGroup}, models.RoleManager},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			authenticator, m := newTestDirectoryAuthenticator()
+			entry := &directory.Entry{DN: "CN=Ivanov,OU=People,DC=corp,DC=example,DC=com", Email: "ivanov@corp.example.com", Groups: tt.groups}
+
+			// Настройка моков
+			var created *models.User
+			var change *models.RoleChange
+			m.directory.On("Authenticate", mock.Anything, entry.Email, "directory-password").Return(entry, nil)
+			m.userRepo.On("GetByEmail", mock.Anything, entry.Email).Return(nil, nil)
+			m.userRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+				created = args.Get(1).(*models.User)
+			}).Return(nil)
+			m.roleChangeRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RoleChange")).Run(func(args mock.Arguments) {
+				change = args.Get(1).(*models.RoleChange)
+			}).Return(nil)
+
+			// Выполнение
+			user, err := authenticator.Authenticate(context.Background(), entry.Email, "directory-password")
+
+			// Проверка - пользователь без локального пароля, роль из каталога в журнале
+			require.NoError(t, err)
+			require.NotNil(t, user)
+			assert.Same(t, created, user)
+			assert.Equal(t, tt.wantRole, user.Role)
+			assert.Empty(t, user.Password)
+			assert.True(t, user.IsEmailVerified())
+			require.NotNil(t, change)
+			assert.Nil(t, change.OldRole)
+			assert.Nil(t, change.ChangedBy)
+			assert.Equal(t, models.RoleChangeSourceDirectory, change.Source)
+		})
+	}
+}
+
+func TestDirectoryAuthenticator_SyncsRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		groups     []string
+		wantRole   string
+		wantChange bool
+	}{
+		{"Повышение до менеджера", models.RoleEmployee, []string{testManagerGroup}, models.RoleManager, true},
+		{"Понижение до сотрудника", models.RoleManager, []string{testEmployeeGroup}, models.RoleEmployee, true},
+		{"Роль совпадает", models.RoleManager, []string{testManagerGroup}, models.RoleManager, false},
+		{"Администратор не меняется", models.RoleAdmin, []string{testEmployeeGroup}, models.RoleAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			authenticator, m := newTestDirectoryAuthenticator()
+			existing := &models.User{ID: uuid.New(), Email: "petrov@corp.example.com", Role: tt.role, Status: models.StatusActive}
+			entry := &directory.Entry{DN: "CN=Petrov,OU=People,DC=corp,DC=example,DC=com", Email: existing.Email, Groups: tt.groups}
+
+			// Настройка моков
+			m.directory.On("Authenticate", mock.Anything, existing.Email, "directory-password").Return(entry, nil)
+			m.userRepo.On("GetByEmail", mock.Anything, existing.Email).Return(existing, nil)
+			if tt.wantChange {
+				m.userRepo.On("UpdateRole", mock.Anything, existing.ID, tt.wantRole).Return(nil)
+				m.roleChangeRepo.On("Create", mock.Anything, mock.MatchedBy(func(change *models.RoleChange) bool {
+					return change.UserID == existing.ID && *change.OldRole == tt.role && change.NewRole == tt.wantRole
+				})).Return(nil)
+			}
+
+			// Выполнение
+			user, err := authenticator.Authenticate(context.Background(), existing.Email, "directory-password")
+
+			// Проверка
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRole, user.Role)
+			m.userRepo.AssertExpectations(t)
+			m.roleChangeRepo.AssertExpectations(t)
+			m.userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDirectoryAuthenticator_Rejected(t *testing.T) {
+	// Подготовка
+	authenticator, m := newTestDirectoryAuthenticator()
+	outsider := &directory.Entry{DN: "CN=Contractor,OU=People,DC=corp,DC=example,DC=com", Email: "contractor@corp.example.com", Groups: []string{"CN=Contractors,OU=Groups,DC=corp,DC=example,DC=com"}}
+
+	// Настройка моков
+	m.directory.On("Authenticate", mock.Anything, "ivanov@corp.example.com", "wrong-password").Return(nil, nil)
+	m.directory.On("Authenticate", mock.Anything, outsider.Email, "directory-password").Return(outsider, nil)
+
+	// Выполнение
+	wrongPassword, wrongPasswordErr := authenticator.Authenticate(context.Background(), "ivanov@corp.example.com", "wrong-password")
+	noGroup, noGroupErr := authenticator.Authenticate(context.Background(), outsider.Email, "directory-password")
+
+	// Проверка - оба отказа выглядят как неверный пароль, пользователь портала не создается
+	require.NoError(t, wrongPasswordErr)
+	require.NoError(t, noGroupErr)
+	assert.Nil(t, wrongPassword)
+	assert.Nil(t, noGroup)
+	m.userRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_ThroughDirectory(t *testing.T) {
+	// Подготовка
+	userRepo := new(MockUserRepository)
+	refreshRepo := new(MockRefreshTokenRepository)
+	dir := new(MockDirectory)
+	roleChangeRepo := new(MockRoleChangeRepository)
+	messages := ru.NewRussianMessages()
+	hasher := newTestHasher()
+
+	ldapConfig := config.LDAPConfig{ManagerGroups: []string{testManagerGroup}}
+	authenticator := services.NewChainAuthenticator([]services.Authenticator{
+		services.NewPasswordAuthenticator(userRepo, hasher, messages),
+		services.NewDirectoryAuthenticator(dir, userRepo, roleChangeRepo, ldapConfig, messages),
+	}, messages)
+	authService := newTestAuthServiceWith(userRepo, refreshRepo, new(MockRevocationStore), authServiceDeps{hasher: hasher, authenticator: authenticator})
+
+	// Пользователь уже входил через каталог: локального пароля нет
+	existing := &models.User{ID: uuid.New(), Email: "sidorov@corp.example.com", Role: models.RoleEmployee, Status: models.StatusActive}
+	req := &requests.LoginRequest{Email: existing.Email, Password: "directory-password"}
+
+	// Настройка моков
+	userRepo.On("GetByEmail", mock.Anything, existing.Email).Return(existing, nil)
+	dir.On("Authenticate", mock.Anything, existing.Email, req.Password).Return(&directory.Entry{Email: existing.Email}, nil)
+	refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	// Выполнение
+	response, err := authService.Login(context.Background(), req, testClient)
+
+	// Проверка - пустой хеш отклонен локальной проверкой, пароль принят каталогом
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	assert.Equal(t, existing.ID, response.User.ID)
+	dir.AssertExpectations(t)
+}
+
+func TestAuthService_Login_DirectoryUnavailable(t *testing.T) {
+	// Подготовка
+	userRepo := new(MockUserRepository)
+	dir := new(MockDirectory)
+	messages := ru.NewRussianMessages()
+	hasher := newTestHasher()
+	loginGuard, attempts := newTestLoginGuardWithRepo()
+
+	authenticator := services.NewChainAuthenticator([]services.Authenticator{
+		services.NewPasswordAuthenticator(userRepo, hasher, messages),
+		services.NewDirectoryAuthenticator(dir, userRepo, new(MockRoleChangeRepository), config.LDAPConfig{}, messages),
+	}, messages)
+	authService := newTestAuthServiceWith(userRepo, new(MockRefreshTokenRepository), new(MockRevocationStore), authServiceDeps{
+		hasher:        hasher,
+		authenticator: authenticator,
+		loginGuard:    loginGuard,
+	})
+	req := &requests.LoginRequest{Email: "sidorov@corp.example.com", Password: "directory-password"}
+
+	// Настройка моков
+	userRepo.On("GetByEmail", mock.Anything, req.Email).Return(nil, nil)
+	dir.On("Authenticate", mock.Anything, req.Email, req.Password).Return(nil, errors.New("ldap: connection refused"))
+
+	// Выполнение
+	response, err := authService.Login(context.Background(), req, testClient)
+
+	// Проверка - общая ошибка, но недоступность каталога не засчитывается как неверный пароль
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "Неверный email или пароль")
+	attempt, err := attempts.Get(context.Background(), "email:"+req.Email)
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+}
@@ -0,0 +1,290 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIClientRepository для тестирования
+type MockAPIClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIClientRepository) Create(ctx context.Context, client *models.APIClient) error {
+	args := m.Called(ctx, client)
+	return args.Error(0)
+}
+
+func (m *MockAPIClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIClient, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIClient), args.Error(1)
+}
+
+func (m *MockAPIClientRepository) List(ctx context.Context) ([]models.APIClient, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIClient), args.Error(1)
+}
+
+func (m *MockAPIClientRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+const testClientSecret = "client-secret"
+
+// newTestClientService создает ClientService с тестовыми зависимостями
+func newTestClientService() (services.ClientService, *MockAPIClientRepository) {
+	clientRepo := new(MockAPIClientRepository)
+	revocations := new(MockRevocationStore)
+	revocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil).Maybe()
+	tokens := newTestTokenService(new(MockRefreshTokenRepository), revocations)
+
+	return services.NewClientService(clientRepo, tokens, ru.NewRussianMessages()), clientRepo
+}
+
+// newTestAPIClient создает активного API клиента с секретом testClientSecret
+func newTestAPIClient(scopes ...models.Permission) *models.APIClient {
+	hash := sha256.Sum256([]byte(testClientSecret))
+	return &models.APIClient{
+		ID:         uuid.New(),
+		Name:       "billing-service",
+		SecretHash: hex.EncodeToString(hash[:]),
+		Scope:      models.FormatScope(scopes),
+		Created:    time.Now(),
+	}
+}
+
+// clientCredentials создает запрос client_credentials для клиента
+func clientCredentials(client *models.APIClient, secret, scope string) *requests.ClientTokenRequest {
+	return &requests.ClientTokenRequest{
+		GrantType:    services.GrantTypeClientCredentials,
+		ClientID:     client.ID.String(),
+		ClientSecret: secret,
+		Scope:        scope,
+	}
+}
+
+// requireOAuthError проверяет код ошибки эндпоинта токенов
+func requireOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *services.OAuthError
+	require.True(t, errors.As(err, &oauthErr))
+	assert.Equal(t, code, oauthErr.Code)
+}
+
+func TestClientService_IssueToken_GrantsScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		scope     string
+		wantScope string
+	}{
+		{"Без scope выдаются все права клиента", "", "tokens:validate courses:read"},
+		{"Запрошенная часть прав", "courses:read", "courses:read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, clientRepo := newTestClientService()
+			client := newTestAPIClient(models.PermissionTokensValidate, models.PermissionCoursesRead)
+
+			// Настройка моков - клиент читается при выдаче и при проверке токена
+			clientRepo.On("GetByID", mock.Anything, client.ID).Return(client, nil)
+
+			// Выполнение
+			response, err := service.IssueToken(context.Background(), clientCredentials(client, testClientSecret, tt.scope))
+
+			// Проверка
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer", response.TokenType)
+			assert.Equal(t, tt.wantScope, response.Scope)
+			assert.Equal(t, int64(testJWTConfig.AccessTokenTTL.Seconds()), response.ExpiresIn)
+
+			authenticated, err := service.ValidateToken(context.Background(), response.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, client.ID, authenticated.ID)
+			assert.Equal(t, models.ParseScope(tt.wantScope), authenticated.Scopes)
+
+			clientRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientService_IssueToken_Errors(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		prepare  func(client *models.APIClient, req *requests.ClientTokenRequest)
+		wantCode string
+	}{
+		{"Неверный секрет", func(_ *models.APIClient, req *requests.ClientTokenRequest) {
+			req.ClientSecret = "wrong-secret"
+		}, services.OAuthInvalidClient},
+		{"Отозванный клиент", func(client *models.APIClient, _ *requests.ClientTokenRequest) {
+			client.RevokedAt = &revokedAt
+		}, services.OAuthInvalidClient},
+		{"Право, которого нет у клиента", func(_ *models.APIClient, req *requests.ClientTokenRequest) {
+			req.Scope = "courses:read team:progress:read"
+		}, services.OAuthInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, clientRepo := newTestClientService()
+			client := newTestAPIClient(models.PermissionCoursesRead)
+			req := clientCredentials(client, testClientSecret, "")
+			tt.prepare(client, req)
+
+			// Настройка моков
+			clientRepo.On("GetByID", mock.Anything, client.ID).Return(client, nil)
+
+			// Выполнение
+			response, err := service.IssueToken(context.Background(), req)
+
+			// Проверка
+			assert.Nil(t, response)
+			requireOAuthError(t, err, tt.wantCode)
+
+			clientRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientService_IssueToken_InvalidRequest(t *testing.T) {
+	// Подготовка
+	service, clientRepo := newTestClientService()
+	client := newTestAPIClient(models.PermissionCoursesRead)
+
+	unsupported := clientCredentials(client, testClientSecret, "")
+	unsupported.GrantType = "password"
+	missingSecret := clientCredentials(client, "", "")
+	unknownClient := clientCredentials(client, testClientSecret, "")
+	unknownClient.ClientID = "not-a-uuid"
+
+	// Выполнение и проверка - до хранилища такие запросы не доходят
+	_, err := service.IssueToken(context.Background(), unsupported)
+	requireOAuthError(t, err, services.OAuthUnsupportedGrantType)
+
+	_, err = service.IssueToken(context.Background(), missingSecret)
+	requireOAuthError(t, err, services.OAuthInvalidRequest)
+
+	_, err = service.IssueToken(context.Background(), unknownClient)
+	requireOAuthError(t, err, services.OAuthInvalidClient)
+
+	clientRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestClientService_Create_StoresSecretHash(t *testing.T) {
+	// Подготовка
+	service, clientRepo := newTestClientService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	req := &requests.CreateClientRequest{
+		Name:   "billing-service",
+		Scopes: []string{"courses:read", "tokens:validate", "courses:read"},
+	}
+
+	// Настройка моков
+	var stored *models.APIClient
+	clientRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.APIClient")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*models.APIClient)
+	}).Return(nil)
+
+	// Выполнение
+	response, err := service.Create(context.Background(), admin, req)
+
+	// Проверка - в БД попадает только хеш секрета, повторы прав убираются
+	require.NoError(t, err)
+	require.NotEmpty(t, response.ClientSecret)
+	hash := sha256.Sum256([]byte(response.ClientSecret))
+	assert.Equal(t, hex.EncodeToString(hash[:]), stored.SecretHash)
+	assert.NotEqual(t, response.ClientSecret, stored.SecretHash)
+	assert.Equal(t, "courses:read tokens:validate", stored.Scope)
+	assert.Equal(t, admin.ID, *stored.CreatedBy)
+	assert.Equal(t, stored.ID, response.Client.ID)
+
+	clientRepo.AssertExpectations(t)
+}
+
+func TestClientService_ValidateToken_RevokedClient(t *testing.T) {
+	// Подготовка
+	service, clientRepo := newTestClientService()
+	client := newTestAPIClient(models.PermissionTokensValidate)
+	revoked := *client
+	revokedAt := time.Now()
+	revoked.RevokedAt = &revokedAt
+
+	// Настройка моков - клиента отзывают после выдачи токена
+	clientRepo.On("GetByID", mock.Anything, client.ID).Return(client, nil).Once()
+	clientRepo.On("GetByID", mock.Anything, client.ID).Return(&revoked, nil).Once()
+
+	response, err := service.IssueToken(context.Background(), clientCredentials(client, testClientSecret, ""))
+	require.NoError(t, err)
+
+	// Выполнение
+	authenticated, err := service.ValidateToken(context.Background(), response.AccessToken)
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, authenticated)
+
+	clientRepo.AssertExpectations(t)
+}
+
+func TestClientService_Revoke_UnknownClient(t *testing.T) {
+	// Подготовка
+	service, clientRepo := newTestClientService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	clientID := uuid.New()
+
+	// Настройка моков
+	clientRepo.On("GetByID", mock.Anything, clientID).Return(nil, nil)
+
+	// Выполнение
+	err := service.Revoke(context.Background(), admin, clientID)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "не найден")
+	clientRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ValidateToken_RejectsClientToken(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRevocations := new(MockRevocationStore)
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil).Maybe()
+	authService := newTestAuthService(mockRepo, new(MockRefreshTokenRepository), mockRevocations)
+	tokens := newTestTokenService(new(MockRefreshTokenRepository), mockRevocations)
+
+	token, err := tokens.GenerateClientToken(newTestAPIClient(models.PermissionCoursesRead), []models.Permission{models.PermissionCoursesRead})
+	require.NoError(t, err)
+
+	// Выполнение
+	user, err := authService.ValidateToken(context.Background(), token)
+
+	// Проверка - токен клиента не должен приниматься как токен пользователя
+	assert.Error(t, err)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,284 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockEmailVerificationRepository для тестирования
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationRepository) Create(ctx context.Context, token *models.EmailVerificationToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationRepository) GetByHash(ctx context.Context, tokenHash string) (*models.EmailVerificationToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailVerificationToken), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockEmailVerificationService для тестирования
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) ResendVerification(ctx context.Context, req *requests.ResendVerificationRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+// newTestEmailVerificationService создает EmailVerificationService с тестовыми зависимостями
+func newTestEmailVerificationService() (services.EmailVerificationService, *MockUserRepository, *MockEmailVerificationRepository, *MockMailSender) {
+	userRepo := new(MockUserRepository)
+	verificationRepo := new(MockEmailVerificationRepository)
+	mailer := new(MockMailSender)
+	verificationConfig := config.EmailVerificationConfig{
+		TokenTTL: 24 * time.Hour,
+		URL:      "http://auth.local/api/v1/verify-email",
+	}
+
+	service := services.NewEmailVerificationService(userRepo, verificationRepo, mailer, verificationConfig, ru.NewRussianMessages())
+	return service, userRepo, verificationRepo, mailer
+}
+
+func TestEmailVerificationService_SendVerification_SendsLink(t *testing.T) {
+	// Подготовка
+	service, userRepo, verificationRepo, mailer := newTestEmailVerificationService()
+
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: "employee"}
+
+	var sent mail.Message
+	var stored *models.EmailVerificationToken
+
+	// Настройка моков
+	verificationRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.EmailVerificationToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.EmailVerificationToken) }).
+		Return(nil)
+	mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mail.Message) }).
+		Return(nil)
+
+	// Выполнение
+	err := service.SendVerification(context.Background(), user)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, sent.To)
+	assert.Equal(t, "Подтверждение email на Портале Обучения", sent.Subject)
+
+	// В письме токен, в БД только его хеш
+	start := strings.Index(sent.Body, "http://auth.local/api/v1/verify-email?token=")
+	require.GreaterOrEqual(t, start, 0)
+	link, err := url.Parse(strings.Fields(sent.Body[start:])[0])
+	require.NoError(t, err)
+	token := link.Query().Get("token")
+	assert.NotEmpty(t, token)
+	assert.Equal(t, hashToken(token), stored.TokenHash)
+	assert.Equal(t, user.ID, stored.UserID)
+
+	userRepo.AssertExpectations(t)
+	verificationRepo.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func TestEmailVerificationService_VerifyEmail_Success(t *testing.T) {
+	// Подготовка
+	service, userRepo, verificationRepo, mailer := newTestEmailVerificationService()
+
+	token := "verification-token"
+	record := &models.EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// Настройка моков
+	verificationRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	verificationRepo.On("MarkUsed", mock.Anything, record.ID).Return(true, nil)
+	userRepo.On("MarkEmailVerified", mock.Anything, record.UserID).Return(nil)
+	verificationRepo.On("InvalidateForUser", mock.Anything, record.UserID).Return(nil)
+
+	// Выполнение
+	err := service.VerifyEmail(context.Background(), token)
+
+	// Проверка
+	require.NoError(t, err)
+
+	userRepo.AssertExpectations(t)
+	verificationRepo.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func TestEmailVerificationService_VerifyEmail_InvalidToken(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name   string
+		record *models.EmailVerificationToken
+	}{
+		{name: "не найден", record: nil},
+		{name: "истек", record: &models.EmailVerificationToken{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}},
+		{name: "использован", record: &models.EmailVerificationToken{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Подготовка
+			service, userRepo, verificationRepo, mailer := newTestEmailVerificationService()
+
+			// Настройка моков
+			if tc.record == nil {
+				verificationRepo.On("GetByHash", mock.Anything, hashToken("bad-token")).Return(nil, nil)
+			} else {
+				verificationRepo.On("GetByHash", mock.Anything, hashToken("bad-token")).Return(tc.record, nil)
+			}
+
+			// Выполнение
+			err := service.VerifyEmail(context.Background(), "bad-token")
+
+			// Проверка - email не подтверждается
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "недействительна или устарела")
+
+			userRepo.AssertExpectations(t)
+			verificationRepo.AssertExpectations(t)
+			mailer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailVerificationService_ResendVerification_AlreadyVerified(t *testing.T) {
+	// Подготовка
+	service, userRepo, verificationRepo, mailer := newTestEmailVerificationService()
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", EmailVerifiedAt: &verifiedAt}
+
+	// Настройка моков
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	// Выполнение
+	err := service.ResendVerification(context.Background(), &requests.ResendVerificationRequest{Email: user.Email})
+
+	// Проверка - повторное письмо не отправляется
+	require.NoError(t, err)
+
+	userRepo.AssertExpectations(t)
+	verificationRepo.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func TestAuthService_Register_VerificationRequired(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	mockVerification := new(MockEmailVerificationService)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{
+		verification: mockVerification,
+		authConfig:   config.AuthConfig{RequireEmailVerification: true},
+	})
+
+	req := &requests.RegisterRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	// Настройка моков
+	mockRepo.On("EmailExists", mock.Anything, req.Email).Return(false, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	mockVerification.On("SendVerification", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Register(context.Background(), req, testClient)
+
+	// Проверка - аккаунт создан, но токены не выданы
+	require.NoError(t, err)
+	assert.True(t, tokenResponse.EmailVerificationRequired)
+	assert.Empty(t, tokenResponse.Token)
+	assert.Empty(t, tokenResponse.RefreshToken)
+	assert.Equal(t, req.Email, tokenResponse.User.Email)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+	mockVerification.AssertExpectations(t)
+}
+
+func TestAuthService_Login_EmailNotVerified(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	mockVerification := new(MockEmailVerificationService)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{
+		verification: mockVerification,
+		authConfig:   config.AuthConfig{RequireEmailVerification: true},
+	})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	existingUser := &models.User{
+		ID:       uuid.New(),
+		Email:    "test@example.com",
+		Password: string(hashedPassword),
+		Role:     "employee",
+	}
+
+	// Настройка моков
+	mockRepo.On("GetByEmail", mock.Anything, existingUser.Email).Return(existingUser, nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: existingUser.Email, Password: "password123"}, testClient)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Nil(t, tokenResponse)
+	assert.Contains(t, err.Error(), "Подтвердите email")
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+	mockVerification.AssertExpectations(t)
+}
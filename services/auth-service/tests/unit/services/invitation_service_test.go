@@ -0,0 +1,286 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockInvitationRepository для тестирования
+type MockInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	args := m.Called(ctx, invitation)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) MarkAccepted(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvitationRepository) ListByEmail(ctx context.Context, email string) ([]models.Invitation, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Invitation), args.Error(1)
+}
+
+// invitationServiceMocks зависимости InvitationService для тестов
+type invitationServiceMocks struct {
+	userRepo       *MockUserRepository
+	invitationRepo *MockInvitationRepository
+	roleChangeRepo *MockRoleChangeRepository
+	refreshRepo    *MockRefreshTokenRepository
+	mailer         *MockMailSender
+}
+
+// newTestInvitationService создает InvitationService с настоящим AuthService на тестовых зависимостях
+func newTestInvitationService() (services.InvitationService, *invitationServiceMocks) {
+	m := &invitationServiceMocks{
+		userRepo:       new(MockUserRepository),
+		invitationRepo: new(MockInvitationRepository),
+		roleChangeRepo: new(MockRoleChangeRepository),
+		refreshRepo:    new(MockRefreshTokenRepository),
+		mailer:         new(MockMailSender),
+	}
+	invitationConfig := config.InvitationConfig{
+		TokenTTL: 72 * time.Hour,
+		URL:      "http://portal.local/accept-invitation",
+	}
+
+	authService := newTestAuthService(m.userRepo, m.refreshRepo, new(MockRevocationStore))
+	service := services.NewInvitationService(m.userRepo, m.invitationRepo, m.roleChangeRepo, authService, m.mailer, invitationConfig, ru.NewRussianMessages())
+	return service, m
+}
+
+func (m *invitationServiceMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.invitationRepo.AssertExpectations(t)
+	m.roleChangeRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+}
+
+func TestInvitationService_Invite_SendsLink(t *testing.T) {
+	// Подготовка
+	service, m := newTestInvitationService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+
+	var sent mail.Message
+	var stored *models.Invitation
+
+	// Настройка моков
+	m.userRepo.On("EmailExists", mock.Anything, "manager@example.com").Return(false, nil)
+	m.invitationRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Invitation")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.Invitation) }).
+		Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mail.Message) }).
+		Return(nil)
+
+	// Выполнение
+	invitation, err := service.Invite(context.Background(), admin, &requests.CreateInvitationRequest{Email: "manager@example.com", Role: models.RoleManager})
+
+	// Проверка - в письме токен, в БД только его хеш
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleManager, invitation.Role)
+	assert.Equal(t, admin.ID, *invitation.InvitedBy)
+	assert.Equal(t, "manager@example.com", sent.To)
+
+	start := strings.Index(sent.Body, "http://portal.local/accept-invitation?token=")
+	require.GreaterOrEqual(t, start, 0)
+	link, err := url.Parse(strings.Fields(sent.Body[start:])[0])
+	require.NoError(t, err)
+	assert.Equal(t, hashToken(link.Query().Get("token")), stored.TokenHash)
+
+	m.assertExpectations(t)
+}
+
+func TestInvitationService_Invite_ManagerInvitesEmployee(t *testing.T) {
+	// Подготовка
+	service, m := newTestInvitationService()
+	manager := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+
+	// Настройка моков
+	m.userRepo.On("EmailExists", mock.Anything, "employee@example.com").Return(false, nil)
+	m.invitationRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Invitation")).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).Return(nil)
+
+	// Выполнение - роль не указана
+	invitation, err := service.Invite(context.Background(), manager, &requests.CreateInvitationRequest{Email: "employee@example.com"})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleEmployee, invitation.Role)
+	assert.Equal(t, manager.ID, *invitation.InvitedBy)
+
+	m.assertExpectations(t)
+}
+
+func TestInvitationService_Invite_ManagerCannotGrantRole(t *testing.T) {
+	// Подготовка
+	service, m := newTestInvitationService()
+	manager := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+
+	// Выполнение
+	invitation, err := service.Invite(context.Background(), manager, &requests.CreateInvitationRequest{Email: "lead@example.com", Role: models.RoleManager})
+
+	// Проверка - приглашение не создается и письмо не отправляется
+	require.Error(t, err)
+	assert.Nil(t, invitation)
+	assert.Contains(t, err.Error(), "Недостаточно прав")
+
+	m.assertExpectations(t)
+}
+
+func TestInvitationService_Invite_ExistingUser(t *testing.T) {
+	// Подготовка
+	service, m := newTestInvitationService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+
+	// Настройка моков
+	m.userRepo.On("EmailExists", mock.Anything, "existing@example.com").Return(true, nil)
+
+	// Выполнение
+	invitation, err := service.Invite(context.Background(), admin, &requests.CreateInvitationRequest{Email: "existing@example.com", Role: models.RoleManager})
+
+	// Проверка
+	require.Error(t, err)
+	assert.Nil(t, invitation)
+	assert.Contains(t, err.Error(), "уже существует")
+
+	m.assertExpectations(t)
+}
+
+func TestInvitationService_Accept_CreatesManager(t *testing.T) {
+	// Подготовка
+	service, m := newTestInvitationService()
+	inviterID := uuid.New()
+	invitation := &models.Invitation{
+		ID:        uuid.New(),
+		Email:     "manager@example.com",
+		Role:      models.RoleManager,
+		TokenHash: hashToken("invite-token"),
+		InvitedBy: &inviterID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	var created *models.User
+	var recorded *models.RoleChange
+
+	// Настройка моков
+	m.invitationRepo.On("GetByHash", mock.Anything, invitation.TokenHash).Return(invitation, nil)
+	m.userRepo.On("EmailExists", mock.Anything, invitation.Email).Return(false, nil)
+	m.userRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*models.User) }).
+		Return(nil)
+	m.refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	m.invitationRepo.On("MarkAccepted", mock.Anything, invitation.ID).Return(true, nil)
+	m.roleChangeRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RoleChange")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.RoleChange) }).
+		Return(nil)
+
+	// Выполнение
+	response, err := service.Accept(context.Background(), &requests.AcceptInvitationRequest{Token: "invite-token", Password: "password123"}, testClient)
+
+	// Проверка - роль из приглашения, email подтвержден, назначение роли в журнале
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	require.NotNil(t, created)
+	assert.Equal(t, models.RoleManager, created.Role)
+	assert.True(t, created.IsEmailVerified())
+	require.NotNil(t, recorded)
+	assert.Nil(t, recorded.OldRole)
+	assert.Equal(t, created.ID, recorded.UserID)
+	assert.Equal(t, inviterID, *recorded.ChangedBy)
+	assert.Equal(t, models.RoleChangeSourceInvitation, recorded.Source)
+
+	m.assertExpectations(t)
+}
+
+func TestInvitationService_Accept_PasswordMatchesEmail(t *testing.T) {
+	// Подготовка
+	service, m := newTestInvitationService()
+	invitation := &models.Invitation{
+		ID:        uuid.New(),
+		Email:     "manager@example.com",
+		Role:      models.RoleManager,
+		TokenHash: hashToken("invite-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// Настройка моков
+	m.invitationRepo.On("GetByHash", mock.Anything, invitation.TokenHash).Return(invitation, nil)
+
+	// Выполнение
+	response, err := service.Accept(context.Background(), &requests.AcceptInvitationRequest{Token: "invite-token", Password: "Manager@Example.com"}, testClient)
+
+	// Проверка - аккаунт не создается, приглашение остается действительным
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "не должен совпадать с email")
+
+	m.assertExpectations(t)
+}
+
+func TestInvitationService_Accept_InvalidToken(t *testing.T) {
+	acceptedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		invitation *models.Invitation
+	}{
+		{"Unknown token", nil},
+		{"Expired invitation", &models.Invitation{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}},
+		{"Accepted invitation", &models.Invitation{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), AcceptedAt: &acceptedAt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestInvitationService()
+
+			// Настройка моков
+			if tt.invitation == nil {
+				m.invitationRepo.On("GetByHash", mock.Anything, hashToken("invite-token")).Return(nil, nil)
+			} else {
+				m.invitationRepo.On("GetByHash", mock.Anything, hashToken("invite-token")).Return(tt.invitation, nil)
+			}
+
+			// Выполнение
+			response, err := service.Accept(context.Background(), &requests.AcceptInvitationRequest{Token: "invite-token", Password: "password123"}, testClient)
+
+			// Проверка - аккаунт не создается
+			require.Error(t, err)
+			assert.Nil(t, response)
+			assert.Contains(t, err.Error(), "Приглашение недействительно")
+
+			m.assertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testClientIP адрес клиента в тестах входа
+const testClientIP = "192.0.2.10"
+
+// testClient клиент, от которого приходят запросы в тестах входа
+var testClient = models.ClientInfo{IP: testClientIP, UserAgent: "test-agent/1.0"}
+
+// testLockoutConfig настройки блокировки для тестов
+var testLockoutConfig = config.LockoutConfig{
+	Store:         "memory",
+	MaxFailures:   3,
+	IPMaxFailures: 5,
+	BaseDuration:  time.Minute,
+	MaxDuration:   10 * time.Minute,
+	FailureWindow: time.Hour,
+}
+
+// newTestLoginGuard создает LoginGuard со счетчиками в памяти
+func newTestLoginGuard() services.LoginGuard {
+	return services.NewLoginGuard(repositories.NewMemoryLoginAttemptRepository(), testLockoutConfig, ru.NewRussianMessages())
+}
+
+// newTestLoginGuardWithRepo создает LoginGuard и возвращает хранилище для проверки блокировок
+func newTestLoginGuardWithRepo() (services.LoginGuard, repositories.LoginAttemptRepository) {
+	repo := repositories.NewMemoryLoginAttemptRepository()
+	return services.NewLoginGuard(repo, testLockoutConfig, ru.NewRussianMessages()), repo
+}
+
+func TestLoginGuard_LocksAccountAfterMaxFailures(t *testing.T) {
+	// Подготовка
+	guard := newTestLoginGuard()
+	ctx := context.Background()
+
+	// Выполнение - до порога вход разрешен, после порога заблокирован
+	for i := 0; i < testLockoutConfig.MaxFailures-1; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "user@example.com", testClientIP))
+		require.NoError(t, guard.Check(ctx, "user@example.com", testClientIP))
+	}
+	require.NoError(t, guard.RecordFailure(ctx, "user@example.com", testClientIP))
+	err := guard.Check(ctx, "USER@example.com", "198.51.100.1")
+
+	// Проверка - блокировка привязана к аккаунту, а не к IP, и не зависит от регистра email
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Аккаунт временно заблокирован")
+
+	// Другой аккаунт с того же IP не заблокирован
+	assert.NoError(t, guard.Check(ctx, "other@example.com", testClientIP))
+}
+
+func TestLoginGuard_ExponentialBackoff(t *testing.T) {
+	// Подготовка
+	guard, repo := newTestLoginGuardWithRepo()
+	ctx := context.Background()
+
+	for i := 0; i < testLockoutConfig.MaxFailures-1; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "user@example.com", ""))
+	}
+
+	// Выполнение и проверка - каждая следующая ошибка удваивает блокировку до MaxDuration
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute} {
+		before := time.Now()
+		require.NoError(t, guard.RecordFailure(ctx, "user@example.com", ""))
+
+		attempt, err := repo.Get(ctx, "email:user@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, attempt.LockedUntil)
+		assert.WithinDuration(t, before.Add(expected), *attempt.LockedUntil, time.Second)
+	}
+}
+
+func TestLoginGuard_LocksClientIP(t *testing.T) {
+	// Подготовка
+	guard := newTestLoginGuard()
+	ctx := context.Background()
+
+	// Выполнение - перебор по разным аккаунтам с одного IP
+	for i := 0; i < testLockoutConfig.IPMaxFailures; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, uuid.NewString()+"@example.com", testClientIP))
+	}
+	err := guard.Check(ctx, "fresh@example.com", testClientIP)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "с вашего адреса")
+	assert.NoError(t, guard.Check(ctx, "fresh@example.com", "198.51.100.1"))
+}
+
+func TestLoginGuard_SuccessAndUnlockResetAccount(t *testing.T) {
+	// Подготовка
+	guard := newTestLoginGuard()
+	ctx := context.Background()
+
+	for i := 0; i < testLockoutConfig.MaxFailures; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "locked@example.com", ""))
+	}
+	for i := 0; i < testLockoutConfig.MaxFailures-1; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "almost@example.com", ""))
+	}
+	require.Error(t, guard.Check(ctx, "locked@example.com", ""))
+
+	// Выполнение
+	require.NoError(t, guard.Unlock(ctx, "locked@example.com"))
+	require.NoError(t, guard.RecordSuccess(ctx, "almost@example.com"))
+	require.NoError(t, guard.RecordFailure(ctx, "almost@example.com", ""))
+
+	// Проверка - счетчики начались заново
+	assert.NoError(t, guard.Check(ctx, "locked@example.com", ""))
+	assert.NoError(t, guard.Check(ctx, "almost@example.com", ""))
+}
+
+func TestAuthService_Login_LockedAccountSkipsPasswordCheck(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	guard := newTestLoginGuard()
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{loginGuard: guard})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	existingUser := &models.User{
+		ID:       uuid.New(),
+		Email:    "test@example.com",
+		Password: string(hashedPassword),
+		Role:     models.RoleEmployee,
+	}
+
+	// Настройка моков - пользователь загружается только для неудачных попыток до блокировки
+	mockRepo.On("GetByEmail", mock.Anything, existingUser.Email).Return(existingUser, nil).Times(testLockoutConfig.MaxFailures)
+
+	for i := 0; i < testLockoutConfig.MaxFailures; i++ {
+		_, err := authService.Login(context.Background(), &requests.LoginRequest{Email: existingUser.Email, Password: "wrong-password"}, testClient)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Неверный email или пароль")
+	}
+
+	// Выполнение - даже верный пароль не проверяется, пока действует блокировка
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: existingUser.Email, Password: "password123"}, testClient)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Nil(t, tokenResponse)
+	assert.Contains(t, err.Error(), "Аккаунт временно заблокирован")
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
@@ -0,0 +1,514 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/models/responses"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/totp"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockMFARepository для тестирования
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserMFA, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserMFA), args.Error(1)
+}
+
+func (m *MockMFARepository) SavePending(ctx context.Context, mfa *models.UserMFA) error {
+	args := m.Called(ctx, mfa)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) Confirm(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []models.MFARecoveryCode) error {
+	args := m.Called(ctx, userID, codes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockMFAChallengeRepository для тестирования
+type MockMFAChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockMFAChallengeRepository) Create(ctx context.Context, challenge *models.MFAChallenge) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockMFAChallengeRepository) GetByHash(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockMFAService для тестирования
+type MockMFAService struct {
+	mock.Mock
+}
+
+func (m *MockMFAService) Challenge(ctx context.Context, user *models.User) (*responses.TokenResponse, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.TokenResponse), args.Error(1)
+}
+
+func (m *MockMFAService) VerifyChallenge(ctx context.Context, req *requests.MFALoginRequest) (*services.MFAVerification, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.MFAVerification), args.Error(1)
+}
+
+func (m *MockMFAService) Enroll(ctx context.Context, userID uuid.UUID) (*responses.MFAEnrollmentResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.MFAEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockMFAService) EnrollWithChallenge(ctx context.Context, req *requests.MFAEnrollRequest) (*responses.MFAEnrollmentResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.MFAEnrollmentResponse), args.Error(1)
+}
+
+func (m *MockMFAService) Confirm(ctx context.Context, userID uuid.UUID, req *requests.MFAConfirmRequest) (*responses.MFARecoveryCodesResponse, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*responses.MFARecoveryCodesResponse), args.Error(1)
+}
+
+// mfaServiceMocks зависимости MFAService для тестов
+type mfaServiceMocks struct {
+	userRepo      *MockUserRepository
+	mfaRepo       *MockMFARepository
+	challengeRepo *MockMFAChallengeRepository
+}
+
+// newTestMFAService создает MFAService с тестовыми зависимостями
+func newTestMFAService(requiredForManagers bool) (services.MFAService, *mfaServiceMocks) {
+	m := &mfaServiceMocks{
+		userRepo:      new(MockUserRepository),
+		mfaRepo:       new(MockMFARepository),
+		challengeRepo: new(MockMFAChallengeRepository),
+	}
+	mfaConfig := config.MFAConfig{
+		Issuer:              "Learning Portal",
+		RequiredForManagers: requiredForManagers,
+		ChallengeTTL:        5 * time.Minute,
+	}
+
+	service := services.NewMFAService(m.userRepo, m.mfaRepo, m.challengeRepo, mfaConfig, ru.NewRussianMessages())
+	return service, m
+}
+
+func (m *mfaServiceMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.mfaRepo.AssertExpectations(t)
+	m.challengeRepo.AssertExpectations(t)
+}
+
+// newConfirmedMFA создает подключенный второй фактор со случайным секретом
+func newConfirmedMFA(t *testing.T, userID uuid.UUID) *models.UserMFA {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	confirmedAt := time.Now().Add(-time.Hour)
+	return &models.UserMFA{UserID: userID, Secret: secret, ConfirmedAt: &confirmedAt}
+}
+
+// newActiveChallenge создает незавершенный вход для токена
+func newActiveChallenge(userID uuid.UUID, challengeToken string) *models.MFAChallenge {
+	return &models.MFAChallenge{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: hashToken(challengeToken),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+}
+
+func TestMFAService_Enroll_ReturnsOTPAuthURI(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+
+	var saved *models.UserMFA
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.mfaRepo.On("GetByUserID", mock.Anything, user.ID).Return(nil, nil)
+	m.mfaRepo.On("SavePending", mock.Anything, mock.AnythingOfType("*models.UserMFA")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*models.UserMFA) }).
+		Return(nil)
+
+	// Выполнение
+	response, err := service.Enroll(context.Background(), user.ID)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, saved.Secret, response.Secret)
+	assert.Nil(t, saved.ConfirmedAt)
+
+	uri, err := url.Parse(response.OTPAuthURI)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, response.Secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Learning Portal", uri.Query().Get("issuer"))
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_Enroll_AlreadyEnabled(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.mfaRepo.On("GetByUserID", mock.Anything, user.ID).Return(newConfirmedMFA(t, user.ID), nil)
+
+	// Выполнение
+	response, err := service.Enroll(context.Background(), user.ID)
+
+	// Проверка - подключенный секрет не перезаписывается
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "уже включена")
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_Confirm_IssuesHashedRecoveryCodes(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	userID := uuid.New()
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	pending := &models.UserMFA{UserID: userID, Secret: secret}
+
+	now := time.Now()
+	code, err := totp.Code(secret, now)
+	require.NoError(t, err)
+
+	var stored []models.MFARecoveryCode
+
+	// Настройка моков
+	m.mfaRepo.On("GetByUserID", mock.Anything, userID).Return(pending, nil)
+	m.mfaRepo.On("Confirm", mock.Anything, userID, mock.AnythingOfType("int64")).Return(true, nil)
+	m.mfaRepo.On("ReplaceRecoveryCodes", mock.Anything, userID, mock.AnythingOfType("[]models.MFARecoveryCode")).
+		Run(func(args mock.Arguments) { stored = args.Get(2).([]models.MFARecoveryCode) }).
+		Return(nil)
+
+	// Выполнение
+	response, err := service.Confirm(context.Background(), userID, &requests.MFAConfirmRequest{Code: code})
+
+	// Проверка - в ответе коды, в БД только их хеши
+	require.NoError(t, err)
+	require.Len(t, response.RecoveryCodes, 10)
+	require.Len(t, stored, 10)
+	for i, recoveryCode := range response.RecoveryCodes {
+		assert.Equal(t, hashToken(strings.ReplaceAll(recoveryCode, "-", "")), stored[i].CodeHash)
+	}
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_Confirm_InvalidCode(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	userID := uuid.New()
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	// Код не должен случайно совпасть с одним из допустимых в текущем окне
+	wrongCode := "000000"
+	if _, ok := totp.Validate(secret, wrongCode, time.Now()); ok {
+		wrongCode = "111111"
+	}
+
+	// Настройка моков
+	m.mfaRepo.On("GetByUserID", mock.Anything, userID).Return(&models.UserMFA{UserID: userID, Secret: secret}, nil)
+
+	// Выполнение
+	response, err := service.Confirm(context.Background(), userID, &requests.MFAConfirmRequest{Code: wrongCode})
+
+	// Проверка
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "Неверный код")
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_Challenge(t *testing.T) {
+	testCases := []struct {
+		name               string
+		role               string
+		requiredForManager bool
+		mfa                func(t *testing.T, userID uuid.UUID) *models.UserMFA
+		wantChallenge      bool
+		wantEnrollment     bool
+	}{
+		{name: "второй фактор не подключен и не обязателен", role: models.RoleManager, wantChallenge: false},
+		{name: "обязателен только для менеджеров", role: models.RoleEmployee, requiredForManager: true, wantChallenge: false},
+		{name: "подключен", role: models.RoleEmployee, mfa: newConfirmedMFA, wantChallenge: true},
+		{name: "обязателен, но не подключен", role: models.RoleManager, requiredForManager: true, wantChallenge: true, wantEnrollment: true},
+		{name: "обязателен и для администраторов", role: models.RoleAdmin, requiredForManager: true, wantChallenge: true, wantEnrollment: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestMFAService(tc.requiredForManager)
+			user := &models.User{ID: uuid.New(), Email: "user@example.com", Role: tc.role}
+
+			// Настройка моков
+			if tc.mfa != nil {
+				m.mfaRepo.On("GetByUserID", mock.Anything, user.ID).Return(tc.mfa(t, user.ID), nil)
+			} else {
+				m.mfaRepo.On("GetByUserID", mock.Anything, user.ID).Return(nil, nil)
+			}
+			if tc.wantChallenge {
+				m.challengeRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.MFAChallenge")).Return(nil)
+			}
+
+			// Выполнение
+			response, err := service.Challenge(context.Background(), user)
+
+			// Проверка
+			require.NoError(t, err)
+			if !tc.wantChallenge {
+				assert.Nil(t, response)
+			} else {
+				require.NotNil(t, response)
+				assert.True(t, response.MFARequired)
+				assert.Equal(t, tc.wantEnrollment, response.MFAEnrollmentRequired)
+				assert.NotEmpty(t, response.MFAToken)
+				assert.Empty(t, response.Token)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestMFAService_VerifyChallenge_TOTP(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	userID := uuid.New()
+	mfa := newConfirmedMFA(t, userID)
+	challenge := newActiveChallenge(userID, "challenge-token")
+
+	code, err := totp.Code(mfa.Secret, time.Now())
+	require.NoError(t, err)
+
+	// Настройка моков
+	m.challengeRepo.On("GetByHash", mock.Anything, challenge.TokenHash).Return(challenge, nil)
+	m.mfaRepo.On("GetByUserID", mock.Anything, userID).Return(mfa, nil)
+	m.mfaRepo.On("UseStep", mock.Anything, userID, mock.AnythingOfType("int64")).Return(true, nil)
+	m.challengeRepo.On("MarkUsed", mock.Anything, challenge.ID).Return(true, nil)
+
+	// Выполнение
+	verification, err := service.VerifyChallenge(context.Background(), &requests.MFALoginRequest{MFAToken: "challenge-token", Code: code})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, userID, verification.UserID)
+	assert.Empty(t, verification.RecoveryCodes)
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_VerifyChallenge_RecoveryCode(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	userID := uuid.New()
+	challenge := newActiveChallenge(userID, "challenge-token")
+
+	// Настройка моков - код восстановления принимается без учета регистра и дефисов
+	m.challengeRepo.On("GetByHash", mock.Anything, challenge.TokenHash).Return(challenge, nil)
+	m.mfaRepo.On("GetByUserID", mock.Anything, userID).Return(newConfirmedMFA(t, userID), nil)
+	m.mfaRepo.On("UseRecoveryCode", mock.Anything, userID, hashToken("abcdefghijklmnop")).Return(true, nil)
+	m.challengeRepo.On("MarkUsed", mock.Anything, challenge.ID).Return(true, nil)
+
+	// Выполнение
+	verification, err := service.VerifyChallenge(context.Background(), &requests.MFALoginRequest{MFAToken: "challenge-token", Code: "ABCD-EFGH-IJKL-MNOP"})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, userID, verification.UserID)
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_VerifyChallenge_WrongCodeCountsAttempt(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	userID := uuid.New()
+	challenge := newActiveChallenge(userID, "challenge-token")
+
+	// Настройка моков
+	m.challengeRepo.On("GetByHash", mock.Anything, challenge.TokenHash).Return(challenge, nil)
+	m.mfaRepo.On("GetByUserID", mock.Anything, userID).Return(newConfirmedMFA(t, userID), nil)
+	m.mfaRepo.On("UseRecoveryCode", mock.Anything, userID, mock.AnythingOfType("string")).Return(false, nil)
+	m.challengeRepo.On("IncrementAttempts", mock.Anything, challenge.ID).Return(nil)
+
+	// Выполнение
+	verification, err := service.VerifyChallenge(context.Background(), &requests.MFALoginRequest{MFAToken: "challenge-token", Code: "not-a-code"})
+
+	// Проверка - вход не завершен
+	require.Error(t, err)
+	assert.Nil(t, verification)
+	assert.Contains(t, err.Error(), "Неверный код")
+
+	m.assertExpectations(t)
+}
+
+func TestMFAService_VerifyChallenge_AttemptsExhausted(t *testing.T) {
+	// Подготовка
+	service, m := newTestMFAService(false)
+	userID := uuid.New()
+	challenge := newActiveChallenge(userID, "challenge-token")
+	challenge.Attempts = 5
+
+	// Настройка моков
+	m.challengeRepo.On("GetByHash", mock.Anything, challenge.TokenHash).Return(challenge, nil)
+
+	// Выполнение
+	verification, err := service.VerifyChallenge(context.Background(), &requests.MFALoginRequest{MFAToken: "challenge-token", Code: "123456"})
+
+	// Проверка - придется заново ввести пароль
+	require.Error(t, err)
+	assert.Nil(t, verification)
+	assert.Contains(t, err.Error(), "Войдите заново")
+
+	m.assertExpectations(t)
+}
+
+func TestAuthService_Login_MFARequired(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	mockMFA := new(MockMFAService)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{mfa: mockMFA})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	existingUser := &models.User{
+		ID:       uuid.New(),
+		Email:    "manager@example.com",
+		Password: string(hashedPassword),
+		Role:     models.RoleManager,
+	}
+	challenge := &responses.TokenResponse{User: *existingUser, MFARequired: true, MFAToken: "challenge-token"}
+
+	// Настройка моков
+	mockRepo.On("GetByEmail", mock.Anything, existingUser.Email).Return(existingUser, nil)
+	mockMFA.On("Challenge", mock.Anything, existingUser).Return(challenge, nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: existingUser.Email, Password: "password123"}, testClient)
+
+	// Проверка - вместо токенов выдан токен второго шага, refresh токен не создан
+	require.NoError(t, err)
+	assert.True(t, tokenResponse.MFARequired)
+	assert.Equal(t, "challenge-token", tokenResponse.MFAToken)
+	assert.Empty(t, tokenResponse.Token)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+	mockMFA.AssertExpectations(t)
+}
+
+func TestAuthService_LoginMFA_IssuesTokens(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	mockMFA := new(MockMFAService)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{mfa: mockMFA})
+
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+	req := &requests.MFALoginRequest{MFAToken: "challenge-token", Code: "123456"}
+	verification := &services.MFAVerification{UserID: user.ID, RecoveryCodes: []string{"aaaa-bbbb-cccc-dddd"}}
+
+	// Настройка моков
+	mockMFA.On("VerifyChallenge", mock.Anything, req).Return(verification, nil)
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	mockRefreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.LoginMFA(context.Background(), req, testClient)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokenResponse.Token)
+	assert.NotEmpty(t, tokenResponse.RefreshToken)
+	assert.Equal(t, verification.RecoveryCodes, tokenResponse.RecoveryCodes)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+	mockMFA.AssertExpectations(t)
+}
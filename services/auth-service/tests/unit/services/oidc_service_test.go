@@ -0,0 +1,375 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer      = "https://auth.example.com"
+	testRedirectURI = "https://crm.example.com/callback"
+)
+
+// memoryCodeRepository хранит коды авторизации в памяти и, как БД, выдает каждый код один раз
+type memoryCodeRepository struct {
+	mu    sync.Mutex
+	codes map[string]*models.AuthorizationCode
+}
+
+func newMemoryCodeRepository() *memoryCodeRepository {
+	return &memoryCodeRepository{codes: make(map[string]*models.AuthorizationCode)}
+}
+
+func (r *memoryCodeRepository) Create(ctx context.Context, code *models.AuthorizationCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes[code.CodeHash] = code
+	return nil
+}
+
+func (r *memoryCodeRepository) Consume(ctx context.Context, codeHash string) (*models.AuthorizationCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code, ok := r.codes[codeHash]
+	if !ok || code.UsedAt != nil {
+		return nil, nil
+	}
+	now := time.Now()
+	code.UsedAt = &now
+	return code, nil
+}
+
+// oidcTestEnv провайдер OpenID Connect с асимметричным ключом и зависимостями для тестов
+type oidcTestEnv struct {
+	service      services.OIDCService
+	tokenService services.TokenService
+	keySet       *keys.KeySet
+	client       *models.APIClient
+	user         *models.User
+}
+
+// newOIDCTestEnv создает провайдер с одним зарегистрированным клиентом и активным пользователем
+func newOIDCTestEnv(t *testing.T) *oidcTestEnv {
+	t.Helper()
+	messages := ru.NewRussianMessages()
+
+	// ID токен должен проверяться внешним сервисом, поэтому подпись асимметричная
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "signing.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	jwtConfig := testJWTConfig
+	jwtConfig.SigningKeyFile = keyPath
+	keySet, err := keys.NewKeySet(jwtConfig, messages)
+	require.NoError(t, err)
+
+	revocations := new(MockRevocationStore)
+	revocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil).Maybe()
+	tokenService := services.NewTokenService(new(MockRefreshTokenRepository), newTestSessionRepository(), revocations, keySet, jwtConfig, messages)
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee, Status: models.StatusActive, EmailVerifiedAt: &verifiedAt}
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Maybe()
+	authService := newTestAuthService(userRepo, new(MockRefreshTokenRepository), revocations)
+
+	client := newTestAPIClient()
+	client.RedirectURIs = testRedirectURI + " https://crm.example.com/silent"
+	clientRepo := new(MockAPIClientRepository)
+	clientRepo.On("GetByID", mock.Anything, client.ID).Return(client, nil).Maybe()
+	clientRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	clientService := services.NewClientService(clientRepo, tokenService, messages)
+
+	codes := newMemoryCodeRepository()
+	oidcConfig := config.OIDCConfig{Issuer: testIssuer, LoginURL: "https://portal.example.com/oauth/authorize", CodeTTL: time.Minute}
+
+	return &oidcTestEnv{
+		service:      services.NewOIDCService(codes, clientRepo, clientService, authService, tokenService, keySet, oidcConfig, messages),
+		tokenService: tokenService,
+		keySet:       keySet,
+		client:       client,
+		user:         user,
+	}
+}
+
+// relyingParty внешний сервис, который входит через провайдер по authorization code с PKCE
+type relyingParty struct {
+	clientID string
+	secret   string
+	verifier string
+	state    string
+	nonce    string
+}
+
+func newRelyingParty(client *models.APIClient) *relyingParty {
+	return &relyingParty{
+		clientID: client.ID.String(),
+		secret:   testClientSecret,
+		verifier: base64.RawURLEncoding.EncodeToString([]byte("relying-party-code-verifier-0123456789")),
+		state:    "state-" + uuid.NewString(),
+		nonce:    "nonce-" + uuid.NewString(),
+	}
+}
+
+// authorizeRequest формирует запрос авторизации с code_challenge по S256
+func (rp *relyingParty) authorizeRequest() *requests.AuthorizeRequest {
+	sum := sha256.Sum256([]byte(rp.verifier))
+	return &requests.AuthorizeRequest{
+		ResponseType:        services.ResponseTypeCode,
+		ClientID:            rp.clientID,
+		RedirectURI:         testRedirectURI,
+		Scope:               "openid email profile",
+		State:               rp.state,
+		Nonce:               rp.nonce,
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(sum[:]),
+		CodeChallengeMethod: services.CodeChallengeMethodS256,
+	}
+}
+
+// callback разбирает адрес возврата и проверяет state
+func (rp *relyingParty) callback(t *testing.T, redirectTo string) url.Values {
+	t.Helper()
+	target, err := url.Parse(redirectTo)
+	require.NoError(t, err)
+	assert.Equal(t, "crm.example.com", target.Host)
+	assert.Equal(t, "/callback", target.Path)
+	assert.Equal(t, rp.state, target.Query().Get("state"))
+	return target.Query()
+}
+
+// tokenRequest формирует обмен кода на токены
+func (rp *relyingParty) tokenRequest(code string) *requests.ClientTokenRequest {
+	return &requests.ClientTokenRequest{
+		GrantType:    services.GrantTypeAuthorizationCode,
+		ClientID:     rp.clientID,
+		ClientSecret: rp.secret,
+		Code:         code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: rp.verifier,
+	}
+}
+
+// verifyIDToken проверяет ID токен так, как это делает внешний сервис: по ключу из JWKS
+func (rp *relyingParty) verifyIDToken(t *testing.T, idToken string, jwks keys.JWKS, issuer string) *services.IDTokenClaims {
+	t.Helper()
+	claims := &services.IDTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		for _, jwk := range jwks.Keys {
+			if jwk.Kid == token.Header["kid"] {
+				return ed25519.PublicKey(mustDecodeBase64URL(t, jwk.X)), nil
+			}
+		}
+		return nil, jwt.ErrTokenUnverifiable
+	}, jwt.WithValidMethods([]string{"EdDSA"}), jwt.WithIssuer(issuer), jwt.WithAudience(rp.clientID))
+	require.NoError(t, err)
+	assert.Equal(t, rp.nonce, claims.Nonce)
+	return claims
+}
+
+func mustDecodeBase64URL(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	require.NoError(t, err)
+	return decoded
+}
+
+// signIn выполняет вход пользователя в портал и авторизацию запроса внешнего сервиса
+func (env *oidcTestEnv) signIn(t *testing.T, req *requests.AuthorizeRequest) string {
+	t.Helper()
+	portalToken, err := env.tokenService.GenerateAccessToken(env.user, uuid.New())
+	require.NoError(t, err)
+
+	response, err := env.service.Authorize(context.Background(), portalToken, req)
+	require.NoError(t, err)
+	return response.RedirectTo
+}
+
+func TestOIDCService_AuthorizationCodeFlow(t *testing.T) {
+	// Подготовка
+	env := newOIDCTestEnv(t)
+	rp := newRelyingParty(env.client)
+	discovery := env.service.Discovery()
+
+	// Выполнение - пользователь входит в портал и возвращается во внешний сервис с кодом
+	require.NoError(t, env.service.CheckAuthorization(context.Background(), rp.authorizeRequest()))
+	query := rp.callback(t, env.signIn(t, rp.authorizeRequest()))
+	code := query.Get("code")
+	require.NotEmpty(t, code)
+
+	tokens, err := env.service.ExchangeCode(context.Background(), rp.tokenRequest(code))
+	require.NoError(t, err)
+
+	// Проверка - ID токен проверяется по опубликованному JWKS и содержит данные пользователя
+	assert.Equal(t, testIssuer, discovery.Issuer)
+	assert.Equal(t, []string{"EdDSA"}, discovery.IDTokenSigningAlgValuesSupported)
+	assert.Equal(t, "openid email", tokens.Scope)
+	claims := rp.verifyIDToken(t, tokens.IDToken, env.keySet.JWKS(), discovery.Issuer)
+	assert.Equal(t, env.user.ID.String(), claims.Subject)
+	assert.Equal(t, env.user.Email, claims.Email)
+	assert.Equal(t, env.user.Role, claims.Role)
+	require.NotNil(t, claims.EmailVerified)
+	assert.True(t, *claims.EmailVerified)
+
+	userInfo, err := env.service.UserInfo(context.Background(), tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, env.user.ID.String(), userInfo.Subject)
+	assert.Equal(t, env.user.Email, userInfo.Email)
+	assert.Equal(t, env.user.Role, userInfo.Role)
+
+	// Код одноразовый
+	_, err = env.service.ExchangeCode(context.Background(), rp.tokenRequest(code))
+	requireOAuthError(t, err, services.OAuthInvalidGrant)
+}
+
+func TestOIDCService_AuthorizationCodeFlow_WithoutEmailScope(t *testing.T) {
+	// Подготовка
+	env := newOIDCTestEnv(t)
+	rp := newRelyingParty(env.client)
+	req := rp.authorizeRequest()
+	req.Scope = services.ScopeOpenID
+
+	// Выполнение
+	query := rp.callback(t, env.signIn(t, req))
+	tokens, err := env.service.ExchangeCode(context.Background(), rp.tokenRequest(query.Get("code")))
+	require.NoError(t, err)
+
+	// Проверка - без scope email внешний сервис не получает адрес ни в ID токене, ни в userinfo
+	assert.Equal(t, services.ScopeOpenID, tokens.Scope)
+	claims := rp.verifyIDToken(t, tokens.IDToken, env.keySet.JWKS(), testIssuer)
+	assert.Equal(t, env.user.ID.String(), claims.Subject)
+	assert.Empty(t, claims.Email)
+	assert.Nil(t, claims.EmailVerified)
+
+	userInfo, err := env.service.UserInfo(context.Background(), tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, env.user.ID.String(), userInfo.Subject)
+	assert.Empty(t, userInfo.Email)
+	assert.Nil(t, userInfo.EmailVerified)
+}
+
+func TestOIDCService_ExchangeCode_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *requests.ClientTokenRequest)
+		code   string
+	}{
+		{"Неверный code_verifier", func(req *requests.ClientTokenRequest) {
+			req.CodeVerifier = base64.RawURLEncoding.EncodeToString([]byte("another-code-verifier-0123456789abcd"))
+		}, services.OAuthInvalidGrant},
+		{"Другой redirect_uri", func(req *requests.ClientTokenRequest) {
+			req.RedirectURI = "https://crm.example.com/silent"
+		}, services.OAuthInvalidGrant},
+		{"Без code_verifier", func(req *requests.ClientTokenRequest) {
+			req.CodeVerifier = ""
+		}, services.OAuthInvalidRequest},
+		{"Неверный секрет клиента", func(req *requests.ClientTokenRequest) {
+			req.ClientSecret = "wrong-secret"
+		}, services.OAuthInvalidClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			env := newOIDCTestEnv(t)
+			rp := newRelyingParty(env.client)
+			code := rp.callback(t, env.signIn(t, rp.authorizeRequest())).Get("code")
+			req := rp.tokenRequest(code)
+			tt.modify(req)
+
+			// Выполнение
+			tokens, err := env.service.ExchangeCode(context.Background(), req)
+
+			// Проверка
+			assert.Nil(t, tokens)
+			requireOAuthError(t, err, tt.code)
+		})
+	}
+}
+
+func TestOIDCService_CheckAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(req *requests.AuthorizeRequest)
+		oauthCode string // пусто - ошибка без перенаправления к клиенту
+	}{
+		{"Незарегистрированный redirect_uri", func(req *requests.AuthorizeRequest) {
+			req.RedirectURI = "https://evil.example.com/callback"
+		}, ""},
+		{"Неизвестный клиент", func(req *requests.AuthorizeRequest) {
+			req.ClientID = uuid.NewString()
+		}, ""},
+		{"Без PKCE", func(req *requests.AuthorizeRequest) {
+			req.CodeChallenge, req.CodeChallengeMethod = "", ""
+		}, services.OAuthInvalidRequest},
+		{"PKCE plain", func(req *requests.AuthorizeRequest) {
+			req.CodeChallengeMethod = "plain"
+		}, services.OAuthInvalidRequest},
+		{"Без scope openid", func(req *requests.AuthorizeRequest) {
+			req.Scope = "email"
+		}, services.OAuthInvalidScope},
+		{"Неподдерживаемый response_type", func(req *requests.AuthorizeRequest) {
+			req.ResponseType = "token"
+		}, services.OAuthUnsupportedResponseType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			env := newOIDCTestEnv(t)
+			req := newRelyingParty(env.client).authorizeRequest()
+			tt.modify(req)
+
+			// Выполнение
+			err := env.service.CheckAuthorization(context.Background(), req)
+
+			// Проверка
+			require.Error(t, err)
+			if tt.oauthCode == "" {
+				var oauthErr *services.OAuthError
+				assert.NotErrorAs(t, err, &oauthErr)
+				return
+			}
+			requireOAuthError(t, err, tt.oauthCode)
+		})
+	}
+}
+
+func TestOIDCService_UserInfo_RejectsPortalToken(t *testing.T) {
+	// Подготовка
+	env := newOIDCTestEnv(t)
+	portalToken, err := env.tokenService.GenerateAccessToken(env.user, uuid.New())
+	require.NoError(t, err)
+
+	// Выполнение
+	userInfo, err := env.service.UserInfo(context.Background(), portalToken)
+
+	// Проверка - userinfo принимает только токены, выданные внешнему сервису со scope openid
+	require.Error(t, err)
+	assert.Nil(t, userInfo)
+}
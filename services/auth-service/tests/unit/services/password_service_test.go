@@ -0,0 +1,421 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/mail"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockPasswordResetRepository для тестирования
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockMailSender для тестирования
+type MockMailSender struct {
+	mock.Mock
+}
+
+func (m *MockMailSender) Send(ctx context.Context, msg mail.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+// MockPasswordService для тестирования
+type MockPasswordService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordService) ForgotPassword(ctx context.Context, req *requests.ForgotPasswordRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) ResetPassword(ctx context.Context, req *requests.ResetPasswordRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) ChangePassword(ctx context.Context, userID uuid.UUID, req *requests.ChangePasswordRequest) error {
+	args := m.Called(ctx, userID, req)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) ForceReset(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+// passwordServiceMocks зависимости PasswordService для тестов
+type passwordServiceMocks struct {
+	userRepo    *MockUserRepository
+	resetRepo   *MockPasswordResetRepository
+	refreshRepo *MockRefreshTokenRepository
+	revocations *MockRevocationStore
+	mailer      *MockMailSender
+}
+
+// newTestPasswordService создает PasswordService с тестовыми зависимостями
+func newTestPasswordService() (services.PasswordService, *passwordServiceMocks) {
+	m := &passwordServiceMocks{
+		userRepo:    new(MockUserRepository),
+		resetRepo:   new(MockPasswordResetRepository),
+		refreshRepo: new(MockRefreshTokenRepository),
+		revocations: new(MockRevocationStore),
+		mailer:      new(MockMailSender),
+	}
+	resetConfig := config.PasswordResetConfig{
+		TokenTTL: time.Hour,
+		URL:      "http://portal.local/reset-password",
+	}
+
+	service := services.NewPasswordService(m.userRepo, m.resetRepo, newTestTokenService(m.refreshRepo, m.revocations), m.mailer, resetConfig, newTestHasher(), ru.NewRussianMessages())
+	return service, m
+}
+
+func TestPasswordService_ForgotPassword_SendsLink(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: "hash", Role: "employee"}
+
+	var sent mail.Message
+	var stored *models.PasswordResetToken
+
+	// Настройка моков
+	m.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	m.resetRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.PasswordResetToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.PasswordResetToken) }).
+		Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(mail.Message) }).
+		Return(nil)
+
+	// Выполнение
+	err := service.ForgotPassword(context.Background(), &requests.ForgotPasswordRequest{Email: user.Email})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, sent.To)
+	assert.Equal(t, "Сброс пароля на Портале Обучения", sent.Subject)
+
+	// В письме токен, в БД только его хеш
+	start := strings.Index(sent.Body, "http://portal.local/reset-password?token=")
+	require.GreaterOrEqual(t, start, 0)
+	link, err := url.Parse(strings.Fields(sent.Body[start:])[0])
+	require.NoError(t, err)
+	token := link.Query().Get("token")
+	assert.NotEmpty(t, token)
+	assert.Equal(t, hashToken(token), stored.TokenHash)
+	assert.Equal(t, user.ID, stored.UserID)
+
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+}
+
+func TestPasswordService_ForgotPassword_UnknownEmail(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	// Настройка моков
+	m.userRepo.On("GetByEmail", mock.Anything, "unknown@example.com").Return(nil, nil)
+
+	// Выполнение
+	err := service.ForgotPassword(context.Background(), &requests.ForgotPasswordRequest{Email: "unknown@example.com"})
+
+	// Проверка - ответ не раскрывает отсутствие аккаунта, письмо не отправляется
+	require.NoError(t, err)
+
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+}
+
+func TestPasswordService_ForgotPassword_ExternalAccount(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	// Учетная запись создана каталогом или SSO и хранится без пароля
+	user := &models.User{ID: uuid.New(), Email: "ldap.user@example.com", Role: "employee"}
+
+	// Настройка моков
+	m.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	// Выполнение
+	err := service.ForgotPassword(context.Background(), &requests.ForgotPasswordRequest{Email: user.Email})
+
+	// Проверка - ответ тот же, что и для существующего аккаунта, но ссылка не выпускается
+	require.NoError(t, err)
+
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+}
+
+func TestPasswordService_ResetPassword_Success(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	record := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		TokenHash: hashToken("reset-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Created:   time.Now(),
+	}
+
+	// Настройка моков
+	m.resetRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	m.userRepo.On("GetByID", mock.Anything, record.UserID).Return(&models.User{ID: record.UserID, Email: "test@example.com", Password: "hash"}, nil)
+	m.resetRepo.On("MarkUsed", mock.Anything, record.ID).Return(true, nil)
+	m.userRepo.On("UpdatePassword", mock.Anything, record.UserID, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+	})).Return(nil)
+	m.resetRepo.On("InvalidateForUser", mock.Anything, record.UserID).Return(nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, record.UserID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, record.UserID).Return(nil)
+
+	// Выполнение
+	err := service.ResetPassword(context.Background(), &requests.ResetPasswordRequest{Token: "reset-token", Password: "new-password"})
+
+	// Проверка
+	require.NoError(t, err)
+
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+}
+
+func TestPasswordService_ResetPassword_InvalidToken(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		record *models.PasswordResetToken
+	}{
+		{"Unknown token", nil},
+		{"Expired token", &models.PasswordResetToken{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}},
+		{"Used token", &models.PasswordResetToken{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestPasswordService()
+
+			// Настройка моков
+			if tt.record == nil {
+				m.resetRepo.On("GetByHash", mock.Anything, hashToken("reset-token")).Return(nil, nil)
+			} else {
+				m.resetRepo.On("GetByHash", mock.Anything, hashToken("reset-token")).Return(tt.record, nil)
+			}
+
+			// Выполнение
+			err := service.ResetPassword(context.Background(), &requests.ResetPasswordRequest{Token: "reset-token", Password: "new-password"})
+
+			// Проверка
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "недействительна или устарела")
+
+			m.userRepo.AssertExpectations(t)
+			m.resetRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPasswordService_ResetPassword_PasswordMatchesEmail(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	record := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		TokenHash: hashToken("reset-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// Настройка моков
+	m.resetRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	m.userRepo.On("GetByID", mock.Anything, record.UserID).Return(&models.User{ID: record.UserID, Email: "Ivan.Petrov@example.com", Password: "hash"}, nil)
+
+	// Выполнение
+	err := service.ResetPassword(context.Background(), &requests.ResetPasswordRequest{Token: "reset-token", Password: "ivan.petrov"})
+
+	// Проверка - пароль не меняется, токен не погашается
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "не должен совпадать с email")
+
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+}
+
+func TestPasswordService_ResetPassword_ExternalAccount(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	record := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		TokenHash: hashToken("reset-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	// Настройка моков - ссылка выдана до того, как учетная запись перешла на вход через SSO
+	m.resetRepo.On("GetByHash", mock.Anything, record.TokenHash).Return(record, nil)
+	m.userRepo.On("GetByID", mock.Anything, record.UserID).Return(&models.User{ID: record.UserID, Email: "sso.user@example.com"}, nil)
+
+	// Выполнение
+	err := service.ResetPassword(context.Background(), &requests.ResetPasswordRequest{Token: "reset-token", Password: "new-password"})
+
+	// Проверка - пароль не появляется
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "корпоративный каталог или SSO")
+
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+}
+
+func TestPasswordService_ChangePassword_Success(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old-password"), 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword), Role: "employee"}
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.userRepo.On("UpdatePassword", mock.Anything, user.ID, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+	})).Return(nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+
+	// Выполнение
+	err := service.ChangePassword(context.Background(), user.ID, &requests.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+	})
+
+	// Проверка - все ранее выданные токены отозваны
+	require.NoError(t, err)
+
+	m.userRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+}
+
+func TestPasswordService_ChangePassword_WrongCurrentPassword(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old-password"), 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword), Role: "employee"}
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+	// Выполнение
+	err := service.ChangePassword(context.Background(), user.ID, &requests.ChangePasswordRequest{
+		CurrentPassword: "wrong-password",
+		NewPassword:     "new-password",
+	})
+
+	// Проверка - пароль не меняется, сессии не трогаются
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Неверный текущий пароль")
+
+	m.userRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+}
+
+func TestPasswordService_ForceReset_ReplacesPasswordAndSendsLink(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("old-password"), 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword), Role: "employee"}
+
+	// Настройка моков - старый пароль больше не подходит
+	m.userRepo.On("UpdatePassword", mock.Anything, user.ID, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("old-password")) != nil
+	})).Return(nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+	m.resetRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).Return(nil)
+
+	// Выполнение
+	err := service.ForceReset(context.Background(), user)
+
+	// Проверка
+	require.NoError(t, err)
+
+	m.userRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+}
+
+func TestPasswordService_ForceReset_ExternalAccountRevokesSessionsOnly(t *testing.T) {
+	// Подготовка
+	service, m := newTestPasswordService()
+
+	user := &models.User{ID: uuid.New(), Email: "ldap.user@example.com", Role: "employee"}
+
+	// Настройка моков - пароль не задается, письмо не отправляется
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+
+	// Выполнение
+	err := service.ForceReset(context.Background(), user)
+
+	// Проверка
+	require.NoError(t, err)
+
+	m.userRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTokenRevocationRepository для тестирования
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, revokedBefore time.Time) error {
+	args := m.Called(ctx, userID, revokedBefore)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) ListRevokedTokens(ctx context.Context, now time.Time) ([]models.RevokedToken, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]models.RevokedToken), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) ListUserRevocations(ctx context.Context, since time.Time) ([]models.UserTokenRevocation, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]models.UserTokenRevocation), args.Error(1)
+}
+
+// newClaims создает claims токена, выпущенного в issuedAt
+func newClaims(userID uuid.UUID, issuedAt time.Time) *services.JWTClaims {
+	return &services.JWTClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(testJWTConfig.AccessTokenTTL)),
+		},
+	}
+}
+
+func TestRevocationStore_IsRevoked_LoadsFromDatabase(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockTokenRevocationRepository)
+	store := services.NewRevocationStore(mockRepo, testJWTConfig, ru.NewRussianMessages())
+
+	userID := uuid.New()
+	revokedClaims := newClaims(userID, time.Now())
+	activeClaims := newClaims(userID, time.Now())
+
+	// Настройка моков - отзыв сделан другим экземпляром сервиса
+	mockRepo.On("ListRevokedTokens", mock.Anything, mock.Anything).Return([]models.RevokedToken{
+		{JTI: uuid.MustParse(revokedClaims.ID), UserID: userID, ExpiresAt: revokedClaims.ExpiresAt.Time},
+	}, nil).Once()
+	mockRepo.On("ListUserRevocations", mock.Anything, mock.Anything).Return([]models.UserTokenRevocation{}, nil).Once()
+
+	// Выполнение
+	revoked, err := store.IsRevoked(context.Background(), revokedClaims)
+	require.NoError(t, err)
+	active, err := store.IsRevoked(context.Background(), activeClaims)
+	require.NoError(t, err)
+
+	// Проверка - второй вызов обслужен из кеша без обращения к БД
+	assert.True(t, revoked)
+	assert.False(t, active)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevocationStore_RevokeToken(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockTokenRevocationRepository)
+	store := services.NewRevocationStore(mockRepo, testJWTConfig, ru.NewRussianMessages())
+
+	claims := newClaims(uuid.New(), time.Now())
+	jti := uuid.MustParse(claims.ID)
+
+	// Настройка моков
+	mockRepo.On("RevokeToken", mock.Anything, mock.MatchedBy(func(token *models.RevokedToken) bool {
+		return token.JTI == jti && token.UserID == claims.UserID
+	})).Return(nil)
+	mockRepo.On("ListRevokedTokens", mock.Anything, mock.Anything).Return([]models.RevokedToken{}, nil)
+	mockRepo.On("ListUserRevocations", mock.Anything, mock.Anything).Return([]models.UserTokenRevocation{}, nil)
+
+	// Выполнение
+	err := store.RevokeToken(context.Background(), jti, claims.UserID, claims.ExpiresAt.Time)
+	require.NoError(t, err)
+	revoked, err := store.IsRevoked(context.Background(), claims)
+
+	// Проверка - локальный отзыв не теряется при синхронизации с БД
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevocationStore_RevokeAllForUser(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockTokenRevocationRepository)
+	store := services.NewRevocationStore(mockRepo, testJWTConfig, ru.NewRussianMessages())
+
+	userID := uuid.New()
+	oldClaims := newClaims(userID, time.Now().Add(-time.Minute))
+	otherUserClaims := newClaims(uuid.New(), time.Now().Add(-time.Minute))
+
+	// Настройка моков
+	mockRepo.On("RevokeAllForUser", mock.Anything, userID, mock.MatchedBy(func(revokedBefore time.Time) bool {
+		return revokedBefore.Equal(revokedBefore.Truncate(time.Second))
+	})).Return(nil)
+	mockRepo.On("ListRevokedTokens", mock.Anything, mock.Anything).Return([]models.RevokedToken{}, nil)
+	mockRepo.On("ListUserRevocations", mock.Anything, mock.Anything).Return([]models.UserTokenRevocation{}, nil)
+
+	// Выполнение
+	err := store.RevokeAllForUser(context.Background(), userID)
+	require.NoError(t, err)
+
+	// Проверка
+	revoked, err := store.IsRevoked(context.Background(), oldClaims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(context.Background(), otherUserClaims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Токен, выпущенный сразу после отзыва, принимается, даже если iat совпадает с секундой отзыва
+	freshClaims := newClaims(userID, time.Now())
+	revoked, err = store.IsRevoked(context.Background(), freshClaims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	mockRepo.AssertExpectations(t)
+}
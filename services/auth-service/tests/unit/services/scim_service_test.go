@@ -0,0 +1,497 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/scim"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockGroupRepository для тестирования
+type MockGroupRepository struct {
+	mock.Mock
+}
+
+func (m *MockGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Group, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *MockGroupRepository) List(ctx context.Context, filter models.GroupFilter) ([]models.Group, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Group), args.Int(1), args.Error(2)
+}
+
+func (m *MockGroupRepository) Update(ctx context.Context, group *models.Group, changes []models.GroupMembersChange) error {
+	args := m.Called(ctx, group, changes)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) ListMembers(ctx context.Context, groupID uuid.UUID) ([]models.GroupMember, error) {
+	args := m.Called(ctx, groupID)
+	return args.Get(0).([]models.GroupMember), args.Error(1)
+}
+
+func (m *MockGroupRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Group, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Group), args.Error(1)
+}
+
+func (m *MockGroupRepository) AddMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	args := m.Called(ctx, groupID, userIDs)
+	return args.Error(0)
+}
+
+// scimServiceMocks зависимости SCIMService для тестов
+type scimServiceMocks struct {
+	userRepo     *MockUserRepository
+	identityRepo *MockUserIdentityRepository
+	groupRepo    *MockGroupRepository
+	resetRepo    *MockPasswordResetRepository
+	verifyRepo   *MockEmailVerificationRepository
+	refreshRepo  *MockRefreshTokenRepository
+	revocations  *MockRevocationStore
+}
+
+// newTestSCIMService создает SCIMService с тестовыми зависимостями
+func newTestSCIMService() (services.SCIMService, *scimServiceMocks) {
+	m := &scimServiceMocks{
+		userRepo:     new(MockUserRepository),
+		identityRepo: new(MockUserIdentityRepository),
+		groupRepo:    new(MockGroupRepository),
+		resetRepo:    new(MockPasswordResetRepository),
+		verifyRepo:   new(MockEmailVerificationRepository),
+		refreshRepo:  new(MockRefreshTokenRepository),
+		revocations:  new(MockRevocationStore),
+	}
+	tokenService := newTestTokenService(m.refreshRepo, m.revocations)
+	return services.NewSCIMService(m.userRepo, m.identityRepo, m.groupRepo, m.resetRepo, m.verifyRepo, tokenService, ru.NewRussianMessages()), m
+}
+
+// requireSCIMError проверяет статус и scimType ошибки запроса
+func requireSCIMError(t *testing.T, err error, status int, scimType string) {
+	t.Helper()
+	var scimErr *scim.Error
+	require.True(t, errors.As(err, &scimErr), "ожидалась ошибка SCIM, получено %v", err)
+	assert.Equal(t, status, scimErr.Status)
+	assert.Equal(t, scimType, scimErr.Type)
+}
+
+// patchRequest собирает запрос PATCH из JSON операций
+func patchRequest(t *testing.T, operations string) *scim.PatchRequest {
+	t.Helper()
+	req := &scim.PatchRequest{Schemas: []string{scim.SchemaPatchOp}}
+	require.NoError(t, json.Unmarshal([]byte(operations), &req.Operations))
+	return req
+}
+
+func TestSCIMService_CreateUser(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	req := &scim.User{UserName: "ivanov@example.com", ExternalID: "E-1024"}
+
+	// Настройка моков
+	var created *models.User
+	var identity *models.UserIdentity
+	m.userRepo.On("GetByEmail", mock.Anything, req.UserName).Return(nil, nil)
+	m.identityRepo.On("Get", mock.Anything, models.IdentityProviderSCIM, req.ExternalID).Return(nil, nil)
+	m.userRepo.On("CreateWithIdentity", mock.Anything, mock.AnythingOfType("*models.User"), mock.AnythingOfType("*models.UserIdentity")).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.User)
+		identity = args.Get(2).(*models.UserIdentity)
+	}).Return(nil)
+	m.identityRepo.On("GetByUser", mock.Anything, models.IdentityProviderSCIM, mock.Anything).Return(&models.UserIdentity{Subject: req.ExternalID}, nil)
+
+	// Выполнение
+	user, err := service.CreateUser(context.Background(), req)
+
+	// Проверка - сотрудник без локального пароля с подтвержденным email
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, models.RoleEmployee, created.Role)
+	assert.Empty(t, created.Password)
+	assert.True(t, created.IsEmailVerified())
+	assert.Equal(t, models.IdentityProviderSCIM, identity.Provider)
+	assert.Equal(t, req.ExternalID, identity.Subject)
+	assert.Equal(t, created.ID, identity.UserID)
+	assert.Equal(t, created.ID.String(), user.ID)
+	assert.Equal(t, req.UserName, user.UserName)
+	assert.Equal(t, req.ExternalID, user.ExternalID)
+	assert.Equal(t, []string{scim.SchemaUser}, user.Schemas)
+	require.NotNil(t, user.Active)
+	assert.True(t, *user.Active)
+	m.identityRepo.AssertExpectations(t)
+}
+
+func TestSCIMService_CreateUser_Conflict(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	existing := &models.User{ID: uuid.New(), Email: "ivanov@example.com", Status: models.StatusActive}
+
+	// Настройка моков
+	m.userRepo.On("GetByEmail", mock.Anything, existing.Email).Return(existing, nil)
+
+	// Выполнение
+	user, err := service.CreateUser(context.Background(), &scim.User{UserName: existing.Email})
+
+	// Проверка - кадровая система по 409 находит пользователя фильтром и привязывает его
+	requireSCIMError(t, err, http.StatusConflict, scim.ErrorUniqueness)
+	assert.Nil(t, user)
+	m.userRepo.AssertNotCalled(t, "CreateWithIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSCIMService_CreateUser_Inactive(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	active := false
+	req := &scim.User{UserName: "ivanov@example.com", Active: &active}
+
+	// Настройка моков - уволенный сотрудник создается сразу деактивированным, без второго запроса
+	var created *models.User
+	m.userRepo.On("GetByEmail", mock.Anything, req.UserName).Return(nil, nil)
+	m.userRepo.On("CreateWithIdentity", mock.Anything, mock.AnythingOfType("*models.User"), (*models.UserIdentity)(nil)).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.User)
+	}).Return(nil)
+	m.identityRepo.On("GetByUser", mock.Anything, models.IdentityProviderSCIM, mock.Anything).Return(nil, nil)
+
+	// Выполнение
+	user, err := service.CreateUser(context.Background(), req)
+
+	// Проверка
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, models.StatusDeactivated, created.Status)
+	require.NotNil(t, created.StatusReason)
+	require.NotNil(t, user.Active)
+	assert.False(t, *user.Active)
+	m.userRepo.AssertExpectations(t)
+	m.userRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSCIMService_PatchUser_Active(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		operations string
+		wantStatus string // пустая строка - статус не меняется
+	}{
+		{"Azure AD деактивирует строкой False", models.StatusActive, `[{"op":"Replace","path":"active","value":"False"}]`, models.StatusDeactivated},
+		{"Okta деактивирует объектом без path", models.StatusActive, `[{"op":"replace","value":{"active":false}}]`, models.StatusDeactivated},
+		{"Повторный прием на работу", models.StatusDeactivated, `[{"op":"replace","path":"active","value":true}]`, models.StatusActive},
+		{"Приостановленный администратором остается приостановленным", models.StatusSuspended, `[{"op":"replace","path":"active","value":true}]`, ""},
+		{"Уже деактивирован", models.StatusDeactivated, `[{"op":"replace","path":"active","value":false}]`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestSCIMService()
+			user := &models.User{ID: uuid.New(), Email: "petrov@example.com", Role: models.RoleEmployee, Status: tt.status}
+
+			// Настройка моков
+			m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+			m.identityRepo.On("GetByUser", mock.Anything, models.IdentityProviderSCIM, user.ID).Return(nil, nil)
+			switch tt.wantStatus {
+			case models.StatusDeactivated:
+				m.userRepo.On("UpdateStatus", mock.Anything, user.ID, models.StatusDeactivated, mock.AnythingOfType("*string")).Return(nil)
+				m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+				m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+			case models.StatusActive:
+				m.userRepo.On("UpdateStatus", mock.Anything, user.ID, models.StatusActive, (*string)(nil)).Return(nil)
+			}
+
+			// Выполнение
+			resource, err := service.PatchUser(context.Background(), user.ID.String(), patchRequest(t, tt.operations))
+
+			// Проверка - при деактивации все сессии завершаются сразу
+			require.NoError(t, err)
+			assert.Equal(t, user.Status != models.StatusDeactivated, *resource.Active)
+			if tt.wantStatus == "" {
+				assert.Equal(t, tt.status, user.Status)
+				m.userRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.userRepo.AssertExpectations(t)
+			m.refreshRepo.AssertExpectations(t)
+			m.revocations.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSCIMService_PatchUser_IgnoresUnknownAttributes(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	user := &models.User{ID: uuid.New(), Email: "petrov@example.com", Status: models.StatusActive}
+	operations := `[
+		{"op":"replace","path":"name.givenName","value":"Петр"},
+		{"op":"replace","path":"emails[type eq \"work\"].value","value":"petrov@example.com"},
+		{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department","value":"Продажи"}
+	]`
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.identityRepo.On("GetByUser", mock.Anything, models.IdentityProviderSCIM, user.ID).Return(nil, nil)
+
+	// Выполнение
+	resource, err := service.PatchUser(context.Background(), user.ID.String(), patchRequest(t, operations))
+
+	// Проверка - атрибуты, которые портал не хранит, не мешают синхронизации
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, resource.UserName)
+	m.userRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	m.userRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSCIMService_PatchUser_EmailInvalidatesLinks(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	user := &models.User{ID: uuid.New(), Email: "petrova@example.com", Status: models.StatusActive}
+	operations := `[{"op":"replace","path":"userName","value":"ivanova@example.com"}]`
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.userRepo.On("GetByEmail", mock.Anything, "ivanova@example.com").Return(nil, nil)
+	m.userRepo.On("UpdateEmail", mock.Anything, user.ID, "ivanova@example.com").Return(nil)
+	m.resetRepo.On("InvalidateForUser", mock.Anything, user.ID).Return(nil)
+	m.verifyRepo.On("InvalidateForUser", mock.Anything, user.ID).Return(nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+	m.identityRepo.On("GetByUser", mock.Anything, models.IdentityProviderSCIM, user.ID).Return(nil, nil)
+
+	// Выполнение
+	resource, err := service.PatchUser(context.Background(), user.ID.String(), patchRequest(t, operations))
+
+	// Проверка - ссылки, отправленные на прежний адрес, и сессии больше не действуют,
+	// новый адрес не подтвержден
+	require.NoError(t, err)
+	assert.Equal(t, "ivanova@example.com", resource.UserName)
+	assert.False(t, user.IsEmailVerified())
+	m.userRepo.AssertExpectations(t)
+	m.resetRepo.AssertExpectations(t)
+	m.verifyRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+}
+
+func TestSCIMService_AdminForbidden(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin, Status: models.StatusActive}
+
+	tests := []struct {
+		name string
+		call func(service services.SCIMService) error
+	}{
+		{"Replace", func(service services.SCIMService) error {
+			_, err := service.ReplaceUser(context.Background(), admin.ID.String(), &scim.User{UserName: "attacker@example.com"})
+			return err
+		}},
+		{"Patch", func(service services.SCIMService) error {
+			_, err := service.PatchUser(context.Background(), admin.ID.String(), patchRequest(t, `[{"op":"replace","path":"active","value":false}]`))
+			return err
+		}},
+		{"Delete", func(service services.SCIMService) error {
+			return service.DeleteUser(context.Background(), admin.ID.String())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestSCIMService()
+
+			// Настройка моков
+			m.userRepo.On("GetByID", mock.Anything, admin.ID).Return(admin, nil)
+
+			// Выполнение
+			err := tt.call(service)
+
+			// Проверка - email, статус и сама учетная запись администратора не меняются
+			requireSCIMError(t, err, http.StatusForbidden, "")
+			m.userRepo.AssertExpectations(t)
+			m.identityRepo.AssertExpectations(t)
+			m.refreshRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSCIMService_PatchUser_NotFound(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	userID := uuid.New()
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, userID).Return(nil, nil)
+
+	// Выполнение
+	_, unknownErr := service.PatchUser(context.Background(), userID.String(), patchRequest(t, `[{"op":"replace","path":"active","value":false}]`))
+	_, malformedErr := service.PatchUser(context.Background(), "not-a-uuid", patchRequest(t, `[{"op":"replace","path":"active","value":false}]`))
+
+	// Проверка
+	requireSCIMError(t, unknownErr, http.StatusNotFound, "")
+	requireSCIMError(t, malformedErr, http.StatusNotFound, "")
+}
+
+func TestSCIMService_ListUsers_Filter(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	user := &models.User{ID: uuid.New(), Email: "sidorov@example.com", Status: models.StatusActive}
+	identity := &models.UserIdentity{Provider: models.IdentityProviderSCIM, Subject: "E-2048", UserID: user.ID}
+
+	// Настройка моков
+	m.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	m.userRepo.On("GetByEmail", mock.Anything, "unknown@example.com").Return(nil, nil)
+	m.identityRepo.On("Get", mock.Anything, models.IdentityProviderSCIM, identity.Subject).Return(identity, nil)
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.identityRepo.On("GetByUser", mock.Anything, models.IdentityProviderSCIM, user.ID).Return(identity, nil)
+
+	// Выполнение
+	byUserName, err := service.ListUsers(context.Background(), scim.NewListQuery(`userName eq "sidorov@example.com"`, "", "", ""))
+	require.NoError(t, err)
+	byExternalID, err := service.ListUsers(context.Background(), scim.NewListQuery(`externalId eq "E-2048"`, "", "", ""))
+	require.NoError(t, err)
+	missing, err := service.ListUsers(context.Background(), scim.NewListQuery(`userName eq "unknown@example.com"`, "", "", ""))
+	require.NoError(t, err)
+	_, unsupportedErr := service.ListUsers(context.Background(), scim.NewListQuery(`userName sw "sidorov"`, "", "", ""))
+
+	// Проверка
+	for _, response := range []*scim.ListResponse{byUserName, byExternalID} {
+		assert.Equal(t, []string{scim.SchemaListResponse}, response.Schemas)
+		assert.Equal(t, 1, response.TotalResults)
+		require.Len(t, response.Resources, 1)
+		resource := response.Resources[0].(*scim.User)
+		assert.Equal(t, user.ID.String(), resource.ID)
+		assert.Equal(t, identity.Subject, resource.ExternalID)
+	}
+	assert.Equal(t, 0, missing.TotalResults)
+	assert.NotNil(t, missing.Resources)
+	requireSCIMError(t, unsupportedErr, http.StatusBadRequest, scim.ErrorInvalidFilter)
+}
+
+func TestSCIMService_CreateGroup_UnknownMember(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	missingID := uuid.New()
+	req := &scim.Group{DisplayName: "Продажи", Members: []scim.Member{{Value: missingID.String()}}}
+
+	// Настройка моков
+	m.groupRepo.On("List", mock.Anything, models.GroupFilter{DisplayName: req.DisplayName, Limit: 1}).Return([]models.Group{}, 0, nil)
+	m.userRepo.On("GetByID", mock.Anything, missingID).Return(nil, nil)
+
+	// Выполнение
+	group, err := service.CreateGroup(context.Background(), req)
+
+	// Проверка - группа не создается наполовину
+	requireSCIMError(t, err, http.StatusBadRequest, scim.ErrorInvalidValue)
+	assert.Nil(t, group)
+	m.groupRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSCIMService_CreateGroup_DuplicateName(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	existing := models.Group{ID: uuid.New(), DisplayName: "Продажи"}
+
+	// Настройка моков
+	m.groupRepo.On("List", mock.Anything, models.GroupFilter{DisplayName: existing.DisplayName, Limit: 1}).Return([]models.Group{existing}, 1, nil)
+
+	// Выполнение
+	_, err := service.CreateGroup(context.Background(), &scim.Group{DisplayName: existing.DisplayName})
+
+	// Проверка
+	requireSCIMError(t, err, http.StatusConflict, scim.ErrorUniqueness)
+}
+
+func TestSCIMService_PatchGroup(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	group := &models.Group{ID: uuid.New(), DisplayName: "Продажи"}
+	hired := &models.User{ID: uuid.New(), Email: "new@example.com"}
+	transferred := uuid.New()
+	operations := `[
+		{"op":"Add","path":"members","value":[{"value":"` + hired.ID.String() + `"}]},
+		{"op":"Remove","path":"members[value eq \"` + transferred.String() + `\"]"},
+		{"op":"Replace","value":{"id":"` + group.ID.String() + `","displayName":"Продажи и маркетинг"}}
+	]`
+
+	// Настройка моков
+	m.groupRepo.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+	m.userRepo.On("GetByID", mock.Anything, hired.ID).Return(hired, nil)
+	m.groupRepo.On("List", mock.Anything, models.GroupFilter{DisplayName: "Продажи и маркетинг", Limit: 1}).Return([]models.Group{}, 0, nil)
+	m.groupRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Group) bool {
+		return updated.ID == group.ID && updated.DisplayName == "Продажи и маркетинг"
+	}), []models.GroupMembersChange{
+		{Op: models.GroupMembersAdd, UserIDs: []uuid.UUID{hired.ID}},
+		{Op: models.GroupMembersRemove, UserIDs: []uuid.UUID{transferred}},
+	}).Return(nil)
+	m.groupRepo.On("ListMembers", mock.Anything, group.ID).Return([]models.GroupMember{{UserID: hired.ID, Email: hired.Email}}, nil)
+
+	// Выполнение
+	resource, err := service.PatchGroup(context.Background(), group.ID.String(), patchRequest(t, operations))
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, "Продажи и маркетинг", resource.DisplayName)
+	assert.Equal(t, []scim.Member{{Value: hired.ID.String(), Display: hired.Email}}, resource.Members)
+	m.groupRepo.AssertExpectations(t)
+}
+
+func TestSCIMService_PatchGroup_InvalidOperation(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	group := &models.Group{ID: uuid.New(), DisplayName: "Продажи"}
+
+	// Настройка моков
+	m.groupRepo.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+
+	// Выполнение
+	_, err := service.PatchGroup(context.Background(), group.ID.String(), patchRequest(t, `[{"op":"move","path":"members"}]`))
+
+	// Проверка
+	requireSCIMError(t, err, http.StatusBadRequest, scim.ErrorInvalidSyntax)
+}
+
+func TestSCIMService_PatchGroup_AllOrNothing(t *testing.T) {
+	// Подготовка
+	service, m := newTestSCIMService()
+	group := &models.Group{ID: uuid.New(), DisplayName: "Продажи"}
+	other := models.Group{ID: uuid.New(), DisplayName: "Маркетинг"}
+	hired := &models.User{ID: uuid.New(), Email: "new@example.com"}
+	operations := `[
+		{"op":"add","path":"members","value":[{"value":"` + hired.ID.String() + `"}]},
+		{"op":"replace","path":"displayName","value":"Маркетинг"}
+	]`
+
+	// Настройка моков - название уже занято другой группой
+	m.groupRepo.On("GetByID", mock.Anything, group.ID).Return(group, nil)
+	m.userRepo.On("GetByID", mock.Anything, hired.ID).Return(hired, nil)
+	m.groupRepo.On("List", mock.Anything, models.GroupFilter{DisplayName: "Маркетинг", Limit: 1}).Return([]models.Group{other}, 1, nil)
+
+	// Выполнение
+	_, err := service.PatchGroup(context.Background(), group.ID.String(), patchRequest(t, operations))
+
+	// Проверка - участник из первой операции не добавлен
+	requireSCIMError(t, err, http.StatusConflict, scim.ErrorUniqueness)
+	m.groupRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	m.groupRepo.AssertNotCalled(t, "AddMembers", mock.Anything, mock.Anything, mock.Anything)
+	m.groupRepo.AssertExpectations(t)
+}
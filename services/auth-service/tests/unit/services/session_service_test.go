@@ -0,0 +1,284 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockSessionRepository для тестирования
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(ctx context.Context, id uuid.UUID, client models.ClientInfo, expiresAt time.Time) error {
+	args := m.Called(ctx, id, client, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeByUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// newTestSessionRepository создает хранилище сессий, которое принимает любые изменения
+// и считает каждую сессию активной
+func newTestSessionRepository() *MockSessionRepository {
+	repo := new(MockSessionRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("Touch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("Revoke", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("RevokeByUser", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("GetByID", mock.Anything, mock.Anything).Return(&models.Session{ExpiresAt: time.Now().Add(time.Hour)}, nil).Maybe()
+	return repo
+}
+
+// sessionServiceMocks зависимости SessionService для тестов
+type sessionServiceMocks struct {
+	sessionRepo *MockSessionRepository
+	refreshRepo *MockRefreshTokenRepository
+	revocations *MockRevocationStore
+	tokens      services.TokenService
+}
+
+// newTestSessionService создает SessionService с тестовыми зависимостями
+func newTestSessionService() (services.SessionService, *sessionServiceMocks) {
+	m := &sessionServiceMocks{
+		sessionRepo: new(MockSessionRepository),
+		refreshRepo: new(MockRefreshTokenRepository),
+		revocations: new(MockRevocationStore),
+	}
+	m.tokens = newTestTokenServiceWith(m.refreshRepo, m.sessionRepo, m.revocations)
+	m.revocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil).Maybe()
+
+	return services.NewSessionService(m.sessionRepo, m.tokens, ru.NewRussianMessages()), m
+}
+
+// newActiveSession создает активную сессию пользователя
+func newActiveSession(userID uuid.UUID) *models.Session {
+	return &models.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		UserAgent:  testClient.UserAgent,
+		IP:         testClientIP,
+		Created:    time.Now().Add(-time.Hour),
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+}
+
+func TestSessionService_List_MarksCurrentSession(t *testing.T) {
+	// Подготовка
+	service, m := newTestSessionService()
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+	current := newActiveSession(user.ID)
+	other := newActiveSession(user.ID)
+
+	token, err := m.tokens.GenerateAccessToken(user, current.ID)
+	require.NoError(t, err)
+
+	// Настройка моков
+	m.sessionRepo.On("GetByID", mock.Anything, current.ID).Return(current, nil)
+	m.sessionRepo.On("ListActiveByUser", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).
+		Return([]models.Session{*other, *current}, nil)
+
+	// Выполнение
+	response, err := service.List(context.Background(), token)
+
+	// Проверка
+	require.NoError(t, err)
+	require.Len(t, response.Sessions, 2)
+	assert.False(t, response.Sessions[0].Current)
+	assert.Equal(t, current.ID, response.Sessions[1].ID)
+	assert.True(t, response.Sessions[1].Current)
+	assert.Equal(t, testClientIP, response.Sessions[1].IP)
+
+	m.sessionRepo.AssertExpectations(t)
+}
+
+func TestSessionService_Revoke_RevokesFamilyAndSession(t *testing.T) {
+	// Подготовка
+	service, m := newTestSessionService()
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+	current := newActiveSession(user.ID)
+	other := newActiveSession(user.ID)
+
+	token, err := m.tokens.GenerateAccessToken(user, current.ID)
+	require.NoError(t, err)
+
+	// Настройка моков
+	m.sessionRepo.On("GetByID", mock.Anything, current.ID).Return(current, nil)
+	m.sessionRepo.On("GetByID", mock.Anything, other.ID).Return(other, nil)
+	m.refreshRepo.On("RevokeFamily", mock.Anything, other.ID).Return(nil)
+	m.sessionRepo.On("Revoke", mock.Anything, other.ID).Return(nil)
+
+	// Выполнение
+	err = service.Revoke(context.Background(), token, other.ID)
+
+	// Проверка
+	require.NoError(t, err)
+
+	m.sessionRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+}
+
+func TestSessionService_Revoke_ForeignOrInactiveSessionNotFound(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+
+	foreign := newActiveSession(uuid.New())
+	revoked := newActiveSession(user.ID)
+	revoked.RevokedAt = &revokedAt
+	expired := newActiveSession(user.ID)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	cases := map[string]*models.Session{
+		"чужая сессия":      foreign,
+		"завершенная":       revoked,
+		"истекшая":          expired,
+		"несуществующая ID": nil,
+	}
+
+	for name, target := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestSessionService()
+			current := newActiveSession(user.ID)
+			token, err := m.tokens.GenerateAccessToken(user, current.ID)
+			require.NoError(t, err)
+
+			targetID := uuid.New()
+			if target != nil {
+				targetID = target.ID
+			}
+
+			// Настройка моков
+			m.sessionRepo.On("GetByID", mock.Anything, current.ID).Return(current, nil)
+			m.sessionRepo.On("GetByID", mock.Anything, targetID).Return(target, nil)
+
+			// Выполнение
+			err = service.Revoke(context.Background(), token, targetID)
+
+			// Проверка
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "Сессия не найдена")
+
+			m.sessionRepo.AssertExpectations(t)
+			m.refreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthService_ValidateToken_RevokedSession(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	sessionRepo := new(MockSessionRepository)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{sessions: sessionRepo})
+	tokens := newTestTokenServiceWith(mockRefreshRepo, sessionRepo, mockRevocations)
+
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+	revokedAt := time.Now().Add(-time.Minute)
+	session := newActiveSession(user.ID)
+	session.RevokedAt = &revokedAt
+
+	token, err := tokens.GenerateAccessToken(user, session.ID)
+	require.NoError(t, err)
+
+	// Настройка моков - access токен еще не истек, но его сессию завершили на другом устройстве
+	mockRevocations.On("IsRevoked", mock.Anything, mock.AnythingOfType("*services.JWTClaims")).Return(false, nil)
+	sessionRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+
+	// Выполнение
+	validatedUser, err := authService.ValidateToken(context.Background(), token)
+
+	// Проверка
+	assert.Error(t, err)
+	assert.Nil(t, validatedUser)
+
+	mockRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_StartsSessionWithClientInfo(t *testing.T) {
+	// Подготовка
+	mockRepo := new(MockUserRepository)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockRevocations := new(MockRevocationStore)
+	sessionRepo := new(MockSessionRepository)
+	authService := newTestAuthServiceWith(mockRepo, mockRefreshRepo, mockRevocations, authServiceDeps{sessions: sessionRepo})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword), Role: models.RoleEmployee}
+	client := models.ClientInfo{IP: testClientIP, UserAgent: strings.Repeat("а", 600)}
+
+	// Настройка моков - цепочка refresh токенов совпадает с ID новой сессии
+	var sessionID uuid.UUID
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	sessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(session *models.Session) bool {
+		return session.UserID == user.ID && session.IP == testClientIP &&
+			len([]rune(session.UserAgent)) == 512 && session.ExpiresAt.After(time.Now())
+	})).Run(func(args mock.Arguments) {
+		sessionID = args.Get(1).(*models.Session).ID
+	}).Return(nil)
+	mockRefreshRepo.On("Create", mock.Anything, mock.MatchedBy(func(token *models.RefreshToken) bool {
+		return token.FamilyID == sessionID
+	})).Return(nil)
+
+	// Выполнение
+	tokenResponse, err := authService.Login(context.Background(), &requests.LoginRequest{Email: user.Email, Password: "password123"}, client)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokenResponse.Token)
+
+	mockRepo.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
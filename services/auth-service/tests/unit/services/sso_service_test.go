@@ -0,0 +1,492 @@
+package services_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/config"
+	"github.com/avangero/auth-service/internal/keys"
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/avangero/auth-service/internal/sso"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSSOClientID    = "learning-portal"
+	testSSOSecret      = "sso-client-secret"
+	testSSORedirectURL = "https://portal.example.com/sso/callback"
+)
+
+var (
+	idpKeyOnce sync.Once
+	idpKey     *rsa.PrivateKey
+)
+
+// testIdPKey возвращает RSA ключ подписи локального провайдера, общий для всех тестов
+func testIdPKey(t *testing.T) *rsa.PrivateKey {
+	idpKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		idpKey = key
+	})
+	return idpKey
+}
+
+// idpAccount пользователь локального провайдера
+type idpAccount struct {
+	subject       string
+	email         string
+	emailVerified bool
+}
+
+// idpGrant код авторизации, выданный локальным провайдером
+type idpGrant struct {
+	account       idpAccount
+	nonce         string
+	codeChallenge string
+}
+
+// mockIdP локальный OpenID Connect провайдер: документ обнаружения, JWKS и эндпоинт токенов
+// с проверкой секрета клиента, redirect_uri и PKCE. Вход пользователя выполняет signIn.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]idpGrant
+
+	// Поля для проверки отказов: подмена nonce и получателя в ID токене
+	nonceOverride    string
+	audienceOverride string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	idp := &mockIdP{key: testIdPKey(t), grants: make(map[string]idpGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys.JWKS{Keys: []keys.JWK{{
+			Kty: "RSA",
+			Kid: "idp-key",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// signIn выполняет вход пользователя у провайдера по адресу авторизации и возвращает
+// code и state, с которыми браузер вернется на страницу фронтенда
+func (idp *mockIdP) signIn(t *testing.T, authURL string, account idpAccount) (string, string) {
+	t.Helper()
+	target, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := target.Query()
+
+	require.Equal(t, idp.server.URL+"/authorize", target.Scheme+"://"+target.Host+target.Path)
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, testSSOClientID, query.Get("client_id"))
+	require.Equal(t, testSSORedirectURL, query.Get("redirect_uri"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Contains(t, query.Get("scope"), "openid")
+
+	code := uuid.NewString()
+	idp.mu.Lock()
+	idp.grants[code] = idpGrant{account: account, nonce: query.Get("nonce"), codeChallenge: query.Get("code_challenge")}
+	idp.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+// token обменивает код на ID токен, подписанный RS256
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != testSSOClientID || secret != testSSOSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	idp.mu.Lock()
+	grant, found := idp.grants[r.PostFormValue("code")]
+	delete(idp.grants, r.PostFormValue("code"))
+	idp.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !found || r.PostFormValue("redirect_uri") != testSSORedirectURL || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	nonce, audience := grant.nonce, testSSOClientID
+	if idp.nonceOverride != "" {
+		nonce = idp.nonceOverride
+	}
+	if idp.audienceOverride != "" {
+		audience = idp.audienceOverride
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            grant.account.subject,
+		"aud":            audience,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          nonce,
+		"email":          grant.account.email,
+		"email_verified": grant.account.emailVerified,
+	})
+	token.Header["kid"] = "idp-key"
+	idToken, _ := token.SignedString(idp.key)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+// memorySSOStateRepository хранит состояния входа в памяти и выдает каждое один раз
+type memorySSOStateRepository struct {
+	mu     sync.Mutex
+	states map[string]*models.SSOState
+}
+
+func (r *memorySSOStateRepository) Create(ctx context.Context, state *models.SSOState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *memorySSOStateRepository) Consume(ctx context.Context, stateHash string) (*models.SSOState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.states[stateHash]
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+// MockUserIdentityRepository для тестирования
+type MockUserIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserIdentityRepository) Get(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) Save(ctx context.Context, identity *models.UserIdentity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockUserIdentityRepository) GetByUser(ctx context.Context, provider string, userID uuid.UUID) (*models.UserIdentity, error) {
+	args := m.Called(ctx, provider, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserIdentity), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) DeleteByUser(ctx context.Context, provider string, userID uuid.UUID) error {
+	args := m.Called(ctx, provider, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.UserIdentity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserIdentity), args.Error(1)
+}
+
+// ssoTestEnv сервис входа через локальный провайдер с зависимостями для тестов
+type ssoTestEnv struct {
+	service      services.SSOService
+	idp          *mockIdP
+	userRepo     *MockUserRepository
+	identityRepo *MockUserIdentityRepository
+}
+
+func newSSOTestEnv(t *testing.T, autoProvision bool) *ssoTestEnv {
+	return newSSOTestEnvWithAuth(t, autoProvision, config.AuthConfig{RegistrationMode: config.RegistrationOpen})
+}
+
+// newSSOTestEnvWithAuth создает окружение с заданными правилами регистрации
+func newSSOTestEnvWithAuth(t *testing.T, autoProvision bool, authConfig config.AuthConfig) *ssoTestEnv {
+	messages := ru.NewRussianMessages()
+	idp := newMockIdP(t)
+
+	provider := sso.NewOIDCProvider(config.SSOProviderConfig{
+		Name:         "corporate",
+		Issuer:       idp.server.URL,
+		ClientID:     testSSOClientID,
+		ClientSecret: testSSOSecret,
+		RedirectURL:  testSSORedirectURL,
+		Scopes:       []string{"openid", "email"},
+	}, idp.server.Client(), messages)
+
+	userRepo := new(MockUserRepository)
+	identityRepo := new(MockUserIdentityRepository)
+	refreshRepo := new(MockRefreshTokenRepository)
+	refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil).Maybe()
+	authService := newTestAuthService(userRepo, refreshRepo, new(MockRevocationStore))
+
+	stateRepo := &memorySSOStateRepository{states: make(map[string]*models.SSOState)}
+	ssoConfig := config.SSOConfig{AutoProvision: autoProvision, StateTTL: 10 * time.Minute}
+
+	return &ssoTestEnv{
+		service:      services.NewSSOService([]sso.Provider{provider}, stateRepo, identityRepo, userRepo, authService, authConfig, ssoConfig, messages),
+		idp:          idp,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+	}
+}
+
+// signIn проходит вход у провайдера и возвращает запрос, который фронтенд отправит на callback
+func (env *ssoTestEnv) signIn(t *testing.T, account idpAccount) *requests.SSOCallbackRequest {
+	t.Helper()
+	authURL, browserState, err := env.service.Begin(context.Background(), "corporate")
+	require.NoError(t, err)
+
+	code, state := env.idp.signIn(t, authURL, account)
+	return &requests.SSOCallbackRequest{Code: code, State: state, BrowserState: browserState}
+}
+
+func TestSSOService_Complete_LinksExistingUserByEmail(t *testing.T) {
+	// Подготовка
+	env := newSSOTestEnv(t, false)
+	user := &models.User{ID: uuid.New(), Email: "employee@corp.example.com", Role: models.RoleEmployee, Status: models.StatusActive}
+	account := idpAccount{subject: "idp-subject-1", email: user.Email, emailVerified: true}
+
+	// Настройка моков
+	var saved *models.UserIdentity
+	env.identityRepo.On("Get", mock.Anything, "corporate", account.subject).Return(nil, nil)
+	env.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	env.userRepo.On("MarkEmailVerified", mock.Anything, user.ID).Return(nil)
+	env.identityRepo.On("Save", mock.Anything, mock.AnythingOfType("*models.UserIdentity")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*models.UserIdentity)
+	}).Return(nil)
+
+	// Выполнение
+	response, err := env.service.Complete(context.Background(), "corporate", env.signIn(t, account), testClient)
+
+	// Проверка - выданы обычные токены сервиса, учетная запись провайдера привязана по sub
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	assert.NotEmpty(t, response.RefreshToken)
+	assert.Equal(t, user.ID, response.User.ID)
+	assert.True(t, response.User.IsEmailVerified())
+	require.NotNil(t, saved)
+	assert.Equal(t, user.ID, saved.UserID)
+	assert.Equal(t, account.subject, saved.Subject)
+
+	env.userRepo.AssertExpectations(t)
+	env.identityRepo.AssertExpectations(t)
+}
+
+func TestSSOService_Complete_LinkedIdentityIgnoresEmail(t *testing.T) {
+	// Подготовка
+	env := newSSOTestEnv(t, false)
+	user := &models.User{ID: uuid.New(), Email: "old@corp.example.com", Role: models.RoleManager, Status: models.StatusActive}
+	account := idpAccount{subject: "idp-subject-2", email: "renamed@corp.example.com"}
+
+	// Настройка моков - email сменился у провайдера и не подтвержден, но sub уже привязан
+	env.identityRepo.On("Get", mock.Anything, "corporate", account.subject).
+		Return(&models.UserIdentity{Provider: "corporate", Subject: account.subject, UserID: user.ID}, nil)
+	env.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	env.identityRepo.On("Save", mock.Anything, mock.AnythingOfType("*models.UserIdentity")).Return(nil)
+
+	// Выполнение
+	response, err := env.service.Complete(context.Background(), "corporate", env.signIn(t, account), testClient)
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, response.User.ID)
+	env.userRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestSSOService_Complete_ProvisionsEmployee(t *testing.T) {
+	// Подготовка
+	env := newSSOTestEnv(t, true)
+	account := idpAccount{subject: "idp-subject-3", email: "newcomer@corp.example.com", emailVerified: true}
+
+	// Настройка моков
+	var created *models.User
+	env.identityRepo.On("Get", mock.Anything, "corporate", account.subject).Return(nil, nil)
+	env.userRepo.On("GetByEmail", mock.Anything, account.email).Return(nil, nil)
+	env.userRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.User)
+	}).Return(nil)
+	env.identityRepo.On("Save", mock.Anything, mock.AnythingOfType("*models.UserIdentity")).Return(nil)
+
+	// Выполнение
+	response, err := env.service.Complete(context.Background(), "corporate", env.signIn(t, account), testClient)
+
+	// Проверка - сотрудник без локального пароля с подтвержденным email
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, models.RoleEmployee, created.Role)
+	assert.Empty(t, created.Password)
+	assert.True(t, created.IsEmailVerified())
+	assert.Equal(t, created.ID, response.User.ID)
+}
+
+func TestSSOService_Complete_ProvisioningFollowsRegistrationRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		authConfig config.AuthConfig
+		wantErr    string
+	}{
+		{"Регистрация только по приглашению", config.AuthConfig{RegistrationMode: config.RegistrationInviteOnly}, "только по приглашению"},
+		{"Домен не из списка разрешенных", config.AuthConfig{RegistrationMode: config.RegistrationDomain, RegistrationAllowedDomains: []string{"example.com"}}, "корпоративного email"},
+		{"Запрещенный домен", config.AuthConfig{RegistrationMode: config.RegistrationOpen, RegistrationDeniedDomains: []string{"corp.example.com"}}, "запрещена"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			env := newSSOTestEnvWithAuth(t, true, tt.authConfig)
+			account := idpAccount{subject: "idp-subject-7", email: "outsider@corp.example.com", emailVerified: true}
+
+			// Настройка моков
+			env.identityRepo.On("Get", mock.Anything, "corporate", account.subject).Return(nil, nil)
+			env.userRepo.On("GetByEmail", mock.Anything, account.email).Return(nil, nil)
+
+			// Выполнение
+			response, err := env.service.Complete(context.Background(), "corporate", env.signIn(t, account), testClient)
+
+			// Проверка - учетная запись не создается и не привязывается
+			require.Error(t, err)
+			assert.Nil(t, response)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			env.userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			env.identityRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSSOService_Complete_Rejected(t *testing.T) {
+	verified := idpAccount{subject: "idp-subject-4", email: "employee@corp.example.com", emailVerified: true}
+
+	tests := []struct {
+		name    string
+		prepare func(env *ssoTestEnv, req *requests.SSOCallbackRequest)
+		account idpAccount
+		wantErr string
+	}{
+		{"Неизвестный state", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			req.State = "forged-state"
+		}, verified, "начните вход заново"},
+		{"Вход начат в другом браузере", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			req.BrowserState = ""
+		}, verified, "начните вход заново"},
+		{"Подмененный nonce", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			env.idp.nonceOverride = "replayed-nonce"
+		}, verified, "Не удалось войти"},
+		{"ID токен другому получателю", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			env.idp.audienceOverride = "another-service"
+		}, verified, "Не удалось войти"},
+		{"Неизвестный код", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			req.Code = "forged-code"
+		}, verified, "Не удалось войти"},
+		{"Email не подтвержден провайдером", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			env.identityRepo.On("Get", mock.Anything, "corporate", "idp-subject-5").Return(nil, nil)
+		}, idpAccount{subject: "idp-subject-5", email: "employee@corp.example.com"}, "не подтвердил email"},
+		{"Нет учетной записи без автоматического создания", func(env *ssoTestEnv, req *requests.SSOCallbackRequest) {
+			env.identityRepo.On("Get", mock.Anything, "corporate", verified.subject).Return(nil, nil)
+			env.userRepo.On("GetByEmail", mock.Anything, verified.email).Return(nil, nil)
+		}, verified, "обратитесь к администратору"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			env := newSSOTestEnv(t, false)
+			req := env.signIn(t, tt.account)
+			tt.prepare(env, req)
+
+			// Выполнение
+			response, err := env.service.Complete(context.Background(), "corporate", req, testClient)
+
+			// Проверка
+			require.Error(t, err)
+			assert.Nil(t, response)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			env.identityRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSSOService_Complete_StateIsSingleUse(t *testing.T) {
+	// Подготовка
+	env := newSSOTestEnv(t, false)
+	user := &models.User{ID: uuid.New(), Email: "employee@corp.example.com", Role: models.RoleEmployee, Status: models.StatusActive}
+	account := idpAccount{subject: "idp-subject-6", email: user.Email, emailVerified: true}
+
+	// Настройка моков
+	env.identityRepo.On("Get", mock.Anything, "corporate", account.subject).
+		Return(&models.UserIdentity{Provider: "corporate", Subject: account.subject, UserID: user.ID}, nil)
+	env.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	env.identityRepo.On("Save", mock.Anything, mock.AnythingOfType("*models.UserIdentity")).Return(nil)
+	req := env.signIn(t, account)
+
+	// Выполнение
+	_, firstErr := env.service.Complete(context.Background(), "corporate", req, testClient)
+	_, replayErr := env.service.Complete(context.Background(), "corporate", req, testClient)
+
+	// Проверка
+	require.NoError(t, firstErr)
+	require.Error(t, replayErr)
+	assert.Contains(t, replayErr.Error(), "начните вход заново")
+}
+
+func TestSSOService_Begin_UnknownProvider(t *testing.T) {
+	// Подготовка
+	env := newSSOTestEnv(t, false)
+
+	// Выполнение
+	authURL, browserState, err := env.service.Begin(context.Background(), "github")
+
+	// Проверка
+	require.Error(t, err)
+	assert.Empty(t, authURL)
+	assert.Empty(t, browserState)
+	assert.Equal(t, []string{"corporate"}, env.service.Providers().Providers)
+}
@@ -0,0 +1,379 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
+	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/repositories"
+	"github.com/avangero/auth-service/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRoleChangeRepository для тестирования
+type MockRoleChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleChangeRepository) Create(ctx context.Context, change *models.RoleChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockRoleChangeRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RoleChange, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RoleChange), args.Error(1)
+}
+
+// userManagementMocks зависимости UserManagementService для тестов
+type userManagementMocks struct {
+	userRepo        *MockUserRepository
+	roleChangeRepo  *MockRoleChangeRepository
+	mfaRepo         *MockMFARepository
+	refreshRepo     *MockRefreshTokenRepository
+	revocations     *MockRevocationStore
+	passwordService *MockPasswordService
+	loginAttempts   repositories.LoginAttemptRepository
+}
+
+// newTestUserManagementService создает UserManagementService с тестовыми зависимостями
+func newTestUserManagementService() (services.UserManagementService, *userManagementMocks) {
+	m := &userManagementMocks{
+		userRepo:        new(MockUserRepository),
+		roleChangeRepo:  new(MockRoleChangeRepository),
+		mfaRepo:         new(MockMFARepository),
+		refreshRepo:     new(MockRefreshTokenRepository),
+		revocations:     new(MockRevocationStore),
+		passwordService: new(MockPasswordService),
+	}
+	loginGuard, loginAttempts := newTestLoginGuardWithRepo()
+	m.loginAttempts = loginAttempts
+
+	service := services.NewUserManagementService(
+		m.userRepo,
+		m.roleChangeRepo,
+		m.mfaRepo,
+		newTestTokenService(m.refreshRepo, m.revocations),
+		m.passwordService,
+		loginGuard,
+		ru.NewRussianMessages(),
+	)
+	return service, m
+}
+
+func (m *userManagementMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.roleChangeRepo.AssertExpectations(t)
+	m.mfaRepo.AssertExpectations(t)
+	m.refreshRepo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+	m.passwordService.AssertExpectations(t)
+}
+
+func TestUserManagementService_ChangeRole_Success(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee}
+
+	var recorded *models.RoleChange
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.userRepo.On("UpdateRole", mock.Anything, user.ID, models.RoleManager).Return(nil)
+	m.roleChangeRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RoleChange")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.RoleChange) }).
+		Return(nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+
+	// Выполнение
+	updated, err := service.ChangeRole(context.Background(), admin, user.ID, &requests.ChangeRoleRequest{Role: models.RoleManager})
+
+	// Проверка - изменение записано в журнал, токены со старой ролью отозваны
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleManager, updated.Role)
+	require.NotNil(t, recorded)
+	assert.Equal(t, models.RoleEmployee, *recorded.OldRole)
+	assert.Equal(t, models.RoleManager, recorded.NewRole)
+	assert.Equal(t, admin.ID, *recorded.ChangedBy)
+	assert.Equal(t, models.RoleChangeSourceAdmin, recorded.Source)
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ChangeRole_SameRole(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+	// Выполнение
+	updated, err := service.ChangeRole(context.Background(), admin, user.ID, &requests.ChangeRoleRequest{Role: models.RoleManager})
+
+	// Проверка - без изменения нет записи в журнале и отзыва токенов
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleManager, updated.Role)
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ChangeRole_Rejected(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	missingID := uuid.New()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		setup   func(m *userManagementMocks)
+		wantErr string
+	}{
+		{"Own role", admin.ID, func(m *userManagementMocks) {}, "собственную роль"},
+		{"Unknown user", missingID, func(m *userManagementMocks) {
+			m.userRepo.On("GetByID", mock.Anything, missingID).Return(nil, nil)
+		}, "не найден"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestUserManagementService()
+			tt.setup(m)
+
+			// Выполнение
+			updated, err := service.ChangeRole(context.Background(), admin, tt.userID, &requests.ChangeRoleRequest{Role: models.RoleEmployee})
+
+			// Проверка
+			require.Error(t, err)
+			assert.Nil(t, updated)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserManagementService_ListUsers_BuildsFilter(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	users := []models.User{{ID: uuid.New(), Email: "manager@example.com", Role: models.RoleManager}}
+
+	var filter models.UserFilter
+
+	// Настройка моков
+	m.userRepo.On("List", mock.Anything, mock.AnythingOfType("models.UserFilter")).
+		Run(func(args mock.Arguments) { filter = args.Get(1).(models.UserFilter) }).
+		Return(users, 41, nil)
+
+	// Выполнение
+	list, err := service.ListUsers(context.Background(), &requests.ListUsersRequest{
+		Page:        3,
+		PerPage:     20,
+		Role:        models.RoleManager,
+		Email:       " manager ",
+		CreatedFrom: "2026-01-01",
+		CreatedTo:   "2026-01-31",
+	})
+
+	// Проверка - дата окончания включительно, смещение считается по номеру страницы
+	require.NoError(t, err)
+	assert.Equal(t, users, list.Users)
+	assert.Equal(t, 41, list.Total)
+	assert.Equal(t, 3, list.Page)
+	assert.Equal(t, 20, list.PerPage)
+
+	assert.Equal(t, models.RoleManager, filter.Role)
+	assert.Equal(t, "manager", filter.Email)
+	assert.Equal(t, 20, filter.Limit)
+	assert.Equal(t, 40, filter.Offset)
+	require.NotNil(t, filter.CreatedFrom)
+	require.NotNil(t, filter.CreatedTo)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), *filter.CreatedFrom)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), *filter.CreatedTo)
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ListUsers_Defaults(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+
+	// Настройка моков
+	m.userRepo.On("List", mock.Anything, models.UserFilter{Limit: 20, Offset: 0}).Return([]models.User{}, 0, nil)
+
+	// Выполнение
+	list, err := service.ListUsers(context.Background(), &requests.ListUsersRequest{})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.Page)
+	assert.Equal(t, 20, list.PerPage)
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ChangeStatus_SuspendRevokesSessions(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee, Status: models.StatusActive}
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.userRepo.On("UpdateStatus", mock.Anything, user.ID, models.StatusSuspended, mock.MatchedBy(func(reason *string) bool {
+		return reason != nil && *reason == "Служебная проверка"
+	})).Return(nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+
+	// Выполнение
+	updated, err := service.ChangeStatus(context.Background(), admin, user.ID, &requests.ChangeStatusRequest{
+		Status: models.StatusSuspended,
+		Reason: " Служебная проверка ",
+	})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuspended, updated.Status)
+	require.NotNil(t, updated.StatusReason)
+	assert.Equal(t, "Служебная проверка", *updated.StatusReason)
+	assert.NotNil(t, updated.StatusChangedAt)
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ChangeStatus_ActivateKeepsSessions(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee, Status: models.StatusSuspended}
+
+	// Настройка моков - токены не отзываются, причина сбрасывается
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.userRepo.On("UpdateStatus", mock.Anything, user.ID, models.StatusActive, (*string)(nil)).Return(nil)
+
+	// Выполнение
+	updated, err := service.ChangeStatus(context.Background(), admin, user.ID, &requests.ChangeStatusRequest{Status: models.StatusActive})
+
+	// Проверка
+	require.NoError(t, err)
+	assert.True(t, updated.IsActive())
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ChangeStatus_ReasonRequired(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+
+	// Выполнение
+	updated, err := service.ChangeStatus(context.Background(), admin, uuid.New(), &requests.ChangeStatusRequest{
+		Status: models.StatusDeactivated,
+		Reason: "   ",
+	})
+
+	// Проверка
+	require.Error(t, err)
+	assert.Nil(t, updated)
+	assert.Contains(t, err.Error(), "Укажите причину")
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_OwnAccountForbidden(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+
+	tests := []struct {
+		name   string
+		action func(service services.UserManagementService) error
+	}{
+		{"Status", func(service services.UserManagementService) error {
+			_, err := service.ChangeStatus(context.Background(), admin, admin.ID, &requests.ChangeStatusRequest{Status: models.StatusActive})
+			return err
+		}},
+		{"Delete", func(service services.UserManagementService) error {
+			return service.DeleteUser(context.Background(), admin, admin.ID)
+		}},
+		{"Reset", func(service services.UserManagementService) error {
+			return service.ResetUser(context.Background(), admin, admin.ID, &requests.ResetUserRequest{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			service, m := newTestUserManagementService()
+
+			// Выполнение
+			err := tt.action(service)
+
+			// Проверка - до обращения к репозиториям
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "собственной учетной записью")
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestUserManagementService_DeleteUser_Success(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee}
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.refreshRepo.On("RevokeByUser", mock.Anything, user.ID).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, user.ID).Return(nil)
+	m.userRepo.On("Delete", mock.Anything, user.ID).Return(nil)
+
+	// Выполнение
+	err := service.DeleteUser(context.Background(), admin, user.ID)
+
+	// Проверка
+	require.NoError(t, err)
+
+	m.assertExpectations(t)
+}
+
+func TestUserManagementService_ResetUser_ClearsLockoutAndMFA(t *testing.T) {
+	// Подготовка
+	service, m := newTestUserManagementService()
+	ctx := context.Background()
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: models.RoleEmployee}
+
+	_, err := m.loginAttempts.RegisterFailure(ctx, "email:"+user.Email, time.Now(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, m.loginAttempts.Lock(ctx, "email:"+user.Email, time.Now().Add(time.Hour)))
+
+	// Настройка моков
+	m.userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	m.mfaRepo.On("Delete", mock.Anything, user.ID).Return(nil)
+	m.passwordService.On("ForceReset", mock.Anything, user).Return(nil)
+
+	// Выполнение
+	err = service.ResetUser(ctx, admin, user.ID, &requests.ResetUserRequest{ResetMFA: true})
+
+	// Проверка
+	require.NoError(t, err)
+	attempt, err := m.loginAttempts.Get(ctx, "email:"+user.Email)
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+
+	m.assertExpectations(t)
+}
@@ -0,0 +1,86 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/avangero/auth-service/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret секрет из тестовых векторов RFC 6238 ("12345678901234567890")
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// Тестовые векторы RFC 6238 для SHA1, последние 6 цифр
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+
+	for _, tc := range testCases {
+		// Выполнение
+		code, err := totp.Code(rfcSecret, time.Unix(tc.unix, 0))
+
+		// Проверка
+		require.NoError(t, err)
+		assert.Equal(t, tc.code, code)
+	}
+}
+
+func TestValidate_AllowsClockSkew(t *testing.T) {
+	// Подготовка
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	previous, err := totp.Code(secret, now.Add(-totp.Period))
+	require.NoError(t, err)
+	stale, err := totp.Code(secret, now.Add(-3*totp.Period))
+	require.NoError(t, err)
+
+	// Выполнение
+	step, ok := totp.Validate(secret, previous, now)
+	_, staleOK := totp.Validate(secret, stale, now)
+
+	// Проверка
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+	assert.False(t, staleOK)
+}
+
+func TestValidate_RejectsMalformedCode(t *testing.T) {
+	// Подготовка
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	// Выполнение и проверка
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		_, ok := totp.Validate(secret, code, time.Now())
+		assert.False(t, ok, code)
+	}
+}
+
+func TestURI(t *testing.T) {
+	// Выполнение
+	uri := totp.URI("Learning Portal", "manager@example.com", "JBSWY3DPEHPK3PXP")
+
+	// Проверка
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Learning Portal:manager@example.com", parsed.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Learning Portal", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
@@ -1,10 +1,14 @@
 package validators_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/avangero/auth-service/internal/config"
 	"github.com/avangero/auth-service/internal/lang/ru"
+	"github.com/avangero/auth-service/internal/models"
 	"github.com/avangero/auth-service/internal/models/requests"
+	"github.com/avangero/auth-service/internal/passwords"
 	"github.com/avangero/auth-service/internal/validators"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,7 +22,6 @@ func TestAuthValidator_Validate_RegisterRequest_Success(t *testing.T) {
 	req := &requests.RegisterRequest{
 		Email:    "test@example.com",
 		Password: "password123",
-		Role:     "employee",
 	}
 
 	// Выполнение
@@ -36,7 +39,6 @@ func TestAuthValidator_Validate_RegisterRequest_InvalidEmail(t *testing.T) {
 	req := &requests.RegisterRequest{
 		Email:    "invalid-email",
 		Password: "password123",
-		Role:     "employee",
 	}
 
 	// Выполнение
@@ -55,7 +57,6 @@ func TestAuthValidator_Validate_RegisterRequest_ShortPassword(t *testing.T) {
 	req := &requests.RegisterRequest{
 		Email:    "test@example.com",
 		Password: "123", // слишком короткий
-		Role:     "employee",
 	}
 
 	// Выполнение
@@ -66,15 +67,50 @@ func TestAuthValidator_Validate_RegisterRequest_ShortPassword(t *testing.T) {
 	assert.Contains(t, err.Error(), "минимум")
 }
 
-func TestAuthValidator_Validate_RegisterRequest_InvalidRole(t *testing.T) {
+func TestAuthValidator_Validate_RegisterRequest_EmailDomainPolicy(t *testing.T) {
+	policy := models.EmailDomainPolicy{
+		Allowed: []string{"corp.example"},
+		Denied:  []string{"mailinator.com"},
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{"Allowed domain", "user@corp.example", false},
+		{"Allowed domain in upper case", "user@CORP.EXAMPLE", false},
+		{"Foreign domain", "user@gmail.com", true},
+		{"Denied domain", "user@mailinator.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			validator := validators.NewAuthValidator(ru.NewRussianMessages(), validators.WithEmailDomainPolicy(policy))
+			req := &requests.RegisterRequest{Email: tt.email, Password: "password123"}
+
+			// Выполнение
+			err := validator.Validate(req)
+
+			// Проверка
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "домена email недоступна")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthValidator_Validate_ChangeRoleRequest_InvalidRole(t *testing.T) {
 	// Подготовка
 	messages := ru.NewRussianMessages()
 	validator := validators.NewAuthValidator(messages)
 
-	req := &requests.RegisterRequest{
-		Email:    "test@example.com",
-		Password: "password123",
-		Role:     "admin", // недопустимая роль
+	req := &requests.ChangeRoleRequest{
+		Role: "superuser", // недопустимая роль
 	}
 
 	// Выполнение
@@ -140,3 +176,161 @@ func TestAuthValidator_Validate_LoginRequest_MissingFields(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthValidator_Validate_ChangePasswordRequest_SamePassword(t *testing.T) {
+	// Подготовка
+	messages := ru.NewRussianMessages()
+	validator := validators.NewAuthValidator(messages)
+
+	req := &requests.ChangePasswordRequest{
+		CurrentPassword: "password123",
+		NewPassword:     "password123",
+	}
+
+	// Выполнение
+	err := validator.Validate(req)
+
+	// Проверка
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "должен отличаться от текущего")
+}
+
+func TestAuthValidator_Validate_PasswordPolicy(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	policy := passwords.NewPolicy(config.PasswordPolicyConfig{
+		MinLength:       10,
+		MaxBytes:        72,
+		RequiredClasses: []string{config.PasswordClassDigit, config.PasswordClassSymbol},
+	}, nil, messages)
+
+	tests := []struct {
+		name    string
+		request interface{}
+		want    []string
+	}{
+		{
+			name:    "Register reports every rule",
+			request: &requests.RegisterRequest{Email: "test@example.com", Password: "short"},
+			want:    []string{"мин. 10 символов", "должен содержать цифру", "отличный от буквы и цифры"},
+		},
+		{
+			name:    "Register rejects email as password",
+			request: &requests.RegisterRequest{Email: "test-1@example.com", Password: "test-1@example.com"},
+			want:    []string{"не должен совпадать с email"},
+		},
+		{
+			name:    "Change password uses owner email",
+			request: &requests.ChangePasswordRequest{CurrentPassword: "old", NewPassword: "Test-1@Example.com", Email: "test-1@example.com"},
+			want:    []string{"не должен совпадать с email"},
+		},
+		{
+			name:    "Reset password",
+			request: &requests.ResetPasswordRequest{Token: "reset-token", Password: "no-digits-here"},
+			want:    []string{"должен содержать цифру"},
+		},
+		{
+			name:    "Accept invitation",
+			request: &requests.AcceptInvitationRequest{Token: "invite-token", Password: "1234567890" + strings.Repeat("!", 63)},
+			want:    []string{"макс. 72 байт"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Подготовка
+			validator := validators.NewAuthValidator(messages, validators.WithPasswordPolicy(policy))
+
+			// Выполнение
+			err := validator.Validate(tt.request)
+
+			// Проверка
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestAuthValidator_Validate_PasswordPolicy_Success(t *testing.T) {
+	// Подготовка
+	messages := ru.NewRussianMessages()
+	validator := validators.NewAuthValidator(messages)
+
+	req := &requests.ChangePasswordRequest{
+		CurrentPassword: "password123",
+		NewPassword:     "Correct-Horse-7",
+		Email:           "test@example.com",
+	}
+
+	// Выполнение
+	err := validator.Validate(req)
+
+	// Проверка
+	assert.NoError(t, err)
+}
+
+func TestAuthValidator_Validate_CreateClientRequest(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	validator := validators.NewAuthValidator(messages)
+
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{"Known scopes", []string{string(models.PermissionTokensValidate), string(models.PermissionCoursesRead)}, false},
+		{"Admin scope", []string{string(models.PermissionUsersManage)}, true},
+		{"No scopes", []string{}, true},
+		{"Missing scopes", nil, true},
+		{"Unknown scope", []string{"tokens:validate", "root"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Выполнение
+			err := validator.Validate(&requests.CreateClientRequest{Name: "user-service", Scopes: tt.scopes})
+
+			// Проверка
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "Scopes")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthValidator_Validate_CreateClientRequest_RedirectURIs(t *testing.T) {
+	messages := ru.NewRussianMessages()
+	validator := validators.NewAuthValidator(messages)
+
+	tests := []struct {
+		name         string
+		scopes       []string
+		redirectURIs []string
+		wantErr      bool
+	}{
+		{"OIDC client without scopes", nil, []string{"https://crm.example.com/callback"}, false},
+		{"Localhost callback", nil, []string{"http://localhost:8080/callback"}, false},
+		{"Relative URI", nil, []string{"/callback"}, true},
+		{"Fragment", nil, []string{"https://crm.example.com/callback#token"}, true},
+		{"Whitespace", nil, []string{"https://crm.example.com/a b"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Выполнение
+			err := validator.Validate(&requests.CreateClientRequest{Name: "crm", Scopes: tt.scopes, RedirectURIs: tt.redirectURIs})
+
+			// Проверка
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "RedirectURIs")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}